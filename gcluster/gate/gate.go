@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/glocate"
 	"github.com/goodluck0107/gcore/glog"
 	"github.com/goodluck0107/gcore/gmodules"
 	"github.com/goodluck0107/gcore/gnetwork"
@@ -82,6 +83,8 @@ func (g *Gate) Start() {
 
 	g.registerServiceInstance()
 
+	g.leaseLocation()
+
 	g.proxy.watch()
 
 	g.printInfo()
@@ -108,6 +111,8 @@ func (g *Gate) Destroy() {
 
 	g.deregisterServiceInstance()
 
+	g.revokeLocation()
+
 	g.stopNetworkServer()
 
 	g.stopLinkerServer()
@@ -115,6 +120,11 @@ func (g *Gate) Destroy() {
 	g.cancel()
 }
 
+// Node 获取网关已同步的节点实例；网关尚未感知该节点时返回false
+func (g *Gate) Node(nid string) (*gregistry.ServiceInstance, bool) {
+	return g.proxy.nodeLinker.Instance(nid)
+}
+
 // 启动网络服务器
 func (g *Gate) startNetworkServer() {
 	g.opts.server.OnConnect(g.handleConnect)
@@ -205,6 +215,7 @@ func (g *Gate) registerServiceInstance() {
 		State:    g.getState().String(),
 		Weight:   g.opts.weight,
 		Endpoint: g.linker.Endpoint().String(),
+		Metadata: g.opts.metadata,
 	}
 
 	ctx, cancel := context.WithTimeout(g.ctx, defaultTimeout)
@@ -241,6 +252,36 @@ func (g *Gate) deregisterServiceInstance() {
 	}
 }
 
+// 租用用户定位实例
+func (g *Gate) leaseLocation() {
+	leaser, ok := g.opts.locator.(glocate.Leaser)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(g.ctx, defaultTimeout)
+	defer cancel()
+
+	if err := leaser.Lease(ctx, gcluster.Gate.String(), g.opts.id); err != nil {
+		glog.Fatalf("lease location instance failed: %v", err)
+	}
+}
+
+// 撤销用户定位实例租约
+func (g *Gate) revokeLocation() {
+	leaser, ok := g.opts.locator.(glocate.Leaser)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(g.ctx, defaultTimeout)
+	defer cancel()
+
+	if err := leaser.Revoke(ctx, gcluster.Gate.String(), g.opts.id); err != nil {
+		glog.Errorf("revoke location instance failed: %v", err)
+	}
+}
+
 // 获取状态
 func (g *Gate) getState() gcluster.State {
 	return gcluster.State(g.state.Load())
@@ -4,11 +4,11 @@ import (
 	"context"
 	"github.com/goodluck0107/gcore/getc"
 	"github.com/goodluck0107/gcore/glocate"
-	"github.com/goodluck0107/gcore/gutils/guuid"
-	"time"
-
 	"github.com/goodluck0107/gcore/gnetwork"
 	"github.com/goodluck0107/gcore/gregistry"
+	"github.com/goodluck0107/gcore/gutils/gconv"
+	"github.com/goodluck0107/gcore/gutils/guuid"
+	"time"
 )
 
 const (
@@ -19,11 +19,12 @@ const (
 )
 
 const (
-	defaultIDKey      = "etc.cluster.gate.id"
-	defaultNameKey    = "etc.cluster.gate.name"
-	defaultAddrKey    = "etc.cluster.gate.addr"
-	defaultTimeoutKey = "etc.cluster.gate.timeout"
-	defaultWeightKey  = "etc.cluster.gate.weight"
+	defaultIDKey       = "etc.cluster.gate.id"
+	defaultNameKey     = "etc.cluster.gate.name"
+	defaultAddrKey     = "etc.cluster.gate.addr"
+	defaultTimeoutKey  = "etc.cluster.gate.timeout"
+	defaultWeightKey   = "etc.cluster.gate.weight"
+	defaultMetadataKey = "etc.cluster.gate.metadata"
 )
 
 type Option func(o *options)
@@ -35,6 +36,7 @@ type options struct {
 	addr     string             // 监听地址
 	timeout  time.Duration      // RPC调用超时时间
 	weight   int                // 权重
+	metadata map[string]string  // 元数据
 	server   gnetwork.Server    // 网关服务器
 	locator  glocate.Locator    // 用户定位器
 	registry gregistry.Registry // 服务注册器
@@ -71,6 +73,13 @@ func defaultOptions() *options {
 		opts.weight = weight
 	}
 
+	if metadata := getc.Get(defaultMetadataKey).Map(); len(metadata) > 0 {
+		opts.metadata = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			opts.metadata[k] = gconv.String(v)
+		}
+	}
+
 	return opts
 }
 
@@ -84,6 +93,11 @@ func WithName(name string) Option {
 	return func(o *options) { o.name = name }
 }
 
+// WithAddr 设置连接器监听地址
+func WithAddr(addr string) Option {
+	return func(o *options) { o.addr = addr }
+}
+
 // WithContext 设置上下文
 func WithContext(ctx context.Context) Option {
 	return func(o *options) { o.ctx = ctx }
@@ -113,3 +127,8 @@ func WithRegistry(r gregistry.Registry) Option {
 func WithWeight(weight int) Option {
 	return func(o *options) { o.weight = weight }
 }
+
+// WithMetadata 设置元数据
+func WithMetadata(metadata map[string]string) Option {
+	return func(o *options) { o.metadata = metadata }
+}
@@ -0,0 +1,191 @@
+package harness
+
+import (
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/gcluster/client"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gnetwork/tcp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrExpectTimeout     = gerrors.New("expect message timeout")
+	ErrUnexpectedMessage = gerrors.New("unexpected message")
+)
+
+// Bot 脚本化测试机器人；收到的消息按到达顺序缓存，供 Expect 系列方法断言
+type Bot struct {
+	cluster  *Cluster
+	client   *client.Client
+	conn     *client.Conn
+	seq      atomic.Int32
+	mu       sync.Mutex
+	messages []*client.Context
+	notify   chan struct{}
+	closed   atomic.Bool
+}
+
+// NewBot 新建机器人并连接到集群网关
+func (c *Cluster) NewBot() (*Bot, error) {
+	b := &Bot{cluster: c, notify: make(chan struct{}, 1)}
+	b.client = client.NewClient(
+		client.WithContext(c.ctx),
+		client.WithCodec(c.opts.codec),
+		client.WithClient(tcp.NewClient(tcp.WithClientDialAddr(c.addr))),
+	)
+	b.client.Proxy().SetDefaultRouteHandler(b.receive)
+	b.client.Init()
+	b.client.Start()
+
+	conn, err := b.client.Proxy().Dial()
+	if err != nil {
+		b.client.Destroy()
+		return nil, err
+	}
+
+	b.conn = conn
+
+	c.mu.Lock()
+	c.bots = append(c.bots, b)
+	c.mu.Unlock()
+
+	return b, nil
+}
+
+// Conn 获取连接
+func (b *Bot) Conn() *client.Conn {
+	return b.conn
+}
+
+// Send 发送消息；返回本次消息的序列号
+func (b *Bot) Send(route int32, data interface{}) (int32, error) {
+	seq := b.seq.Add(1)
+
+	return seq, b.conn.Push(&gcluster.Message{
+		Seq:   seq,
+		Route: route,
+		Data:  data,
+	})
+}
+
+// Request 发送消息并等待相同路由与序列号的响应，响应数据解析到 reply 中
+func (b *Bot) Request(route int32, data interface{}, reply interface{}, timeout ...time.Duration) error {
+	seq, err := b.Send(route, data)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := b.expect(func(ctx *client.Context) bool {
+		return ctx.Route() == route && ctx.Seq() == seq
+	}, timeout...)
+	if err != nil {
+		return err
+	}
+
+	if reply == nil {
+		return nil
+	}
+
+	return ctx.Parse(reply)
+}
+
+// Expect 等待指定路由的消息；其他路由的消息保留在缓存中
+func (b *Bot) Expect(route int32, timeout ...time.Duration) (*client.Context, error) {
+	return b.expect(func(ctx *client.Context) bool {
+		return ctx.Route() == route
+	}, timeout...)
+}
+
+// ExpectParse 等待指定路由的消息，并将消息数据解析到 v 中
+func (b *Bot) ExpectParse(route int32, v interface{}, timeout ...time.Duration) error {
+	ctx, err := b.Expect(route, timeout...)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Parse(v)
+}
+
+// ExpectNone 断言在指定时间内未收到指定路由的消息
+func (b *Bot) ExpectNone(route int32, d time.Duration) error {
+	if _, err := b.Expect(route, d); err == nil {
+		return ErrUnexpectedMessage
+	} else if !gerrors.Is(err, ErrExpectTimeout) {
+		return err
+	}
+
+	return nil
+}
+
+// Pending 获取尚未被断言消费的消息数量
+func (b *Bot) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.messages)
+}
+
+// Close 关闭机器人
+func (b *Bot) Close() error {
+	if !b.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	err := b.conn.Close()
+
+	b.client.Destroy()
+
+	return err
+}
+
+// 接收消息
+func (b *Bot) receive(ctx *client.Context) {
+	b.mu.Lock()
+	b.messages = append(b.messages, ctx)
+	b.mu.Unlock()
+
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+}
+
+// 等待首个满足条件的消息
+func (b *Bot) expect(match func(ctx *client.Context) bool, timeout ...time.Duration) (*client.Context, error) {
+	d := b.cluster.opts.timeout
+	if len(timeout) > 0 {
+		d = timeout[0]
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	for {
+		if ctx, ok := b.take(match); ok {
+			return ctx, nil
+		}
+
+		select {
+		case <-b.notify:
+		case <-timer.C:
+			return nil, ErrExpectTimeout
+		}
+	}
+}
+
+// 取出首个满足条件的消息
+func (b *Bot) take(match func(ctx *client.Context) bool) (*client.Context, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, ctx := range b.messages {
+		if match(ctx) {
+			b.messages = append(b.messages[:i], b.messages[i+1:]...)
+			return ctx, true
+		}
+	}
+
+	return nil, false
+}
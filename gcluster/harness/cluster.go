@@ -0,0 +1,356 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/gcluster/gate"
+	"github.com/goodluck0107/gcore/gcluster/node"
+	"github.com/goodluck0107/gcore/gerrors"
+	locator "github.com/goodluck0107/gcore/glocate/memory"
+	"github.com/goodluck0107/gcore/gnetwork/tcp"
+	registry "github.com/goodluck0107/gcore/gregistry/memory"
+	"net"
+	"sync"
+	"time"
+)
+
+const gateID = "harness-gate"
+
+var (
+	ErrInvalidNodeIndex = gerrors.New("invalid node index")
+	ErrNodeKilled       = gerrors.New("node is killed")
+	ErrClusterNotReady  = gerrors.New("cluster is not ready")
+)
+
+// Cluster 进程内测试集群；由一个网关与若干节点组成，共用内存注册中心与定位器
+type Cluster struct {
+	opts     *options
+	ctx      context.Context
+	cancel   context.CancelFunc
+	registry *registry.Registry
+	locator  *locator.Locator
+	gate     *gate.Gate
+	addr     string
+	nodes    []*node.Node
+	killed   []bool
+	hangs    []chan struct{}
+	bots     []*Bot
+	mu       sync.Mutex
+}
+
+func NewCluster(opts ...Option) *Cluster {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c := &Cluster{}
+	c.opts = o
+	c.ctx, c.cancel = context.WithCancel(o.ctx)
+	c.registry = registry.NewRegistry(registry.WithContext(c.ctx))
+	c.locator = locator.NewLocator(locator.WithContext(c.ctx))
+
+	return c
+}
+
+// Start 启动集群；待网关与全部节点在注册中心就绪后返回
+func (c *Cluster) Start() error {
+	// 直接移交监听器给网关服务器，避免先分配端口再监听期间端口被抢占
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	c.addr = ln.Addr().String()
+
+	c.gate = gate.NewGate(
+		gate.WithContext(c.ctx),
+		gate.WithID(gateID),
+		gate.WithAddr("127.0.0.1:0"),
+		gate.WithServer(tcp.NewServer(tcp.WithServerListener(ln))),
+		gate.WithLocator(c.locator),
+		gate.WithRegistry(c.registry),
+		gate.WithTimeout(c.opts.timeout),
+	)
+	c.gate.Init()
+	c.gate.Start()
+
+	c.nodes = make([]*node.Node, 0, c.opts.nodes)
+	c.killed = make([]bool, c.opts.nodes)
+	c.hangs = make([]chan struct{}, c.opts.nodes)
+
+	for i := 0; i < c.opts.nodes; i++ {
+		n := node.NewNode(
+			node.WithContext(c.ctx),
+			node.WithID(fmt.Sprintf("harness-node-%d", i)),
+			node.WithAddr("127.0.0.1:0"),
+			node.WithCodec(c.opts.codec),
+			node.WithLocator(c.locator),
+			node.WithRegistry(c.registry),
+			node.WithTimeout(c.opts.timeout),
+		)
+
+		if c.opts.setup != nil {
+			c.opts.setup(i, n.Proxy())
+		}
+
+		n.Init()
+		n.Start()
+
+		c.nodes = append(c.nodes, n)
+	}
+
+	return c.wait()
+}
+
+// Addr 获取网关对外的监听地址
+func (c *Cluster) Addr() string {
+	return c.addr
+}
+
+// Gate 获取网关
+func (c *Cluster) Gate() *gate.Gate {
+	return c.gate
+}
+
+// Node 获取节点
+func (c *Cluster) Node(idx int) (*node.Node, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if idx < 0 || idx >= len(c.nodes) {
+		return nil, ErrInvalidNodeIndex
+	}
+
+	return c.nodes[idx], nil
+}
+
+// DrainNode 将节点置为挂起状态；网关不再向该节点分发无状态路由消息，节点仍正常处理有状态路由及直接投递的消息
+// 仅变更节点在注册中心中的状态，如需模拟节点失去响应请使用HangNode
+func (c *Cluster) DrainNode(idx int) error {
+	return c.setNodeState(idx, gcluster.Work, gcluster.Hang)
+}
+
+// HangNode 使节点失去响应；节点保持注册状态与连接，但其主协程不再处理路由消息、事件及投递的函数，直至调用ResumeNode
+// Actor在各自的协程中处理消息，不受影响
+func (c *Cluster) HangNode(idx int) error {
+	n, err := c.aliveNode(idx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.hangs[idx] != nil {
+		c.mu.Unlock()
+		return nil
+	}
+	release := make(chan struct{})
+	c.hangs[idx] = release
+	c.mu.Unlock()
+
+	blocked := make(chan struct{})
+
+	n.Proxy().Invoke(func() {
+		close(blocked)
+		<-release
+	})
+
+	select {
+	case <-blocked:
+		return nil
+	case <-time.After(c.opts.timeout):
+		return ErrClusterNotReady
+	}
+}
+
+// ResumeNode 恢复失去响应或挂起的节点
+func (c *Cluster) ResumeNode(idx int) error {
+	if _, err := c.aliveNode(idx); err != nil {
+		return err
+	}
+
+	c.release(idx)
+
+	return c.setNodeState(idx, gcluster.Hang, gcluster.Work)
+}
+
+// KillNode 关闭并销毁节点
+func (c *Cluster) KillNode(idx int) error {
+	n, err := c.aliveNode(idx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.killed[idx] = true
+	c.mu.Unlock()
+
+	c.release(idx)
+
+	n.Close()
+	n.Destroy()
+
+	return c.wait()
+}
+
+// Close 关闭集群；同时关闭由集群创建的机器人
+func (c *Cluster) Close() {
+	c.mu.Lock()
+	bots := c.bots
+	c.bots = nil
+
+	nodes := make([]*node.Node, 0, len(c.nodes))
+	for i, n := range c.nodes {
+		if !c.killed[i] {
+			c.killed[i] = true
+			nodes = append(nodes, n)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, bot := range bots {
+		_ = bot.Close()
+	}
+
+	for i := range c.hangs {
+		c.release(i)
+	}
+
+	for _, n := range nodes {
+		n.Close()
+		n.Destroy()
+	}
+
+	if c.gate != nil {
+		c.gate.Close()
+		c.gate.Destroy()
+	}
+
+	c.cancel()
+
+	_ = c.locator.Close()
+	_ = c.registry.Close()
+}
+
+// 设置节点状态；节点当前不处于from状态时不做变更
+func (c *Cluster) setNodeState(idx int, from, to gcluster.State) error {
+	n, err := c.aliveNode(idx)
+	if err != nil {
+		return err
+	}
+
+	if n.Proxy().GetState() != from {
+		return nil
+	}
+
+	if err = n.Proxy().SetState(to); err != nil {
+		return err
+	}
+
+	return c.wait()
+}
+
+// 释放失去响应的节点
+func (c *Cluster) release(idx int) {
+	c.mu.Lock()
+	release := c.hangs[idx]
+	c.hangs[idx] = nil
+	c.mu.Unlock()
+
+	if release != nil {
+		close(release)
+	}
+}
+
+// 获取存活的节点
+func (c *Cluster) aliveNode(idx int) (*node.Node, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if idx < 0 || idx >= len(c.nodes) {
+		return nil, ErrInvalidNodeIndex
+	}
+
+	if c.killed[idx] {
+		return nil, ErrNodeKilled
+	}
+
+	return c.nodes[idx], nil
+}
+
+// 等待注册中心、网关及各节点的链接器与集群状态一致
+func (c *Cluster) wait() error {
+	expected := make(map[string]string, len(c.nodes))
+	alive := make([]*node.Node, 0, len(c.nodes))
+	killed := make([]string, 0, len(c.nodes))
+
+	c.mu.Lock()
+	for i, n := range c.nodes {
+		if c.killed[i] {
+			killed = append(killed, n.Proxy().GetID())
+		} else {
+			expected[n.Proxy().GetID()] = n.Proxy().GetState().String()
+			alive = append(alive, n)
+		}
+	}
+	c.mu.Unlock()
+
+	deadline := time.Now().Add(c.opts.timeout)
+
+	for {
+		if c.isSynced(expected) && c.isLinked(expected, alive, killed) {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return ErrClusterNotReady
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// 检测注册中心是否已同步
+func (c *Cluster) isSynced(expected map[string]string) bool {
+	gates, err := c.registry.Services(c.ctx, gcluster.Gate.String())
+	if err != nil || len(gates) != 1 {
+		return false
+	}
+
+	nodes, err := c.registry.Services(c.ctx, gcluster.Node.String())
+	if err != nil || len(nodes) != len(expected) {
+		return false
+	}
+
+	for _, ins := range nodes {
+		if state, ok := expected[ins.ID]; !ok || state != ins.State {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 检测网关与节点的链接器是否已同步；网关需感知存活节点的最新状态且不再持有已销毁的节点，节点需感知网关
+func (c *Cluster) isLinked(expected map[string]string, alive []*node.Node, killed []string) bool {
+	for id, state := range expected {
+		if ins, ok := c.gate.Node(id); !ok || ins.State != state {
+			return false
+		}
+	}
+
+	for _, id := range killed {
+		if _, ok := c.gate.Node(id); ok {
+			return false
+		}
+	}
+
+	for _, n := range alive {
+		if !n.Proxy().HasGate(gateID) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,182 @@
+package harness_test
+
+import (
+	"github.com/goodluck0107/gcore/gcluster/harness"
+	"github.com/goodluck0107/gcore/gcluster/node"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	echo   = 1
+	silent = 2
+)
+
+type echoReq struct {
+	Text string `json:"text"`
+}
+
+type echoRes struct {
+	Node int    `json:"node"`
+	Text string `json:"text"`
+}
+
+func newCluster(t *testing.T, nodes int) *harness.Cluster {
+	c := harness.NewCluster(
+		harness.WithNodes(nodes),
+		harness.WithSetup(func(idx int, proxy *node.Proxy) {
+			proxy.Router().AddRouteHandler(echo, false, func(ctx node.Context) {
+				req := &echoReq{}
+
+				if err := ctx.Parse(req); err != nil {
+					t.Errorf("parse request failed: %v", err)
+					return
+				}
+
+				if err := ctx.Response(&echoRes{Node: idx, Text: req.Text}); err != nil {
+					t.Errorf("response failed: %v", err)
+				}
+			})
+		}),
+	)
+
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(c.Close)
+
+	return c
+}
+
+func request(t *testing.T, bot *harness.Bot, text string) *echoRes {
+	res := &echoRes{}
+
+	if err := bot.Request(echo, &echoReq{Text: text}, res); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if res.Text != text {
+		t.Fatalf("unexpected echo: %s", res.Text)
+	}
+
+	return res
+}
+
+func TestCluster_Request(t *testing.T) {
+	c := newCluster(t, 2)
+
+	bot, err := c.NewBot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request(t, bot, "hello")
+
+	if err = bot.ExpectNone(silent, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := bot.Pending(); n != 0 {
+		t.Fatalf("unexpected pending messages: %d", n)
+	}
+}
+
+func TestCluster_DrainNode(t *testing.T) {
+	c := newCluster(t, 2)
+
+	bot, err := c.NewBot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.DrainNode(0); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if res := request(t, bot, "hang"); res.Node != 1 {
+			t.Fatalf("message is delivered to hanged node %d", res.Node)
+		}
+	}
+
+	if err = c.ResumeNode(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.KillNode(1); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if res := request(t, bot, "kill"); res.Node != 0 {
+			t.Fatalf("message is delivered to killed node %d", res.Node)
+		}
+	}
+
+	if err = c.KillNode(1); err != harness.ErrNodeKilled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCluster_HangNode(t *testing.T) {
+	c := newCluster(t, 1)
+
+	bot, err := c.NewBot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.HangNode(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = bot.Send(echo, &echoReq{Text: "hang"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 失去响应的节点仍保持注册状态，消息照常投递但不会被处理
+	if err = bot.ExpectNone(echo, 200*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.ResumeNode(0); err != nil {
+		t.Fatal(err)
+	}
+
+	res := &echoRes{}
+
+	if err = bot.ExpectParse(echo, res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Text != "hang" {
+		t.Fatalf("unexpected echo: %s", res.Text)
+	}
+}
+
+func TestCluster_KillTickingNode(t *testing.T) {
+	var ticks atomic.Int64
+
+	c := harness.NewCluster(
+		harness.WithNodes(1),
+		harness.WithSetup(func(idx int, proxy *node.Proxy) {
+			proxy.TickInvoke(time.Millisecond, func() { ticks.Add(1) })
+		}),
+	)
+
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// 周期调用持续触发时关闭并销毁节点，不应阻塞或向已关闭的队列投递
+	if err := c.KillNode(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if ticks.Load() == 0 {
+		t.Fatal("tick is not invoked")
+	}
+}
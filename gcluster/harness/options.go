@@ -0,0 +1,60 @@
+package harness
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gcluster/node"
+	"github.com/goodluck0107/gcore/gencoding"
+	"time"
+)
+
+const (
+	defaultNodes   = 1               // 默认节点数量
+	defaultCodec   = "json"          // 默认编解码器名称
+	defaultTimeout = 3 * time.Second // 默认等待超时时间
+)
+
+type SetupHandler func(idx int, proxy *node.Proxy)
+
+type Option func(o *options)
+
+type options struct {
+	ctx     context.Context // 上下文
+	nodes   int             // 节点数量
+	codec   gencoding.Codec // 编解码器
+	timeout time.Duration   // 等待超时时间
+	setup   SetupHandler    // 节点安装函数
+}
+
+func defaultOptions() *options {
+	return &options{
+		ctx:     context.Background(),
+		nodes:   defaultNodes,
+		codec:   gencoding.Invoke(defaultCodec),
+		timeout: defaultTimeout,
+	}
+}
+
+// WithContext 设置上下文
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithNodes 设置节点数量
+func WithNodes(nodes int) Option {
+	return func(o *options) { o.nodes = nodes }
+}
+
+// WithCodec 设置编解码器；网关、节点与机器人共用
+func WithCodec(codec gencoding.Codec) Option {
+	return func(o *options) { o.codec = codec }
+}
+
+// WithTimeout 设置等待超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithSetup 设置节点安装函数；在节点启动前调用，用于注册路由与事件处理器
+func WithSetup(setup SetupHandler) Option {
+	return func(o *options) { o.setup = setup }
+}
@@ -167,6 +167,7 @@ func (m *Mesh) registerServiceInstance() {
 		Weight:   m.opts.weight,
 		Endpoint: m.transporter.Endpoint().String(),
 		Services: make([]string, 0, len(m.services)),
+		Metadata: m.opts.metadata,
 	}
 
 	for _, item := range m.services {
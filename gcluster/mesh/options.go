@@ -8,6 +8,7 @@ import (
 	"github.com/goodluck0107/gcore/glocate"
 	"github.com/goodluck0107/gcore/gregistry"
 	"github.com/goodluck0107/gcore/gtransport"
+	"github.com/goodluck0107/gcore/gutils/gconv"
 	"github.com/goodluck0107/gcore/gutils/guuid"
 	"time"
 )
@@ -20,11 +21,12 @@ const (
 )
 
 const (
-	defaultIDKey      = "etc.cluster.mesh.id"
-	defaultNameKey    = "etc.cluster.mesh.name"
-	defaultCodecKey   = "etc.cluster.mesh.codec"
-	defaultTimeoutKey = "etc.cluster.mesh.timeout"
-	defaultWeightKey  = "etc.cluster.mesh.weight"
+	defaultIDKey       = "etc.cluster.mesh.id"
+	defaultNameKey     = "etc.cluster.mesh.name"
+	defaultCodecKey    = "etc.cluster.mesh.codec"
+	defaultTimeoutKey  = "etc.cluster.mesh.timeout"
+	defaultWeightKey   = "etc.cluster.mesh.weight"
+	defaultMetadataKey = "etc.cluster.mesh.metadata"
 )
 
 type Option func(o *options)
@@ -40,6 +42,7 @@ type options struct {
 	encryptor   gcrypto.Encryptor      // 消息加密器
 	transporter gtransport.Transporter // 消息传输器
 	weight      int                    // 权重
+	metadata    map[string]string      // 元数据
 }
 
 func defaultOptions() *options {
@@ -73,6 +76,13 @@ func defaultOptions() *options {
 		opts.weight = weight
 	}
 
+	if metadata := getc.Get(defaultMetadataKey).Map(); len(metadata) > 0 {
+		opts.metadata = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			opts.metadata[k] = gconv.String(v)
+		}
+	}
+
 	return opts
 }
 
@@ -120,3 +130,8 @@ func WithTransporter(transporter gtransport.Transporter) Option {
 func WithWeight(weight int) Option {
 	return func(o *options) { o.weight = weight }
 }
+
+// WithMetadata 设置元数据
+func WithMetadata(metadata map[string]string) Option {
+	return func(o *options) { o.metadata = metadata }
+}
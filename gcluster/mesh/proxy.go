@@ -3,6 +3,7 @@ package mesh
 import (
 	"context"
 	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/gidgen"
 	"github.com/goodluck0107/gcore/gregistry"
 	"github.com/goodluck0107/gcore/gsession"
 	"github.com/goodluck0107/gcore/gtransport"
@@ -42,6 +43,11 @@ func (p *Proxy) GetName() string {
 	return p.mesh.opts.name
 }
 
+// NextID 生成全局唯一ID；使用 gidgen.SetGenerator 设置的ID生成器
+func (p *Proxy) NextID(ctx context.Context) (int64, error) {
+	return gidgen.Next(ctx)
+}
+
 // AddServiceProvider 添加服务提供者
 func (p *Proxy) AddServiceProvider(name string, desc interface{}, provider interface{}) {
 	p.mesh.addServiceProvider(name, desc, provider)
@@ -2,6 +2,7 @@ package node
 
 import (
 	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/gutils/gcall"
 	"sync"
 	"sync/atomic"
@@ -10,6 +11,8 @@ import (
 
 type Creator func(actor *Actor, args ...any) Processor
 
+type TimerHandler func(data []byte)
+
 const (
 	unstart   int32 = iota // 未启动
 	started                // 已启动
@@ -26,7 +29,10 @@ type Actor struct {
 	rw        sync.RWMutex                    // 锁
 	mailbox   chan Context                    // 邮箱
 	fnChan    chan func()                     // 调用函数
+	fires     *fireQueue                      // 定时器回调队列
 	binds     sync.Map                        // 绑定的用户
+	timers    sync.Map                        // 未触发的定时器
+	handlers  sync.Map                        // 具名定时器处理器
 }
 
 // ID 获取Actor的ID
@@ -66,44 +72,104 @@ func (a *Actor) Invoke(fn func()) {
 	a.fnChan <- fn
 }
 
-// AfterFunc 延迟调用，与官方的time.AfterFunc用法一致
+// AfterFunc 延迟调用，与官方的time.AfterFunc用法一致；回调在Actor协程中执行
 func (a *Actor) AfterFunc(d time.Duration, f func()) *Timer {
-	if a.state.Load() != started {
-		return nil
+	return a.addTimer(d, 0, nil, f)
+}
+
+// AfterInvoke 延迟调用（线程安全）
+func (a *Actor) AfterInvoke(d time.Duration, f func()) *Timer {
+	return a.addTimer(d, 0, nil, f)
+}
+
+// TickFunc 周期调用；回调在Actor协程中执行，直至定时器停止或Actor销毁
+func (a *Actor) TickFunc(d time.Duration, f func()) *Timer {
+	return a.addTimer(d, d, nil, f)
+}
+
+// AddTimerHandler 添加具名定时器处理器
+func (a *Actor) AddTimerHandler(name string, handler TimerHandler) {
+	a.handlers.Store(name, handler)
+}
+
+// Schedule 调度具名定时器；回调在Actor协程中执行
+// 具名定时器可通过 Actor.Timers 导出为描述，并在Actor恢复后重新调度
+func (a *Actor) Schedule(desc *TimerDescriptor) (*Timer, error) {
+	handler, ok := a.handlers.Load(desc.Name)
+	if !ok {
+		return nil, gerrors.ErrNotFoundTimerHandler
 	}
 
-	timer := time.AfterFunc(d, func() {
-		a.rw.RLock()
-		defer a.rw.RUnlock()
+	d := *desc
 
-		if a.state.Load() != started {
-			return
+	t := a.addTimer(d.Delay, d.Interval, &d, func() {
+		handler.(TimerHandler)(d.Data)
+	})
+	if t == nil {
+		return nil, gerrors.ErrActorDestroyed
+	}
+
+	return t, nil
+}
+
+// Timers 导出未触发的具名定时器描述
+func (a *Actor) Timers() []*TimerDescriptor {
+	descs := make([]*TimerDescriptor, 0)
+
+	a.timers.Range(func(key, _ any) bool {
+		t := key.(*Timer)
+
+		if t.desc == nil || t.stopped() {
+			return true
 		}
 
-		f()
+		desc := *t.desc
+		desc.Delay = t.entry.wheel.remaining(t.entry)
+		descs = append(descs, &desc)
+
+		return true
 	})
 
-	return &Timer{timer: timer}
+	return descs
 }
 
-// AfterInvoke 延迟调用（线程安全）
-func (a *Actor) AfterInvoke(d time.Duration, f func()) *Timer {
+// 添加定时器
+func (a *Actor) addTimer(d, interval time.Duration, desc *TimerDescriptor, f func()) *Timer {
 	if a.state.Load() != started {
 		return nil
 	}
 
-	timer := time.AfterFunc(d, func() {
-		a.rw.RLock()
-		defer a.rw.RUnlock()
+	t := &Timer{actor: a, desc: desc}
+	t.entry = a.scheduler.node.wheel.entry(interval, func() {
+		a.fire(t, f)
+	})
+
+	a.timers.Store(t, struct{}{})
+
+	a.scheduler.node.wheel.schedule(t.entry, d)
 
-		if a.state.Load() != started {
+	return t
+}
+
+// 触发定时器；将回调按触发顺序移交到Actor协程中执行
+func (a *Actor) fire(t *Timer, f func()) {
+	if t.entry.interval == 0 {
+		a.timers.Delete(t)
+	}
+
+	fn := func() {
+		if t.entry.interval > 0 && t.stopped() {
 			return
 		}
 
-		a.fnChan <- f
-	})
+		f()
+	}
+
+	if a.state.Load() != started {
+		return
+	}
 
-	return &Timer{timer: timer}
+	a.fires.push(fn)
 }
 
 // AddRouteHandler 添加路由处理器
@@ -209,6 +275,11 @@ func (a *Actor) destroy() bool {
 		return false
 	}
 
+	a.timers.Range(func(key, _ any) bool {
+		key.(*Timer).Stop()
+		return true
+	})
+
 	a.processor.Destroy()
 
 	a.scheduler.batchUnbindActor(func(relations map[int64]map[string]*Actor) {
@@ -275,6 +346,15 @@ func (a *Actor) dispatch() {
 			}
 
 			gcall.Call(handle)
+		case <-a.fires.signal:
+			for _, handle := range a.fires.pop() {
+				// 回调中可能销毁Actor，销毁后丢弃剩余的回调
+				if a.state.Load() != started {
+					break
+				}
+
+				gcall.Call(handle)
+			}
 		}
 	}
 }
@@ -24,7 +24,7 @@ type Context interface {
 	Event() gcluster.Event
 	// Kind 上下文消息类型
 	Kind() Kind
-	// Parse 解析消息
+	// Parse 解析消息；通过 WithValidateRequest 开启请求校验后，解析后按 validate 标签校验，校验失败时返回携带 gcodes.InvalidArgument 错误码的错误
 	Parse(v interface{}) error
 	// Defer 添加defer延迟调用栈
 	// 此方法功能与go defer一致，作用域也仅限于当前handler处理函数内，推荐使用Defer方法替代go defer使用
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/glocate"
 	"github.com/goodluck0107/gcore/glog"
 	"github.com/goodluck0107/gcore/gmodules"
 	"github.com/goodluck0107/gcore/gregistry"
@@ -39,9 +40,13 @@ type Node struct {
 	instances   []*gregistry.ServiceInstance
 	linker      *node.Server
 	fnChan      chan func()
+	fires       *fireQueue
 	scheduler   *Scheduler
+	wheel       *wheel
 	transporter gtransport.Server
 	wg          *sync.WaitGroup
+	waitMu      sync.RWMutex
+	waiting     bool // 是否已进入关闭等待
 	rw          sync.RWMutex
 	hooks       map[gcluster.Hook][]HookHandler
 }
@@ -59,10 +64,12 @@ func NewNode(opts ...Option) *Node {
 	n.router = newRouter(n)
 	n.trigger = newTrigger(n)
 	n.scheduler = newScheduler(n)
+	n.wheel = newWheel(o.tick)
 	n.hooks = make(map[gcluster.Hook][]HookHandler)
 	n.services = make([]*serviceEntity, 0)
 	n.instances = make([]*gregistry.ServiceInstance, 0)
 	n.fnChan = make(chan func(), 4096)
+	n.fires = newFireQueue()
 	n.state.Store(int32(gcluster.Shut))
 	n.wg = &sync.WaitGroup{}
 	n.evtPool = &sync.Pool{New: func() interface{} {
@@ -124,6 +131,8 @@ func (n *Node) Start() {
 
 	n.registerServiceInstances()
 
+	n.leaseLocation()
+
 	n.proxy.watch()
 
 	go n.dispatch()
@@ -145,6 +154,10 @@ func (n *Node) Close() {
 
 	n.runHookFunc(gcluster.Close)
 
+	n.waitMu.Lock()
+	n.waiting = true
+	n.waitMu.Unlock()
+
 	n.wg.Wait()
 }
 
@@ -158,6 +171,8 @@ func (n *Node) Destroy() {
 
 	n.deregisterServiceInstances()
 
+	n.revokeLocation()
+
 	n.stopLinkServer()
 
 	n.stopTransportServer()
@@ -166,6 +181,8 @@ func (n *Node) Destroy() {
 
 	n.trigger.close()
 
+	n.wheel.close()
+
 	close(n.fnChan)
 
 	n.cancel()
@@ -202,6 +219,13 @@ func (n *Node) dispatch() {
 				handle()
 				n.doneWait()
 			})
+		case <-n.fires.signal:
+			for _, handle := range n.fires.pop() {
+				gcall.Call(func() {
+					handle()
+					n.doneWait()
+				})
+			}
 		}
 	}
 }
@@ -299,6 +323,7 @@ func (n *Node) registerServiceInstances() {
 		Events:   events,
 		Endpoint: n.linker.Endpoint().String(),
 		Weight:   n.opts.weight,
+		Metadata: n.opts.metadata,
 	})
 
 	if n.transporter != nil {
@@ -316,6 +341,7 @@ func (n *Node) registerServiceInstances() {
 			Services: services,
 			Endpoint: n.transporter.Endpoint().String(),
 			Weight:   n.opts.weight,
+			Metadata: n.opts.metadata,
 		})
 	}
 
@@ -348,6 +374,36 @@ func (n *Node) deregisterServiceInstances() {
 	}
 }
 
+// 租用用户定位实例
+func (n *Node) leaseLocation() {
+	leaser, ok := n.opts.locator.(glocate.Leaser)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(n.ctx, defaultTimeout)
+	defer cancel()
+
+	if err := leaser.Lease(ctx, gcluster.Node.String(), n.opts.id); err != nil {
+		glog.Fatalf("lease location instance failed: %v", err)
+	}
+}
+
+// 撤销用户定位实例租约
+func (n *Node) revokeLocation() {
+	leaser, ok := n.opts.locator.(glocate.Leaser)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(n.ctx, defaultTimeout)
+	defer cancel()
+
+	if err := leaser.Revoke(ctx, gcluster.Node.String(), n.opts.id); err != nil {
+		glog.Errorf("revoke location instance failed: %v", err)
+	}
+}
+
 // 执行注册操作
 func (n *Node) doRegisterServiceInstances() error {
 	eg, ctx := errgroup.WithContext(n.ctx)
@@ -462,6 +518,11 @@ func (n *Node) printInfo() {
 	info.PrintBoxInfo("Node", infos...)
 }
 
+// 投递时间轮触发的函数到节点协程中执行；不阻塞时间轮协程，并保持触发顺序
+func (n *Node) invoke(fn func()) {
+	n.fires.push(fn)
+}
+
 func (n *Node) doneWait() {
 	if n.getState() != gcluster.Shut {
 		n.wg.Done()
@@ -473,3 +534,17 @@ func (n *Node) addWait() {
 		n.wg.Add(1)
 	}
 }
+
+// 节点尚未进入关闭等待时增加等待计数，避免与关闭等待并发
+func (n *Node) tryAddWait() bool {
+	n.waitMu.RLock()
+	defer n.waitMu.RUnlock()
+
+	if n.waiting {
+		return false
+	}
+
+	n.addWait()
+
+	return true
+}
@@ -8,25 +8,30 @@ import (
 	"github.com/goodluck0107/gcore/glocate"
 	"github.com/goodluck0107/gcore/gregistry"
 	"github.com/goodluck0107/gcore/gtransport"
+	"github.com/goodluck0107/gcore/gutils/gconv"
 	"github.com/goodluck0107/gcore/gutils/guuid"
 	"time"
 )
 
 const (
-	defaultName    = "node"          // 默认节点名称
-	defaultAddr    = ":0"            // 连接器监听地址
-	defaultCodec   = "proto"         // 默认编解码器名称
-	defaultTimeout = 3 * time.Second // 默认超时时间
-	defaultWeight  = 1               // 默认权重
+	defaultName    = "node"                // 默认节点名称
+	defaultAddr    = ":0"                  // 连接器监听地址
+	defaultCodec   = "proto"               // 默认编解码器名称
+	defaultTimeout = 3 * time.Second       // 默认超时时间
+	defaultWeight  = 1                     // 默认权重
+	defaultTick    = 10 * time.Millisecond // 默认定时器时间轮刻度
 )
 
 const (
-	defaultIDKey      = "etc.cluster.node.id"
-	defaultNameKey    = "etc.cluster.node.name"
-	defaultAddrKey    = "etc.cluster.node.addr"
-	defaultCodecKey   = "etc.cluster.node.codec"
-	defaultTimeoutKey = "etc.cluster.node.timeout"
-	defaultWeightKey  = "etc.cluster.node.weight"
+	defaultIDKey       = "etc.cluster.node.id"
+	defaultNameKey     = "etc.cluster.node.name"
+	defaultAddrKey     = "etc.cluster.node.addr"
+	defaultCodecKey    = "etc.cluster.node.codec"
+	defaultTimeoutKey  = "etc.cluster.node.timeout"
+	defaultWeightKey   = "etc.cluster.node.weight"
+	defaultMetadataKey = "etc.cluster.node.metadata"
+	defaultTickKey     = "etc.cluster.node.timerTick"
+	defaultValidateKey = "etc.cluster.node.validateRequest"
 )
 
 // SchedulingModel 调度模型
@@ -46,6 +51,9 @@ type options struct {
 	encryptor   gcrypto.Encryptor      // 消息加密器
 	transporter gtransport.Transporter // 消息传输器
 	weight      int                    // 权重
+	metadata    map[string]string      // 元数据
+	tick        time.Duration          // 定时器时间轮刻度；刻度越小定时越精确，驱动开销越大
+	validate    bool                   // 是否在解析请求后按 validate 标签校验
 }
 
 func defaultOptions() *options {
@@ -56,8 +64,11 @@ func defaultOptions() *options {
 		codec:   gencoding.Invoke(defaultCodec),
 		timeout: defaultTimeout,
 		weight:  defaultWeight,
+		tick:    defaultTick,
 	}
 
+	opts.validate = getc.Get(defaultValidateKey, false).Bool()
+
 	if id := getc.Get(defaultIDKey).String(); id != "" {
 		opts.id = id
 	} else {
@@ -84,6 +95,17 @@ func defaultOptions() *options {
 		opts.weight = weight
 	}
 
+	if metadata := getc.Get(defaultMetadataKey).Map(); len(metadata) > 0 {
+		opts.metadata = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			opts.metadata[k] = gconv.String(v)
+		}
+	}
+
+	if tick := getc.Get(defaultTickKey).Duration(); tick > 0 {
+		opts.tick = tick
+	}
+
 	return opts
 }
 
@@ -141,3 +163,18 @@ func WithTransporter(transporter gtransport.Transporter) Option {
 func WithWeight(weight int) Option {
 	return func(o *options) { o.weight = weight }
 }
+
+// WithMetadata 设置元数据
+func WithMetadata(metadata map[string]string) Option {
+	return func(o *options) { o.metadata = metadata }
+}
+
+// WithTimerTick 设置定时器时间轮刻度
+func WithTimerTick(tick time.Duration) Option {
+	return func(o *options) { o.tick = tick }
+}
+
+// WithValidateRequest 设置是否在解析请求后按 validate 标签校验；默认不校验
+func WithValidateRequest(validate bool) Option {
+	return func(o *options) { o.validate = validate }
+}
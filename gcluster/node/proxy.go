@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/goodluck0107/gcore/gcluster"
 	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gidgen"
 	"github.com/goodluck0107/gcore/gregistry"
 	"github.com/goodluck0107/gcore/gsession"
 	"github.com/goodluck0107/gcore/gtransport"
@@ -44,6 +45,11 @@ func (p *Proxy) GetName() string {
 	return p.node.opts.name
 }
 
+// NextID 生成全局唯一ID；使用 gidgen.SetGenerator 设置的ID生成器
+func (p *Proxy) NextID(ctx context.Context) (int64, error) {
+	return gidgen.Next(ctx)
+}
+
 // GetState 获取当前节点状态
 func (p *Proxy) GetState() gcluster.State {
 	return p.node.getState()
@@ -288,23 +294,55 @@ func (p *Proxy) Invoke(fn func()) {
 func (p *Proxy) AfterFunc(d time.Duration, f func()) *Timer {
 	p.node.addWait()
 
-	timer := time.AfterFunc(d, func() {
-		f()
-		p.node.doneWait()
+	entry := p.node.wheel.add(d, 0, func() {
+		go func() {
+			f()
+			p.node.doneWait()
+		}()
 	})
 
-	return &Timer{node: p.node, timer: timer}
+	return &Timer{node: p.node, entry: entry}
 }
 
 // AfterInvoke 延迟调用（线程安全）
 func (p *Proxy) AfterInvoke(d time.Duration, f func()) *Timer {
 	p.node.addWait()
 
-	timer := time.AfterFunc(d, func() {
-		p.node.fnChan <- f
+	entry := p.node.wheel.add(d, 0, func() {
+		p.node.invoke(f)
+	})
+
+	return &Timer{node: p.node, entry: entry}
+}
+
+// TickFunc 周期调用；每次调用均在独立的协程中执行，直至定时器停止
+func (p *Proxy) TickFunc(d time.Duration, f func()) *Timer {
+	entry := p.node.wheel.add(d, d, func() {
+		go f()
 	})
 
-	return &Timer{node: p.node, timer: timer}
+	return &Timer{entry: entry}
+}
+
+// TickInvoke 周期调用（线程安全）；直至定时器停止
+func (p *Proxy) TickInvoke(d time.Duration, f func()) *Timer {
+	t := &Timer{}
+	t.entry = p.node.wheel.entry(d, func() {
+		// 节点进入关闭等待后不再投递周期调用
+		if !p.node.tryAddWait() {
+			return
+		}
+
+		p.node.invoke(func() {
+			if !t.stopped() {
+				f()
+			}
+		})
+	})
+
+	p.node.wheel.schedule(t.entry, d)
+
+	return t
 }
 
 // Spawn 衍生出一个新的Actor
@@ -7,6 +7,7 @@ import (
 	"github.com/goodluck0107/gcore/gsession"
 	"github.com/goodluck0107/gcore/gtask"
 	"github.com/goodluck0107/gcore/gtransport"
+	"github.com/goodluck0107/gcore/gutils/gvalidate"
 	"github.com/goodluck0107/gcore/gwrap/chains"
 	"github.com/jinzhu/copier"
 	"sync/atomic"
@@ -67,8 +68,21 @@ func (r *request) Kind() Kind {
 	return Request
 }
 
-// Parse 解析消息
+// Parse 解析消息；开启请求校验时，解析后按 validate 标签校验，校验失败时返回携带 gcodes.InvalidArgument 错误码的错误
 func (r *request) Parse(v interface{}) error {
+	if err := r.decode(v); err != nil {
+		return err
+	}
+
+	if !r.node.opts.validate {
+		return nil
+	}
+
+	return gvalidate.Request(v)
+}
+
+// 解码消息
+func (r *request) decode(v interface{}) error {
 	msg, ok := r.message.Data.([]byte)
 	if !ok {
 		return copier.CopyWithOption(v, r.message.Data, copier.Option{
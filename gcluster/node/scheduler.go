@@ -39,6 +39,7 @@ func (s *Scheduler) spawn(creator Creator, opts ...ActorOption) (*Actor, error)
 	act.events = make(map[gcluster.Event]EventHandler, 3)
 	act.mailbox = make(chan Context, 4096)
 	act.fnChan = make(chan func(), 4096)
+	act.fires = newFireQueue()
 	act.processor = creator(act, o.args...)
 
 	s.mu.Lock()
@@ -1,10 +1,26 @@
 package node
 
-import "time"
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	wheelRootBits  = 8                                                     // 第一层时间轮槽位位数
+	wheelLevelBits = 6                                                     // 高层时间轮槽位位数
+	wheelLevels    = 5                                                     // 时间轮层数
+	wheelRootSize  = 1 << wheelRootBits                                    // 第一层时间轮槽位数
+	wheelLevelSize = 1 << wheelLevelBits                                   // 高层时间轮槽位数
+	wheelMaxTicks  = 1<<(wheelRootBits+wheelLevelBits*(wheelLevels-1)) - 1 // 时间轮可容纳的最大刻度数
+)
 
 type Timer struct {
-	node  *Node
-	timer *time.Timer
+	node  *Node            // 节点；不为空时定时器参与节点关闭等待
+	actor *Actor           // 所属Actor
+	entry *timerEntry      // 时间轮条目
+	desc  *TimerDescriptor // 具名定时器描述
 }
 
 // Stop 停止定时器
@@ -13,9 +29,300 @@ func (t *Timer) Stop() (ok bool) {
 		return
 	}
 
-	if ok = t.timer.Stop(); ok && t.node != nil {
+	if t.actor != nil {
+		t.actor.timers.Delete(t)
+	}
+
+	if ok = t.entry.wheel.remove(t.entry); ok && t.node != nil {
 		t.node.doneWait()
 	}
 
 	return
 }
+
+// 定时器是否已停止
+func (t *Timer) stopped() bool {
+	return t.entry.stopped.Load()
+}
+
+// TimerDescriptor 具名定时器描述；可随Actor快照序列化，并在恢复时通过 Actor.Schedule 重新调度
+type TimerDescriptor struct {
+	Name     string        `json:"name"`     // 定时器处理器名称
+	Data     []byte        `json:"data"`     // 传递给处理器的数据
+	Delay    time.Duration `json:"delay"`    // 距离下次触发的延迟
+	Interval time.Duration `json:"interval"` // 重复触发间隔；为0时表示仅触发一次
+}
+
+type timerEntry struct {
+	wheel    *wheel        // 所属时间轮
+	expire   uint64        // 到期刻度
+	interval uint64        // 重复间隔刻度；为0时表示仅触发一次
+	fire     func()        // 到期回调；在时间轮协程中执行，不可阻塞，需投递到所属协程时通过 fireQueue 移交
+	slot     *list.List    // 所在槽位
+	elem     *list.Element // 槽位中的元素
+	stopped  atomic.Bool   // 是否已停止
+}
+
+// 定时器回调队列；时间轮协程将到期回调追加到所属协程的队列后立即返回，由所属协程按触发顺序取出执行
+// 队列不设上限，所属协程繁忙时时间轮协程也不会被阻塞
+type fireQueue struct {
+	mu     sync.Mutex
+	fns    []func()
+	signal chan struct{}
+}
+
+func newFireQueue() *fireQueue {
+	return &fireQueue{signal: make(chan struct{}, 1)}
+}
+
+// 追加到期回调
+func (q *fireQueue) push(fn func()) {
+	q.mu.Lock()
+	q.fns = append(q.fns, fn)
+	q.mu.Unlock()
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// 取出全部到期回调
+func (q *fireQueue) pop() []func() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	fns := q.fns
+	q.fns = nil
+
+	return fns
+}
+
+// 分层时间轮；所有定时器共享一个驱动协程，以避免大量 time.Timer 带来的堆调整开销
+type wheel struct {
+	tick    time.Duration
+	mu      sync.Mutex
+	now     uint64 // 下一个待处理的刻度
+	start   time.Time
+	levels  [wheelLevels][]*list.List
+	once    sync.Once
+	closed  bool
+	chClose chan struct{}
+	chDone  chan struct{}
+}
+
+func newWheel(tick time.Duration) *wheel {
+	w := &wheel{}
+	w.tick = tick
+	w.chClose = make(chan struct{})
+	w.chDone = make(chan struct{})
+
+	for i := range w.levels {
+		size := wheelLevelSize
+		if i == 0 {
+			size = wheelRootSize
+		}
+
+		w.levels[i] = make([]*list.List, size)
+		for j := range w.levels[i] {
+			w.levels[i][j] = list.New()
+		}
+	}
+
+	return w
+}
+
+// 新建定时器条目；interval 大于0时为重复定时器
+func (w *wheel) entry(interval time.Duration, fire func()) *timerEntry {
+	e := &timerEntry{wheel: w, fire: fire}
+
+	if interval > 0 {
+		e.interval = w.ticks(interval)
+	}
+
+	return e
+}
+
+// 调度定时器条目
+func (w *wheel) schedule(e *timerEntry, d time.Duration) {
+	w.once.Do(func() {
+		w.start = time.Now()
+		go w.run()
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		e.stopped.Store(true)
+		return
+	}
+
+	// 按到期时刻向上取整，保证定时器不会提前触发
+	e.expire = max(uint64((time.Since(w.start)+d+w.tick-1)/w.tick), w.now)
+
+	w.place(e)
+}
+
+// 添加定时器
+func (w *wheel) add(d, interval time.Duration, fire func()) *timerEntry {
+	e := w.entry(interval, fire)
+
+	w.schedule(e, d)
+
+	return e
+}
+
+// 移除定时器；定时器尚未触发时返回true
+func (w *wheel) remove(e *timerEntry) bool {
+	e.stopped.Store(true)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if e.slot == nil {
+		return false
+	}
+
+	e.slot.Remove(e.elem)
+	e.slot, e.elem = nil, nil
+
+	return true
+}
+
+// 获取定时器距离触发的剩余时间
+func (w *wheel) remaining(e *timerEntry) time.Duration {
+	w.mu.Lock()
+	expire, now := e.expire, w.now
+	w.mu.Unlock()
+
+	if expire <= now {
+		return 0
+	}
+
+	return time.Duration(expire-now) * w.tick
+}
+
+// 关闭时间轮；关闭后未触发的定时器将被丢弃
+func (w *wheel) close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	// 时间轮尚未启动时无需等待驱动协程退出
+	w.once.Do(func() { close(w.chDone) })
+
+	close(w.chClose)
+
+	<-w.chDone
+}
+
+// 将时长换算为刻度数
+func (w *wheel) ticks(d time.Duration) uint64 {
+	return max(uint64((d+w.tick-1)/w.tick), 1)
+}
+
+// 将定时器放入对应的槽位
+// 超出时间轮容量的定时器暂存于最高层可达的最远槽位，级联时按剩余刻度重新放置，直至进入容量范围
+func (w *wheel) place(e *timerEntry) {
+	at, delta := e.expire, e.expire-w.now
+	if delta > wheelMaxTicks {
+		at, delta = w.now+wheelMaxTicks, wheelMaxTicks
+	}
+
+	var slot *list.List
+
+	if delta < wheelRootSize {
+		slot = w.levels[0][at&(wheelRootSize-1)]
+	} else {
+		for level := 1; level < wheelLevels; level++ {
+			if delta < 1<<(wheelRootBits+wheelLevelBits*level) {
+				shift := wheelRootBits + wheelLevelBits*(level-1)
+				slot = w.levels[level][(at>>shift)&(wheelLevelSize-1)]
+				break
+			}
+		}
+	}
+
+	e.slot, e.elem = slot, slot.PushBack(e)
+}
+
+// 将高层槽位中的定时器重新放入低层槽位；返回槽位索引
+func (w *wheel) cascade(level int) uint64 {
+	shift := wheelRootBits + wheelLevelBits*(level-1)
+	index := (w.now >> shift) & (wheelLevelSize - 1)
+	slot := w.levels[level][index]
+
+	for elem := slot.Front(); elem != nil; {
+		next := elem.Next()
+		e := slot.Remove(elem).(*timerEntry)
+		w.place(e)
+		elem = next
+	}
+
+	return index
+}
+
+// 驱动时间轮
+func (w *wheel) run() {
+	defer close(w.chDone)
+
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.chClose:
+			return
+		case <-ticker.C:
+			w.advance(uint64(time.Since(w.start) / w.tick))
+		}
+	}
+}
+
+// 推进时间轮至指定刻度并触发到期的定时器
+func (w *wheel) advance(target uint64) {
+	var expired []*timerEntry
+
+	w.mu.Lock()
+
+	for w.now <= target {
+		if w.now&(wheelRootSize-1) == 0 {
+			for level := 1; level < wheelLevels; level++ {
+				if w.cascade(level) != 0 {
+					break
+				}
+			}
+		}
+
+		slot := w.levels[0][w.now&(wheelRootSize-1)]
+		for elem := slot.Front(); elem != nil; elem = slot.Front() {
+			e := slot.Remove(elem).(*timerEntry)
+			e.slot, e.elem = nil, nil
+			expired = append(expired, e)
+		}
+
+		w.now++
+	}
+
+	w.mu.Unlock()
+
+	for _, e := range expired {
+		e.fire()
+
+		if e.interval == 0 || e.stopped.Load() {
+			continue
+		}
+
+		w.mu.Lock()
+		if !w.closed && !e.stopped.Load() {
+			e.expire = max(e.expire+e.interval, w.now)
+			w.place(e)
+		}
+		w.mu.Unlock()
+	}
+}
@@ -0,0 +1,217 @@
+package node_test
+
+import (
+	"github.com/goodluck0107/gcore/gcluster/node"
+	"github.com/goodluck0107/gcore/gencoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type timerProcessor struct {
+	node.BaseProcessor
+}
+
+func newTimerNode(id string) *node.Node {
+	return node.NewNode(node.WithID(id), node.WithTimerTick(time.Millisecond))
+}
+
+func TestProxy_AfterFunc(t *testing.T) {
+	proxy := newTimerNode("timer-proxy").Proxy()
+
+	var (
+		start = time.Now()
+		fired = make(chan time.Duration, 1)
+	)
+
+	// 超过第一层时间轮范围的定时器需经过层级降级后触发
+	proxy.AfterFunc(300*time.Millisecond, func() { fired <- time.Since(start) })
+
+	stopped := proxy.AfterFunc(100*time.Millisecond, func() { t.Error("stopped timer is fired") })
+	if !stopped.Stop() {
+		t.Fatal("stop timer failed")
+	}
+
+	select {
+	case elapsed := <-fired:
+		if elapsed < 300*time.Millisecond {
+			t.Fatalf("timer is fired too early: %v", elapsed)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timer is not fired")
+	}
+}
+
+func TestActor_TickFunc(t *testing.T) {
+	proxy := newTimerNode("timer-tick").Proxy()
+
+	actor, err := proxy.Spawn(func(actor *node.Actor, args ...any) node.Processor {
+		return &timerProcessor{}
+	}, node.WithActorKind("room"), node.WithActorID("1"), node.WithActorNonWait())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var count atomic.Int32
+
+	ticker := actor.TickFunc(10*time.Millisecond, func() { count.Add(1) })
+
+	time.Sleep(100 * time.Millisecond)
+
+	ticker.Stop()
+
+	n := count.Load()
+	if n < 3 {
+		t.Fatalf("unexpected tick count: %d", n)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if count.Load() != n {
+		t.Fatalf("ticker is fired after stopped: %d != %d", count.Load(), n)
+	}
+
+	actor.AfterFunc(10*time.Millisecond, func() { t.Error("timer of destroyed actor is fired") })
+	actor.Destroy()
+
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestActor_Schedule(t *testing.T) {
+	proxy := newTimerNode("timer-schedule").Proxy()
+
+	creator := func(actor *node.Actor, args ...any) node.Processor {
+		return &timerProcessor{}
+	}
+
+	fired := make(chan string, 1)
+
+	actor, err := proxy.Spawn(creator, node.WithActorKind("player"), node.WithActorID("1"), node.WithActorNonWait())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actor.AddTimerHandler("buff", func(data []byte) { fired <- string(data) })
+
+	if _, err = actor.Schedule(&node.TimerDescriptor{Name: "buff", Data: []byte("speed"), Delay: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = actor.Schedule(&node.TimerDescriptor{Name: "unknown"}); err == nil {
+		t.Fatal("timer without handler is scheduled")
+	}
+
+	// 快照Actor的定时器并在新的Actor上恢复
+	buf, err := json.Marshal(actor.Timers())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actor.Destroy()
+
+	var descs []*node.TimerDescriptor
+	if err = json.Unmarshal(buf, &descs); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(descs) != 1 || descs[0].Name != "buff" || descs[0].Delay <= 59*time.Minute {
+		t.Fatalf("unexpected timers: %s", buf)
+	}
+
+	restored, err := proxy.Spawn(creator, node.WithActorKind("player"), node.WithActorID("1"), node.WithActorNonWait())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Destroy()
+
+	restored.AddTimerHandler("buff", func(data []byte) { fired <- string(data) })
+
+	descs[0].Delay = 10 * time.Millisecond
+
+	if _, err = restored.Schedule(descs[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case data := <-fired:
+		if data != "speed" {
+			t.Fatalf("unexpected data: %s", data)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("restored timer is not fired")
+	}
+}
+
+func TestActor_LongTimer(t *testing.T) {
+	proxy := newTimerNode("timer-long").Proxy()
+
+	actor, err := proxy.Spawn(func(actor *node.Actor, args ...any) node.Processor {
+		return &timerProcessor{}
+	}, node.WithActorKind("player"), node.WithActorID("2"), node.WithActorNonWait())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer actor.Destroy()
+
+	actor.AddTimerHandler("expire", func(data []byte) {})
+
+	// 超出时间轮容量的定时器不应被截断为时间轮容量而提前触发
+	delay := 90 * 24 * time.Hour
+
+	if _, err = actor.Schedule(&node.TimerDescriptor{Name: "expire", Delay: delay}); err != nil {
+		t.Fatal(err)
+	}
+
+	if descs := actor.Timers(); len(descs) != 1 || descs[0].Delay < delay-time.Second {
+		t.Fatalf("unexpected timers: %+v", descs[0])
+	}
+}
+
+func TestActor_TickOrder(t *testing.T) {
+	proxy := newTimerNode("timer-order").Proxy()
+
+	actor, err := proxy.Spawn(func(actor *node.Actor, args ...any) node.Processor {
+		return &timerProcessor{}
+	}, node.WithActorKind("player"), node.WithActorID("3"), node.WithActorNonWait())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer actor.Destroy()
+
+	const total = 8192
+
+	var (
+		fired atomic.Int64
+		done  = make(chan struct{})
+		order = make([]int, 0, total)
+	)
+
+	// Actor协程阻塞期间，到期回调仍按触发顺序依次执行
+	block := make(chan struct{})
+	actor.Invoke(func() { <-block })
+
+	for i := 0; i < total; i++ {
+		actor.AfterFunc(time.Duration(i/1024)*time.Millisecond, func() {
+			order = append(order, i)
+
+			if fired.Add(1) == total {
+				close(done)
+			}
+		})
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(block)
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timers are not fired: %d", fired.Load())
+	}
+
+	for i := 1; i < total; i++ {
+		if order[i]/1024 < order[i-1]/1024 {
+			t.Fatalf("timers are fired out of order at %d", i)
+		}
+	}
+}
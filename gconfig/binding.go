@@ -0,0 +1,149 @@
+package gconfig
+
+import (
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gutils/gvalidate"
+	"log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+type ChangeCallbackFunc[T any] func(old, new *T)
+
+// Binding 配置绑定；配置变化时重新扫描并校验，校验通过后替换绑定值
+type Binding[T any] struct {
+	c         Configurator
+	pattern   string
+	target    *T
+	cancel    func()
+	value     atomic.Pointer[T]
+	mu        sync.Mutex
+	callbacks []ChangeCallbackFunc[T]
+	closed    atomic.Bool
+}
+
+// Bind 将匹配规则对应的配置绑定到结构体上，并保持同步
+// 结构体通过 validate 标签（参见 gvalidate.Struct）校验，校验失败的配置变更将被拒绝并保留上一个有效版本
+// 配置变更时 target 将被原地更新；并发读取时请通过 Binding.Load 获取快照，每次变更均发布新的快照，可安全地并发读取
+func Bind[T any](pattern string, target *T) (*Binding[T], error) {
+	return BindWith(globalConfigurator, pattern, target)
+}
+
+// BindWith 使用指定配置器绑定配置
+func BindWith[T any](c Configurator, pattern string, target *T) (*Binding[T], error) {
+	if c == nil {
+		return nil, gerrors.ErrMissConfigurator
+	}
+
+	if target == nil {
+		return nil, gerrors.ErrInvalidArgument
+	}
+
+	b := &Binding[T]{c: c, pattern: pattern, target: target}
+
+	v, err := b.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	b.store(v)
+
+	fn := func(names ...string) {
+		if b.closed.Load() {
+			return
+		}
+
+		if err := b.reload(); err != nil {
+			log.Printf("reload binding configure %s failed: %v", pattern, err)
+		}
+	}
+
+	// 配置器不支持取消监听时，解除绑定后监听回调将直接返回
+	if wc, ok := c.(WatchCanceler); ok {
+		b.cancel = wc.WatchCancelable(fn)
+	} else {
+		c.Watch(fn)
+	}
+
+	return b, nil
+}
+
+// Load 获取当前绑定值；返回值为只读快照，请勿修改
+func (b *Binding[T]) Load() *T {
+	return b.value.Load()
+}
+
+// OnChange 设置变更回调；回调接收变更前后的绑定值
+func (b *Binding[T]) OnChange(cb ChangeCallbackFunc[T]) *Binding[T] {
+	b.mu.Lock()
+	b.callbacks = append(b.callbacks, cb)
+	b.mu.Unlock()
+
+	return b
+}
+
+// Reload 重新扫描并校验配置
+func (b *Binding[T]) Reload() error {
+	return b.reload()
+}
+
+// Unbind 解除绑定；解除后不再同步配置变更
+func (b *Binding[T]) Unbind() {
+	if b.closed.Swap(true) {
+		return
+	}
+
+	if b.cancel != nil {
+		b.cancel()
+	}
+}
+
+// 扫描并校验配置
+func (b *Binding[T]) scan() (*T, error) {
+	v := new(T)
+
+	if err := b.c.Match(b.pattern).Scan(v); err != nil {
+		return nil, err
+	}
+
+	if err := gvalidate.Struct(v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// 重新加载配置
+func (b *Binding[T]) reload() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed.Load() {
+		return nil
+	}
+
+	v, err := b.scan()
+	if err != nil {
+		return err
+	}
+
+	old := b.value.Load()
+	if reflect.DeepEqual(old, v) {
+		return nil
+	}
+
+	b.store(v)
+
+	for _, cb := range b.callbacks {
+		cb(old, v)
+	}
+
+	return nil
+}
+
+// 发布新的绑定值，并同步到目标结构体
+func (b *Binding[T]) store(v *T) {
+	b.value.Store(v)
+	*b.target = *v
+}
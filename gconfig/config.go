@@ -88,6 +88,33 @@ func Store(ctx context.Context, source string, file string, content interface{},
 	return globalConfigurator.Store(ctx, source, file, content, override...)
 }
 
+// Commit 保存配置项并记录修订版本
+func Commit(ctx context.Context, source string, file string, content interface{}, opts ...CommitOption) (*Revision, error) {
+	if globalConfigurator == nil {
+		return nil, nil
+	}
+
+	return globalConfigurator.Commit(ctx, source, file, content, opts...)
+}
+
+// Revisions 获取配置项的修订记录列表
+func Revisions(ctx context.Context, source string, file string) ([]*Revision, error) {
+	if globalConfigurator == nil {
+		return nil, nil
+	}
+
+	return globalConfigurator.Revisions(ctx, source, file)
+}
+
+// Rollback 将配置项回滚到指定版本
+func Rollback(ctx context.Context, source string, file string, version int64, opts ...CommitOption) (*Revision, error) {
+	if globalConfigurator == nil {
+		return nil, nil
+	}
+
+	return globalConfigurator.Rollback(ctx, source, file, version, opts...)
+}
+
 // Close 关闭配置监听
 func Close() {
 	if globalConfigurator != nil {
@@ -4,6 +4,11 @@ import (
 	"context"
 	"github.com/goodluck0107/gcore/gconfig"
 	"github.com/goodluck0107/gcore/gconfig/file"
+	"github.com/goodluck0107/gcore/gerrors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -73,3 +78,250 @@ func BenchmarkGet(b *testing.B) {
 		gconfig.Get("config").Value()
 	}
 }
+
+type serverConfig struct {
+	Addr    string `json:"addr" validate:"required"`
+	MaxConn int    `json:"maxConn" validate:"min=1,max=10000"`
+}
+
+func TestBind(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "server.json")
+
+	if err := os.WriteFile(filename, []byte(`{"addr":":3553","maxConn":100}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := gconfig.NewConfigurator(gconfig.WithSources(file.NewSource(file.WithPath(dir))))
+	defer c.Close()
+
+	var cfg serverConfig
+
+	binding, err := gconfig.BindWith(c, "server", &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer binding.Unbind()
+
+	if cfg.Addr != ":3553" || cfg.MaxConn != 100 {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	changed := make(chan [2]serverConfig, 1)
+	binding.OnChange(func(old, new *serverConfig) {
+		changed <- [2]serverConfig{*old, *new}
+	})
+
+	if err = os.WriteFile(filename, []byte(`{"addr":":3553","maxConn":0}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case v := <-changed:
+		t.Fatalf("invalid config is accepted: %+v", v)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	if binding.Load().MaxConn != 100 {
+		t.Fatalf("unexpected config: %+v", binding.Load())
+	}
+
+	if err = os.WriteFile(filename, []byte(`{"addr":":3553","maxConn":200}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case v := <-changed:
+		if v[0].MaxConn != 100 || v[1].MaxConn != 200 {
+			t.Fatalf("unexpected change: %+v", v)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("config change is not notified")
+	}
+}
+
+func TestBind_UnbindOnChange(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "server.json")
+
+	if err := os.WriteFile(filename, []byte(`{"addr":":3553","maxConn":100}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := gconfig.NewConfigurator(gconfig.WithSources(file.NewSource(file.WithPath(dir))))
+	defer c.Close()
+
+	var cfg serverConfig
+
+	binding, err := gconfig.BindWith(c, "server", &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 在变更回调中解除绑定不应阻塞
+	unbound := make(chan struct{})
+	binding.OnChange(func(old, new *serverConfig) {
+		binding.Unbind()
+		close(unbound)
+	})
+
+	if err = os.WriteFile(filename, []byte(`{"addr":":3553","maxConn":200}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-unbound:
+	case <-time.After(3 * time.Second):
+		t.Fatal("unbind in change callback is blocked")
+	}
+
+	if err = os.WriteFile(filename, []byte(`{"addr":":3553","maxConn":300}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	if v := binding.Load(); v.MaxConn != 200 {
+		t.Fatalf("unexpected config after unbind: %+v", v)
+	}
+}
+
+// 内存版本化配置源
+type memorySource struct {
+	mu        sync.Mutex
+	files     map[string][]byte
+	revisions map[string][]*gconfig.Revision
+}
+
+func newMemorySource() *memorySource {
+	return &memorySource{files: make(map[string][]byte), revisions: make(map[string][]*gconfig.Revision)}
+}
+
+func (s *memorySource) Name() string { return "memory" }
+
+func (s *memorySource) Load(_ context.Context, file ...string) ([]*gconfig.Configuration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	configs := make([]*gconfig.Configuration, 0, len(s.files))
+	for name, content := range s.files {
+		if len(file) > 0 && file[0] != name {
+			continue
+		}
+
+		ext := filepath.Ext(name)
+		configs = append(configs, &gconfig.Configuration{
+			File:    name,
+			Name:    strings.TrimSuffix(name, ext),
+			Format:  strings.TrimPrefix(ext, "."),
+			Content: content,
+		})
+	}
+
+	return configs, nil
+}
+
+func (s *memorySource) Store(_ context.Context, file string, content []byte) error {
+	s.mu.Lock()
+	s.files[file] = content
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *memorySource) Watch(context.Context) (gconfig.Watcher, error) {
+	return nil, gerrors.New("not support watch")
+}
+
+func (s *memorySource) Close() error { return nil }
+
+func (s *memorySource) Commit(_ context.Context, file string, content []byte, revision *gconfig.Revision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if int64(len(s.revisions[file])) != revision.Version-1 {
+		return gerrors.ErrConfigVersionConflict
+	}
+
+	s.files[file] = content
+	s.revisions[file] = append(s.revisions[file], revision)
+
+	return nil
+}
+
+func (s *memorySource) Revision(_ context.Context, file string, version int64) (*gconfig.Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revisions := s.revisions[file]
+
+	if version <= 0 {
+		version = int64(len(revisions))
+	}
+
+	if version <= 0 || version > int64(len(revisions)) {
+		return nil, gerrors.ErrNotFoundConfigVersion
+	}
+
+	return revisions[version-1], nil
+}
+
+func (s *memorySource) Revisions(_ context.Context, file string) ([]*gconfig.Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*gconfig.Revision(nil), s.revisions[file]...), nil
+}
+
+func TestCommit(t *testing.T) {
+	ctx := context.Background()
+	source := newMemorySource()
+	c := gconfig.NewConfigurator(gconfig.WithSources(source))
+	defer c.Close()
+
+	r1, err := c.Commit(ctx, "memory", "game.yaml", "rate: 1\nmax: 10\n", gconfig.WithAuthor("alice"), gconfig.WithExpectVersion(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r1.Version != 1 || r1.Author != "alice" || r1.Diff != "@@ -1,0 +1,2 @@\n+rate: 1\n+max: 10\n" {
+		t.Fatalf("unexpected revision: %+v", r1)
+	}
+
+	if err = c.Store(ctx, "memory", "game.yaml", "rate: 2\nmax: 10\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 基于过期版本的提交被拒绝
+	_, err = c.Commit(ctx, "memory", "game.yaml", "rate: 3\nmax: 10\n", gconfig.WithAuthor("bob"), gconfig.WithExpectVersion(1))
+	if !gerrors.Is(err, gerrors.ErrConfigVersionConflict) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	diff, err := c.Diff(ctx, "memory", "game.yaml", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != "@@ -1,1 +1,1 @@\n-rate: 1\n+rate: 2\n" {
+		t.Fatalf("unexpected diff: %q", diff)
+	}
+
+	r3, err := c.Rollback(ctx, "memory", "game.yaml", 1, gconfig.WithAuthor("bob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r3.Version != 3 || r3.Checksum != r1.Checksum || r3.Comment != "rollback to version 1" {
+		t.Fatalf("unexpected revision: %+v", r3)
+	}
+
+	revisions, err := c.Revisions(ctx, "memory", "game.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(revisions) != 3 || string(source.files["game.yaml"]) != "rate: 1\nmax: 10\n" {
+		t.Fatalf("unexpected revisions: %d, content: %q", len(revisions), source.files["game.yaml"])
+	}
+}
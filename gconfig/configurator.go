@@ -3,6 +3,7 @@ package gconfig
 import (
 	"context"
 	"dario.cat/mergo"
+	"fmt"
 	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/gutils/gconv"
 	"github.com/goodluck0107/gcore/gutils/greflect"
@@ -16,6 +17,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type Configurator interface {
@@ -33,10 +35,24 @@ type Configurator interface {
 	Load(ctx context.Context, source string, file ...string) ([]*Configuration, error)
 	// Store 保存配置项
 	Store(ctx context.Context, source string, file string, content interface{}, override ...bool) error
+	// Commit 保存配置项并记录修订版本；配置源未实现 Versioner 时仅保存配置项并返回空修订记录
+	Commit(ctx context.Context, source string, file string, content interface{}, opts ...CommitOption) (*Revision, error)
+	// Revisions 获取配置项的修订记录列表
+	Revisions(ctx context.Context, source string, file string) ([]*Revision, error)
+	// Diff 比较配置项两个版本的差异
+	Diff(ctx context.Context, source string, file string, from, to int64) (string, error)
+	// Rollback 将配置项回滚到指定版本；回滚将作为新版本提交
+	Rollback(ctx context.Context, source string, file string, version int64, opts ...CommitOption) (*Revision, error)
 	// Close 关闭配置监听
 	Close()
 }
 
+// WatchCanceler 支持取消监听回调的配置器
+type WatchCanceler interface {
+	// WatchCancelable 设置监听回调；返回的函数用于取消监听
+	WatchCancelable(cb WatchCallbackFunc, names ...string) (cancel func())
+}
+
 type WatchCallbackFunc func(names ...string)
 
 type watcher struct {
@@ -201,12 +217,13 @@ func (c *defaultConfigurator) watch() {
 	}
 }
 
-// 通知给监听器
+// 通知给监听器；监听器列表以写时复制方式维护，回调前取快照并释放锁，以便在回调中设置或取消监听
 func (c *defaultConfigurator) notify(names ...string) {
 	c.rw.RLock()
-	defer c.rw.RUnlock()
+	watchers := c.watchers
+	c.rw.RUnlock()
 
-	for _, w := range c.watchers {
+	for _, w := range watchers {
 		if len(w.names) == 0 {
 			w.callback(names...)
 		} else {
@@ -447,6 +464,11 @@ func (c *defaultConfigurator) Set(pattern string, value interface{}) error {
 
 // Watch 设置监听回调
 func (c *defaultConfigurator) Watch(cb WatchCallbackFunc, names ...string) {
+	c.WatchCancelable(cb, names...)
+}
+
+// WatchCancelable 设置监听回调；返回的函数用于取消监听
+func (c *defaultConfigurator) WatchCancelable(cb WatchCallbackFunc, names ...string) func() {
 	w := &watcher{}
 	w.names = make(map[string]struct{}, len(names))
 	w.callback = cb
@@ -458,6 +480,18 @@ func (c *defaultConfigurator) Watch(cb WatchCallbackFunc, names ...string) {
 	c.rw.Lock()
 	c.watchers = append(c.watchers, w)
 	c.rw.Unlock()
+
+	return func() {
+		c.rw.Lock()
+		defer c.rw.Unlock()
+
+		for i, item := range c.watchers {
+			if item == w {
+				c.watchers = append(c.watchers[:i:i], c.watchers[i+1:]...)
+				return
+			}
+		}
+	}
 }
 
 // Load 加载配置项
@@ -481,15 +515,157 @@ func (c *defaultConfigurator) Load(ctx context.Context, source string, file ...s
 
 // Store 保存配置项
 func (c *defaultConfigurator) Store(ctx context.Context, source string, file string, content interface{}, override ...bool) error {
+	_, err := c.Commit(ctx, source, file, content, WithOverride(len(override) > 0 && override[0]))
+	return err
+}
+
+// Commit 保存配置项并记录修订版本
+func (c *defaultConfigurator) Commit(ctx context.Context, source string, file string, content interface{}, opts ...CommitOption) (*Revision, error) {
 	if content == nil {
-		return gerrors.ErrInvalidConfigContent
+		return nil, gerrors.ErrInvalidConfigContent
+	}
+
+	s, ok := c.sources[source]
+	if !ok {
+		return nil, gerrors.ErrNotFoundConfigSource
+	}
+
+	o := defaultCommitOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	buf, err := c.encode(file, content, o.override)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.commit(ctx, s, file, buf, o)
+}
+
+// Revisions 获取配置项的修订记录列表
+func (c *defaultConfigurator) Revisions(ctx context.Context, source string, file string) ([]*Revision, error) {
+	v, err := c.versioner(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Revisions(ctx, file)
+}
+
+// Diff 比较配置项两个版本的差异
+func (c *defaultConfigurator) Diff(ctx context.Context, source string, file string, from, to int64) (string, error) {
+	v, err := c.versioner(source)
+	if err != nil {
+		return "", err
+	}
+
+	r1, err := v.Revision(ctx, file, from)
+	if err != nil {
+		return "", err
+	}
+
+	r2, err := v.Revision(ctx, file, to)
+	if err != nil {
+		return "", err
+	}
+
+	return Diff(r1.Content, r2.Content), nil
+}
+
+// Rollback 将配置项回滚到指定版本
+func (c *defaultConfigurator) Rollback(ctx context.Context, source string, file string, version int64, opts ...CommitOption) (*Revision, error) {
+	v, err := c.versioner(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if version <= 0 {
+		return nil, gerrors.ErrNotFoundConfigVersion
+	}
+
+	r, err := v.Revision(ctx, file, version)
+	if err != nil {
+		return nil, err
+	}
+
+	o := defaultCommitOptions()
+	o.comment = fmt.Sprintf("rollback to version %d", version)
+	for _, opt := range opts {
+		opt(o)
 	}
 
+	return c.commit(ctx, c.sources[source], file, r.Content, o)
+}
+
+// 获取配置源的版本管理器
+func (c *defaultConfigurator) versioner(source string) (Versioner, error) {
 	s, ok := c.sources[source]
 	if !ok {
-		return gerrors.ErrNotFoundConfigSource
+		return nil, gerrors.ErrNotFoundConfigSource
+	}
+
+	v, ok := s.(Versioner)
+	if !ok {
+		return nil, gerrors.ErrNotSupportVersioning
 	}
 
+	return v, nil
+}
+
+// 提交配置内容
+func (c *defaultConfigurator) commit(ctx context.Context, s Source, file string, content []byte, o *commitOptions) (*Revision, error) {
+	v, ok := s.(Versioner)
+	if !ok {
+		if o.expect != AnyVersion {
+			return nil, gerrors.ErrNotSupportVersioning
+		}
+
+		return nil, s.Store(ctx, file, content)
+	}
+
+	var (
+		version int64
+		prev    []byte
+	)
+
+	head, err := v.Revision(ctx, file, 0)
+	switch {
+	case err == nil:
+		version, prev = head.Version, head.Content
+	case gerrors.Is(err, gerrors.ErrNotFoundConfigVersion):
+		// 尚无修订记录时以现有配置内容作为差异比较的基准
+		if configs, err := s.Load(ctx, file); err == nil && len(configs) > 0 {
+			prev = configs[0].Content
+		}
+	default:
+		return nil, err
+	}
+
+	if o.expect != AnyVersion && o.expect != version {
+		return nil, gerrors.NewError(fmt.Sprintf("expect version %d but current version is %d", o.expect, version), gerrors.ErrConfigVersionConflict)
+	}
+
+	revision := &Revision{
+		File:      file,
+		Version:   version + 1,
+		Author:    o.author,
+		Comment:   o.comment,
+		Checksum:  checksum(content),
+		Diff:      Diff(prev, content),
+		Content:   content,
+		CreatedAt: time.Now(),
+	}
+
+	if err = v.Commit(ctx, file, content, revision); err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// 编码配置内容
+func (c *defaultConfigurator) encode(file string, content interface{}, override bool) ([]byte, error) {
 	var (
 		err    error
 		buf    []byte
@@ -499,12 +675,12 @@ func (c *defaultConfigurator) Store(ctx context.Context, source string, file str
 
 	switch rk, _ := greflect.Value(content); rk {
 	case reflect.Map, reflect.Struct:
-		if len(override) > 0 && override[0] {
+		if override {
 			buf, err = c.opts.encoder(format, content)
 		} else {
 			dest, err := c.copy()
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			name := strings.TrimSuffix(filepath.Base(file), ext)
@@ -515,17 +691,17 @@ func (c *defaultConfigurator) Store(ctx context.Context, source string, file str
 			} else if v, ok := val.(map[string]interface{}); ok {
 				buf, err = c.opts.encoder(format, content)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
 				maps, err := c.opts.decoder(format, buf)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
 				err = mergo.Merge(&v, maps, mergo.WithOverride)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
 				buf, err = c.opts.encoder(format, v)
@@ -539,10 +715,10 @@ func (c *defaultConfigurator) Store(ctx context.Context, source string, file str
 		buf = gconv.Bytes(gconv.String(content))
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return s.Store(ctx, file, buf)
+	return buf, nil
 }
 
 func reviseKeys(keys []string, values map[string]interface{}) []string {
@@ -8,15 +8,17 @@ import (
 )
 
 const (
-	defaultAddr = "127.0.0.1:8500"
-	defaultPath = "config"
-	defaultMode = gconfig.ReadOnly
+	defaultAddr        = "127.0.0.1:8500"
+	defaultPath        = "config"
+	defaultMode        = gconfig.ReadOnly
+	defaultHistoryPath = "history"
 )
 
 const (
-	defaultAddrKey = "etc.config.consul.addr"
-	defaultPathKey = "etc.config.consul.path"
-	defaultModeKey = "etc.config.consul.mode"
+	defaultAddrKey        = "etc.config.consul.addr"
+	defaultPathKey        = "etc.config.consul.path"
+	defaultModeKey        = "etc.config.consul.mode"
+	defaultHistoryPathKey = "etc.config.consul.historyPath"
 )
 
 type Option func(o *options)
@@ -41,14 +43,19 @@ type options struct {
 	// 读写模式
 	// 支持read-only、write-only和read-write三种模式，默认为read-only模式
 	mode gconfig.Mode
+
+	// 修订记录路径
+	// 不可位于配置路径之下，默认为 history
+	historyPath string
 }
 
 func defaultOptions() *options {
 	return &options{
-		ctx:  context.Background(),
-		addr: getc.Get(defaultAddrKey, defaultAddr).String(),
-		path: getc.Get(defaultPathKey, defaultPath).String(),
-		mode: gconfig.Mode(getc.Get(defaultModeKey, defaultMode).String()),
+		ctx:         context.Background(),
+		addr:        getc.Get(defaultAddrKey, defaultAddr).String(),
+		path:        getc.Get(defaultPathKey, defaultPath).String(),
+		mode:        gconfig.Mode(getc.Get(defaultModeKey, defaultMode).String()),
+		historyPath: getc.Get(defaultHistoryPathKey, defaultHistoryPath).String(),
 	}
 }
 
@@ -76,3 +83,8 @@ func WithPath(path string) Option {
 func WithMode(mode gconfig.Mode) Option {
 	return func(o *options) { o.mode = mode }
 }
+
+// WithHistoryPath 设置修订记录路径
+func WithHistoryPath(path string) Option {
+	return func(o *options) { o.historyPath = path }
+}
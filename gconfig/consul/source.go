@@ -2,14 +2,20 @@ package consul
 
 import (
 	"context"
+	"fmt"
 	"github.com/goodluck0107/gcore/gconfig"
+	"github.com/goodluck0107/gcore/gencoding/json"
+	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/hashicorp/consul/api"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 const Name = "consul"
 
+var _ gconfig.Versioner = &Source{}
+
 type Source struct {
 	err  error
 	opts *options
@@ -24,6 +30,7 @@ func NewSource(opts ...Option) gconfig.Source {
 	s := &Source{}
 	s.opts = o
 	s.opts.path = strings.TrimSuffix(strings.TrimPrefix(s.opts.path, "/"), "/")
+	s.opts.historyPath = strings.TrimSuffix(strings.TrimPrefix(s.opts.historyPath, "/"), "/")
 
 	if o.client == nil {
 		c := api.DefaultConfig()
@@ -108,6 +115,115 @@ func (s *Source) Store(ctx context.Context, file string, content []byte) error {
 	return err
 }
 
+// Commit 保存配置项并追加修订记录
+func (s *Source) Commit(ctx context.Context, file string, content []byte, revision *gconfig.Revision) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.opts.mode != gconfig.WriteOnly && s.opts.mode != gconfig.ReadWrite {
+		return gerrors.ErrNoOperationPermission
+	}
+
+	buf, err := json.Marshal(revision)
+	if err != nil {
+		return err
+	}
+
+	var key string
+
+	if s.opts.path != "" {
+		key = s.opts.path + "/" + strings.TrimPrefix(file, "/")
+	} else {
+		key = strings.TrimPrefix(file, "/")
+	}
+
+	// 索引为0的CAS操作仅在键不存在时写入；新版本的修订记录已存在时说明当前版本已被其他提交更新
+	ok, _, _, err := s.opts.client.Txn().Txn(api.TxnOps{
+		&api.TxnOp{KV: &api.KVTxnOp{Verb: api.KVCAS, Key: s.revisionKey(file, revision.Version), Value: buf, Index: 0}},
+		&api.TxnOp{KV: &api.KVTxnOp{Verb: api.KVSet, Key: key, Value: content}},
+	}, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return gerrors.NewError(fmt.Sprintf("version %d of %s already exists", revision.Version, file), gerrors.ErrConfigVersionConflict)
+	}
+
+	return nil
+}
+
+// Revision 获取指定版本的修订记录
+func (s *Source) Revision(ctx context.Context, file string, version int64) (*gconfig.Revision, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	key := s.revisionKey(file, version)
+
+	if version <= 0 {
+		keys, _, err := s.opts.client.KV().Keys(s.revisionPrefix(file), "", (&api.QueryOptions{}).WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(keys) == 0 {
+			return nil, gerrors.ErrNotFoundConfigVersion
+		}
+
+		sort.Strings(keys)
+
+		key = keys[len(keys)-1]
+	}
+
+	kv, _, err := s.opts.client.KV().Get(key, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if kv == nil {
+		return nil, gerrors.ErrNotFoundConfigVersion
+	}
+
+	revision := &gconfig.Revision{}
+
+	if err = json.Unmarshal(kv.Value, revision); err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// Revisions 获取修订记录列表
+func (s *Source) Revisions(ctx context.Context, file string) ([]*gconfig.Revision, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	kvs, _, err := s.opts.client.KV().List(s.revisionPrefix(file), (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+
+	revisions := make([]*gconfig.Revision, 0, len(kvs))
+	for _, kv := range kvs {
+		revision := &gconfig.Revision{}
+
+		if err = json.Unmarshal(kv.Value, revision); err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
 // Watch 监听配置项
 func (s *Source) Watch(ctx context.Context) (gconfig.Watcher, error) {
 	if s.err != nil {
@@ -121,3 +237,13 @@ func (s *Source) Watch(ctx context.Context) (gconfig.Watcher, error) {
 func (s *Source) Close() error {
 	return nil
 }
+
+// 修订记录键前缀
+func (s *Source) revisionPrefix(file string) string {
+	return s.opts.historyPath + "/" + strings.TrimPrefix(file, "/") + "@"
+}
+
+// 修订记录键；版本号补零以保证按键排序与按版本号排序一致
+func (s *Source) revisionKey(file string, version int64) string {
+	return fmt.Sprintf("%s%020d", s.revisionPrefix(file), version)
+}
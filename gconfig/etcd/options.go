@@ -12,6 +12,7 @@ const (
 	defaultDialTimeout = "5s"
 	defaultPath        = "/config"
 	defaultMode        = gconfig.ReadOnly
+	defaultHistoryPath = "/history"
 )
 
 const (
@@ -19,6 +20,7 @@ const (
 	defaultDialTimeoutKey = "etc.config.etcd.dialTimeout"
 	defaultPathKey        = "etc.config.etcd.path"
 	defaultModeKey        = "etc.config.etcd.mode"
+	defaultHistoryPathKey = "etc.config.etcd.historyPath"
 )
 
 type Option func(o *options)
@@ -43,6 +45,10 @@ type options struct {
 	// 读写模式
 	// 支持read-only、write-only和read-write三种模式，默认为read-only模式
 	mode gconfig.Mode
+
+	// 修订记录路径
+	// 不可位于配置路径之下，默认为 /history
+	historyPath string
 }
 
 func defaultOptions() *options {
@@ -51,6 +57,7 @@ func defaultOptions() *options {
 		dialTimeout: getc.Get(defaultDialTimeoutKey, defaultDialTimeout).Duration(),
 		path:        getc.Get(defaultPathKey, defaultPath).String(),
 		mode:        gconfig.Mode(getc.Get(defaultModeKey, defaultMode).String()),
+		historyPath: getc.Get(defaultHistoryPathKey, defaultHistoryPath).String(),
 	}
 }
 
@@ -78,3 +85,8 @@ func WithPath(path string) Option {
 func WithMode(mode gconfig.Mode) Option {
 	return func(o *options) { o.mode = mode }
 }
+
+// WithHistoryPath 设置修订记录路径
+func WithHistoryPath(path string) Option {
+	return func(o *options) { o.historyPath = path }
+}
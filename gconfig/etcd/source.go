@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/goodluck0107/gcore/gconfig"
+	"github.com/goodluck0107/gcore/gencoding/json"
 	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/gutils/gconv"
 	"go.etcd.io/etcd/client/v3"
@@ -13,6 +14,8 @@ import (
 
 const Name = "etcd"
 
+var _ gconfig.Versioner = &Source{}
+
 type Source struct {
 	err     error
 	opts    *options
@@ -28,6 +31,7 @@ func NewSource(opts ...Option) gconfig.Source {
 	s := &Source{}
 	s.opts = o
 	s.opts.path = fmt.Sprintf("/%s", strings.TrimSuffix(strings.TrimPrefix(s.opts.path, "/"), "/"))
+	s.opts.historyPath = fmt.Sprintf("/%s", strings.TrimSuffix(strings.TrimPrefix(s.opts.historyPath, "/"), "/"))
 
 	if o.client == nil {
 		s.builtin = true
@@ -101,6 +105,100 @@ func (s *Source) Store(ctx context.Context, file string, content []byte) error {
 	return err
 }
 
+// Commit 保存配置项并追加修订记录
+func (s *Source) Commit(ctx context.Context, file string, content []byte, revision *gconfig.Revision) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.opts.mode != gconfig.WriteOnly && s.opts.mode != gconfig.ReadWrite {
+		return gerrors.ErrNoOperationPermission
+	}
+
+	buf, err := json.Marshal(revision)
+	if err != nil {
+		return err
+	}
+
+	key := s.opts.path + "/" + strings.TrimPrefix(file, "/")
+	rkey := s.revisionKey(file, revision.Version)
+
+	// 新版本的修订记录已存在时说明当前版本已被其他提交更新
+	res, err := s.opts.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(rkey), "=", 0)).
+		Then(clientv3.OpPut(key, gconv.String(content)), clientv3.OpPut(rkey, gconv.String(buf))).
+		Commit()
+	if err != nil {
+		return err
+	}
+
+	if !res.Succeeded {
+		return gerrors.NewError(fmt.Sprintf("version %d of %s already exists", revision.Version, file), gerrors.ErrConfigVersionConflict)
+	}
+
+	return nil
+}
+
+// Revision 获取指定版本的修订记录
+func (s *Source) Revision(ctx context.Context, file string, version int64) (*gconfig.Revision, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	var (
+		res *clientv3.GetResponse
+		err error
+	)
+
+	if version > 0 {
+		res, err = s.opts.client.Get(ctx, s.revisionKey(file, version))
+	} else {
+		res, err = s.opts.client.Get(ctx, s.revisionPrefix(file), clientv3.WithPrefix(),
+			clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend), clientv3.WithLimit(1))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Kvs) == 0 {
+		return nil, gerrors.ErrNotFoundConfigVersion
+	}
+
+	revision := &gconfig.Revision{}
+
+	if err = json.Unmarshal(res.Kvs[0].Value, revision); err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// Revisions 获取修订记录列表
+func (s *Source) Revisions(ctx context.Context, file string) ([]*gconfig.Revision, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	res, err := s.opts.client.Get(ctx, s.revisionPrefix(file), clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*gconfig.Revision, 0, len(res.Kvs))
+	for _, kv := range res.Kvs {
+		revision := &gconfig.Revision{}
+
+		if err = json.Unmarshal(kv.Value, revision); err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
 // Watch 监听配置项
 func (s *Source) Watch(ctx context.Context) (gconfig.Watcher, error) {
 	if s.err != nil {
@@ -122,3 +220,13 @@ func (s *Source) Close() error {
 
 	return nil
 }
+
+// 修订记录键前缀
+func (s *Source) revisionPrefix(file string) string {
+	return s.opts.historyPath + "/" + strings.TrimPrefix(file, "/") + "@"
+}
+
+// 修订记录键；版本号补零以保证按键排序与按版本号排序一致
+func (s *Source) revisionKey(file string, version int64) string {
+	return fmt.Sprintf("%s%020d", s.revisionPrefix(file), version)
+}
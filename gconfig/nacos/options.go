@@ -25,6 +25,7 @@ const (
 	defaultPassword    = ""
 	defaultLogDir      = "./run/nacos/config/log"
 	defaultLogLevel    = "info"
+	defaultHistoryName = "HISTORY_GROUP"
 )
 
 const (
@@ -44,6 +45,7 @@ const (
 	defaultPasswordKey    = "etc.config.nacos.password"
 	defaultLogDirKey      = "etc.config.nacos.logDir"
 	defaultLogLevelKey    = "etc.config.nacos.logLevel"
+	defaultHistoryNameKey = "etc.config.nacos.historyGroupName"
 )
 
 type Option func(o *options)
@@ -121,27 +123,32 @@ type options struct {
 	// 日志输出级别
 	// 默认为info
 	logLevel string
+
+	// 修订记录群组名称
+	// 不可与配置群组相同，默认为HISTORY_GROUP
+	historyGroupName string
 }
 
 func defaultOptions() *options {
 	return &options{
-		ctx:         context.Background(),
-		mode:        gconfig.Mode(getc.Get(defaultModeKey, defaultMode).String()),
-		urls:        getc.Get(defaultUrlsKey, []string{defaultUrl}).Strings(),
-		clusterName: getc.Get(defaultClusterNameKey, defaultClusterName).String(),
-		groupName:   getc.Get(defaultGroupNameKey, defaultGroupName).String(),
-		timeout:     getc.Get(defaultTimeoutKey, defaultTimeout).Duration(),
-		namespaceId: getc.Get(defaultNamespaceIdKey, defaultNamespaceId).String(),
-		endpoint:    getc.Get(defaultEndpointKey, defaultEndpoint).String(),
-		regionId:    getc.Get(defaultRegionIdKey, defaultRegionId).String(),
-		accessKey:   getc.Get(defaultAccessKeyKey, defaultAccessKey).String(),
-		secretKey:   getc.Get(defaultSecretKeyKey, defaultSecretKey).String(),
-		openKMS:     getc.Get(defaultOpenKMSKey, defaultOpenKMS).Bool(),
-		cacheDir:    getc.Get(defaultCacheDirKey, defaultCacheDir).String(),
-		username:    getc.Get(defaultUsernameKey, defaultUsername).String(),
-		password:    getc.Get(defaultPasswordKey, defaultPassword).String(),
-		logDir:      getc.Get(defaultLogDirKey, defaultLogDir).String(),
-		logLevel:    getc.Get(defaultLogLevelKey, defaultLogLevel).String(),
+		ctx:              context.Background(),
+		mode:             gconfig.Mode(getc.Get(defaultModeKey, defaultMode).String()),
+		urls:             getc.Get(defaultUrlsKey, []string{defaultUrl}).Strings(),
+		clusterName:      getc.Get(defaultClusterNameKey, defaultClusterName).String(),
+		groupName:        getc.Get(defaultGroupNameKey, defaultGroupName).String(),
+		timeout:          getc.Get(defaultTimeoutKey, defaultTimeout).Duration(),
+		namespaceId:      getc.Get(defaultNamespaceIdKey, defaultNamespaceId).String(),
+		endpoint:         getc.Get(defaultEndpointKey, defaultEndpoint).String(),
+		regionId:         getc.Get(defaultRegionIdKey, defaultRegionId).String(),
+		accessKey:        getc.Get(defaultAccessKeyKey, defaultAccessKey).String(),
+		secretKey:        getc.Get(defaultSecretKeyKey, defaultSecretKey).String(),
+		openKMS:          getc.Get(defaultOpenKMSKey, defaultOpenKMS).Bool(),
+		cacheDir:         getc.Get(defaultCacheDirKey, defaultCacheDir).String(),
+		username:         getc.Get(defaultUsernameKey, defaultUsername).String(),
+		password:         getc.Get(defaultPasswordKey, defaultPassword).String(),
+		logDir:           getc.Get(defaultLogDirKey, defaultLogDir).String(),
+		logLevel:         getc.Get(defaultLogLevelKey, defaultLogLevel).String(),
+		historyGroupName: getc.Get(defaultHistoryNameKey, defaultHistoryName).String(),
 	}
 }
 
@@ -234,3 +241,8 @@ func WithLogDir(logDir string) Option {
 func WithLogLevel(logLevel string) Option {
 	return func(o *options) { o.logLevel = logLevel }
 }
+
+// WithHistoryGroupName 设置修订记录群组名称
+func WithHistoryGroupName(historyGroupName string) Option {
+	return func(o *options) { o.historyGroupName = historyGroupName }
+}
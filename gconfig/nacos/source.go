@@ -2,7 +2,11 @@ package nacos
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
 	"github.com/goodluck0107/gcore/gconfig"
+	"github.com/goodluck0107/gcore/gencoding/json"
 	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/glog"
 	"github.com/nacos-group/nacos-sdk-go/v2/clients"
@@ -20,6 +24,11 @@ import (
 
 const Name = "nacos"
 
+// 提交超时时间；修订记录创建后超过该时间仍未推进版本号的提交视为已中断
+const commitTimeout = time.Minute
+
+var _ gconfig.Versioner = &Source{}
+
 type Source struct {
 	err      error
 	opts     *options
@@ -141,6 +150,202 @@ func (s *Source) Store(ctx context.Context, file string, content []byte) error {
 	return nil
 }
 
+// Commit 保存配置项并追加修订记录
+// 当前版本号保存在修订记录群组中与配置项同名的dataId下；提交时先以CAS创建修订记录以独占新版本号，
+// 再写入配置内容，最后通过CAS推进版本号，保证版本号始终指向已完整写入的修订记录
+func (s *Source) Commit(ctx context.Context, file string, content []byte, revision *gconfig.Revision) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.opts.mode != gconfig.WriteOnly && s.opts.mode != gconfig.ReadWrite {
+		return gerrors.ErrNoOperationPermission
+	}
+
+	head, version, err := s.head(file)
+	if err != nil {
+		return err
+	}
+
+	if version != revision.Version-1 {
+		return s.conflict(file, revision.Version)
+	}
+
+	buf, err := json.Marshal(revision)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.opts.client.PublishConfig(vo.ConfigParam{
+		DataId:  s.revisionId(file, revision.Version),
+		Group:   s.opts.historyGroupName,
+		Content: string(buf),
+		CasMd5:  md5sum(""),
+	})
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		s.recover(ctx, file, head, revision.Version)
+
+		return s.conflict(file, revision.Version)
+	}
+
+	if err = s.Store(ctx, file, content); err != nil {
+		return err
+	}
+
+	return s.advance(file, head, revision.Version)
+}
+
+// Revision 获取指定版本的修订记录
+func (s *Source) Revision(ctx context.Context, file string, version int64) (*gconfig.Revision, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if version <= 0 {
+		_, head, err := s.head(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if head == 0 {
+			return nil, gerrors.ErrNotFoundConfigVersion
+		}
+
+		version = head
+	}
+
+	return s.revision(file, version)
+}
+
+// Revisions 获取修订记录列表
+func (s *Source) Revisions(ctx context.Context, file string) ([]*gconfig.Revision, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	_, head, err := s.head(file)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*gconfig.Revision, 0, head)
+	for version := int64(1); version <= head; version++ {
+		revision, err := s.revision(file, version)
+		if err != nil {
+			if gerrors.Is(err, gerrors.ErrNotFoundConfigVersion) {
+				continue
+			}
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+// 获取当前版本号
+func (s *Source) head(file string) (string, int64, error) {
+	content, err := s.opts.client.GetConfig(vo.ConfigParam{
+		DataId: file,
+		Group:  s.opts.historyGroupName,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	if content == "" {
+		return "", 0, nil
+	}
+
+	version, err := strconv.ParseInt(content, 10, 64)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return content, version, nil
+}
+
+// 通过CAS推进版本号；版本号尚不存在时以空内容作为CAS基准，防止并发创建相互覆盖
+func (s *Source) advance(file string, head string, version int64) error {
+	ok, err := s.opts.client.PublishConfig(vo.ConfigParam{
+		DataId:  file,
+		Group:   s.opts.historyGroupName,
+		Content: strconv.FormatInt(version, 10),
+		CasMd5:  md5sum(head),
+	})
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return s.conflict(file, version)
+	}
+
+	return nil
+}
+
+// 恢复中断的提交；修订记录已创建但版本号未推进且已超过提交超时时间时，视为提交方已中断，
+// 由当前提交方补写配置内容并推进版本号，避免该版本号被永久占用
+func (s *Source) recover(ctx context.Context, file string, head string, version int64) {
+	revision, err := s.revision(file, version)
+	if err != nil || time.Since(revision.CreatedAt) < commitTimeout {
+		return
+	}
+
+	if err = s.Store(ctx, file, revision.Content); err != nil {
+		glog.Warnf("recover interrupted commit failed, file: %s version: %d err: %v", file, version, err)
+		return
+	}
+
+	if err = s.advance(file, head, version); err != nil {
+		glog.Warnf("recover interrupted commit failed, file: %s version: %d err: %v", file, version, err)
+	}
+}
+
+// 版本冲突错误
+func (s *Source) conflict(file string, version int64) error {
+	return gerrors.NewError(fmt.Sprintf("version %d of %s already exists", version, file), gerrors.ErrConfigVersionConflict)
+}
+
+// 获取修订记录
+func (s *Source) revision(file string, version int64) (*gconfig.Revision, error) {
+	content, err := s.opts.client.GetConfig(vo.ConfigParam{
+		DataId: s.revisionId(file, version),
+		Group:  s.opts.historyGroupName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if content == "" {
+		return nil, gerrors.ErrNotFoundConfigVersion
+	}
+
+	revision := &gconfig.Revision{}
+
+	if err = json.Unmarshal([]byte(content), revision); err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// 计算内容的MD5值，用作CAS基准
+func md5sum(content string) string {
+	sum := md5.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// 修订记录dataId
+func (s *Source) revisionId(file string, version int64) string {
+	return fmt.Sprintf("%s.%020d", file, version)
+}
+
 // Watch 监听配置项
 func (s *Source) Watch(ctx context.Context) (gconfig.Watcher, error) {
 	if s.err != nil {
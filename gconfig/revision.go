@@ -0,0 +1,266 @@
+package gconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// AnyVersion 不校验当前版本
+const AnyVersion int64 = -1
+
+// Revision 配置修订记录
+type Revision struct {
+	File      string    `json:"file"`      // 文件全称
+	Version   int64     `json:"version"`   // 版本号；从1开始单调递增
+	Author    string    `json:"author"`    // 修订人
+	Comment   string    `json:"comment"`   // 修订说明
+	Checksum  string    `json:"checksum"`  // 内容校验和（sha256）
+	Diff      string    `json:"diff"`      // 相对上一版本的差异
+	Content   []byte    `json:"content"`   // 修订后的完整内容
+	CreatedAt time.Time `json:"createdAt"` // 修订时间
+}
+
+// Versioner 配置版本管理；由支持版本管理的配置源实现，修订记录与配置项保存在同一后端
+type Versioner interface {
+	// Commit 保存配置项并追加修订记录
+	// 须原子地校验当前版本号等于 revision.Version-1，否则返回 gerrors.ErrConfigVersionConflict
+	Commit(ctx context.Context, file string, content []byte, revision *Revision) error
+	// Revision 获取指定版本的修订记录；version 小于等于0时获取最新版本，不存在时返回 gerrors.ErrNotFoundConfigVersion
+	Revision(ctx context.Context, file string, version int64) (*Revision, error)
+	// Revisions 获取修订记录列表；按版本号升序排列
+	Revisions(ctx context.Context, file string) ([]*Revision, error)
+}
+
+type CommitOption func(o *commitOptions)
+
+type commitOptions struct {
+	override bool   // 是否覆盖原有配置，默认与原有配置合并
+	author   string // 修订人，默认为当前主机名
+	comment  string // 修订说明
+	expect   int64  // 期望的当前版本号，默认为 AnyVersion
+}
+
+func defaultCommitOptions() *commitOptions {
+	author, _ := os.Hostname()
+
+	return &commitOptions{
+		author: author,
+		expect: AnyVersion,
+	}
+}
+
+// WithOverride 设置是否覆盖原有配置
+func WithOverride(override bool) CommitOption {
+	return func(o *commitOptions) { o.override = override }
+}
+
+// WithAuthor 设置修订人
+func WithAuthor(author string) CommitOption {
+	return func(o *commitOptions) { o.author = author }
+}
+
+// WithComment 设置修订说明
+func WithComment(comment string) CommitOption {
+	return func(o *commitOptions) { o.comment = comment }
+}
+
+// WithExpectVersion 设置期望的当前版本号；当前版本号不一致时提交失败，版本号为0时表示配置项尚无修订记录
+func WithExpectVersion(version int64) CommitOption {
+	return func(o *commitOptions) { o.expect = version }
+}
+
+// 计算内容校验和
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Diff 按行比较内容差异；输出不带上下文的统一差异格式
+// 行内容先映射为整数标识，再通过 Myers 算法求最短编辑脚本，时间复杂度为 O((N+M)D)
+func Diff(old, new []byte) string {
+	a, b := splitLines(old), splitLines(new)
+
+	// 跳过相同的首尾行以缩小比较范围
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	var (
+		sb      strings.Builder
+		removed []string
+		added   []string
+		start   [2]int
+	)
+
+	flush := func() {
+		if len(removed) == 0 && len(added) == 0 {
+			return
+		}
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", start[0]+prefix+1, len(removed), start[1]+prefix+1, len(added))
+
+		for _, line := range removed {
+			sb.WriteString("-" + line + "\n")
+		}
+
+		for _, line := range added {
+			sb.WriteString("+" + line + "\n")
+		}
+
+		removed, added = removed[:0], added[:0]
+	}
+
+	i, j := 0, 0
+	for _, op := range editScript(hashLines(a, b)) {
+		if op != opEqual && len(removed) == 0 && len(added) == 0 {
+			start = [2]int{i, j}
+		}
+
+		switch op {
+		case opEqual:
+			flush()
+			i++
+			j++
+		case opDelete:
+			removed = append(removed, a[i])
+			i++
+		case opInsert:
+			added = append(added, b[j])
+			j++
+		}
+	}
+
+	flush()
+
+	return sb.String()
+}
+
+const (
+	opEqual  = iota // 相同行
+	opDelete        // 删除行
+	opInsert        // 插入行
+)
+
+// 将行内容映射为整数标识，避免比较过程中重复比较字符串
+func hashLines(a, b []string) ([]int, []int) {
+	ids := make(map[string]int, len(a)+len(b))
+
+	hash := func(lines []string) []int {
+		x := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+			x[i] = id
+		}
+		return x
+	}
+
+	return hash(a), hash(b)
+}
+
+// 通过 Myers 算法计算最短编辑脚本
+// 每轮仅保留对角线 [-d-1, d+1] 范围内的最远到达位置用于回溯，空间复杂度为 O(D²)
+func editScript(a, b []int) []int {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	trace := make([][]int, 0)
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+
+	return nil
+}
+
+// 根据每轮的最远到达位置回溯出编辑脚本
+func backtrack(trace [][]int, x, y int) []int {
+	ops := make([]int, 0, x+y)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		get := func(k int) int { return v[k+d+1] }
+
+		k := x - y
+
+		var prev int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prev = k + 1
+		} else {
+			prev = k - 1
+		}
+
+		px := get(prev)
+		py := px - prev
+
+		for x > px && y > py {
+			ops = append(ops, opEqual)
+			x--
+			y--
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == px {
+			ops = append(ops, opInsert)
+			y--
+		} else {
+			ops = append(ops, opDelete)
+			x--
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// 按行拆分内容
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
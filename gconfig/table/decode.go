@@ -0,0 +1,172 @@
+package table
+
+import (
+	"fmt"
+	"github.com/goodluck0107/gcore/gencoding/json"
+	"github.com/goodluck0107/gcore/gerrors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const tagName = "table"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// 将表格行解码为行结构体；首个非注释行为表头，按 table 标签或字段名（忽略大小写）与列名匹配
+func decodeRows[T any](rows [][]string, comment, separator string) ([]*T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, gerrors.New("table row must be a struct")
+	}
+
+	var (
+		header  []int
+		records = make([]*T, 0, len(rows))
+	)
+
+	for line, row := range rows {
+		if isBlankRow(row) || (comment != "" && strings.HasPrefix(strings.TrimSpace(row[0]), comment)) {
+			continue
+		}
+
+		if header == nil {
+			header = mapHeader(typ, row)
+			continue
+		}
+
+		record := new(T)
+		value := reflect.ValueOf(record).Elem()
+
+		for col, field := range header {
+			if field < 0 || col >= len(row) {
+				continue
+			}
+
+			cell := strings.TrimSpace(row[col])
+			if cell == "" {
+				continue
+			}
+
+			if err := setValue(value.Field(field), cell, separator); err != nil {
+				return nil, gerrors.NewError(fmt.Sprintf("line %d field %s", line+1, typ.Field(field).Name), err)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// 映射表头列到结构体字段序号；未匹配的列为-1
+func mapHeader(typ reflect.Type, row []string) []int {
+	fields := make(map[string]int, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			if tag == "-" {
+				continue
+			}
+
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fields[strings.ToLower(name)] = i
+	}
+
+	header := make([]int, len(row))
+	for col, name := range row {
+		if i, ok := fields[strings.ToLower(strings.TrimSpace(name))]; ok {
+			header[col] = i
+		} else {
+			header[col] = -1
+		}
+	}
+
+	return header
+}
+
+// 设置字段值
+func setValue(v reflect.Value, cell string, separator string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), cell, separator)
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(cell)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(cell, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(cell, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if strings.HasPrefix(cell, "[") {
+			return json.Unmarshal([]byte(cell), v.Addr().Interface())
+		}
+
+		items := strings.Split(cell, separator)
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), strings.TrimSpace(item), separator); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return json.Unmarshal([]byte(cell), v.Addr().Interface())
+	}
+
+	return nil
+}
+
+// 检测是否为空行
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,70 @@
+package table
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gconfig"
+	"github.com/goodluck0107/gcore/gconfig/file/core"
+	"github.com/goodluck0107/gcore/getc"
+)
+
+const (
+	defaultPath      = "./table"
+	defaultSeparator = "|"
+	defaultComment   = "#"
+)
+
+const (
+	defaultPathKey      = "etc.config.table.path"
+	defaultSeparatorKey = "etc.config.table.separator"
+	defaultCommentKey   = "etc.config.table.comment"
+)
+
+type Option func(o *options)
+
+type options struct {
+	// 上下文
+	ctx context.Context
+
+	// 配置表数据源
+	// 默认为读取 ./table 目录的文件配置源
+	source gconfig.Source
+
+	// 单元格内切片元素的分隔符，默认为 |
+	separator string
+
+	// 注释行前缀；首列以该前缀开头的行不作为数据行，默认为 #
+	comment string
+}
+
+func defaultOptions() *options {
+	return &options{
+		ctx:       context.Background(),
+		separator: getc.Get(defaultSeparatorKey, defaultSeparator).String(),
+		comment:   getc.Get(defaultCommentKey, defaultComment).String(),
+	}
+}
+
+// 默认配置表数据源
+func defaultSource() gconfig.Source {
+	return core.NewSource(getc.Get(defaultPathKey, defaultPath).String(), gconfig.ReadOnly)
+}
+
+// WithContext 设置上下文
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithSource 设置配置表数据源
+func WithSource(source gconfig.Source) Option {
+	return func(o *options) { o.source = source }
+}
+
+// WithSeparator 设置单元格内切片元素的分隔符
+func WithSeparator(separator string) Option {
+	return func(o *options) { o.separator = separator }
+}
+
+// WithComment 设置注释行前缀
+func WithComment(comment string) Option {
+	return func(o *options) { o.comment = comment }
+}
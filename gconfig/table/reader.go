@@ -0,0 +1,229 @@
+package table
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"github.com/goodluck0107/gcore/gerrors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// 读取表格内容；返回的每一行均为单元格文本
+func readRows(format string, content []byte) ([][]string, error) {
+	switch strings.ToLower(format) {
+	case CSV:
+		return readCSV(content)
+	case XLSX:
+		return readXLSX(content)
+	default:
+		return nil, gerrors.ErrInvalidFormat
+	}
+}
+
+// 读取CSV内容；兼容Excel导出时携带的BOM头
+func readCSV(content []byte) ([][]string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	return reader.ReadAll()
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t *xlsxText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+
+	sb := strings.Builder{}
+	for _, r := range t.R {
+		sb.WriteString(r.T)
+	}
+
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref  string    `xml:"r,attr"`
+			Type string    `xml:"t,attr"`
+			V    string    `xml:"v"`
+			Is   *xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// 读取XLSX内容；仅读取工作簿中的首个工作表
+func readXLSX(content []byte) ([][]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, f := range reader.File {
+		files[f.Name] = f
+	}
+
+	sheet, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := &xlsxSharedStrings{}
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err = decodeXML(f, shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheet]
+	if !ok {
+		return nil, gerrors.New("not found xlsx worksheet: " + sheet)
+	}
+
+	worksheet := &xlsxWorksheet{}
+	if err = decodeXML(f, worksheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(worksheet.Rows))
+	for _, r := range worksheet.Rows {
+		row := make([]string, 0, len(r.Cells))
+
+		for i, c := range r.Cells {
+			col := i
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+
+			for len(row) < col {
+				row = append(row, "")
+			}
+
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(c.V)
+				if err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, gerrors.New("invalid xlsx shared string index: " + c.V)
+				}
+				row = append(row, shared.Items[idx].String())
+			case "inlineStr":
+				if c.Is != nil {
+					row = append(row, c.Is.String())
+				} else {
+					row = append(row, "")
+				}
+			default:
+				row = append(row, c.V)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// 获取首个工作表的文件路径
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", gerrors.New("not found xlsx workbook")
+	}
+
+	workbook := &xlsxWorkbook{}
+	if err := decodeXML(f, workbook); err != nil {
+		return "", err
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return "", gerrors.New("not found xlsx worksheet")
+	}
+
+	if f, ok = files["xl/_rels/workbook.xml.rels"]; ok {
+		rels := &xlsxRelationships{}
+		if err := decodeXML(f, rels); err != nil {
+			return "", err
+		}
+
+		for _, rel := range rels.Relationships {
+			if rel.ID != workbook.Sheets[0].RID {
+				continue
+			}
+
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+// 解码压缩包内的XML文件
+func decodeXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+
+	return xml.Unmarshal(data, v)
+}
+
+// 将单元格引用（如 AB12）转换为从0开始的列序号
+func columnIndex(ref string) int {
+	col := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A') + 1
+	}
+
+	return col - 1
+}
@@ -0,0 +1,210 @@
+package table
+
+import (
+	"fmt"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/glog"
+)
+
+// 配置表定义；由泛型配置表实现，供配置表集合统一解析与校验
+type definition interface {
+	// 解析表格内容
+	parse(rows [][]string) (snapshot, error)
+	// 校验跨表引用
+	check(self snapshot, set map[string]snapshot) error
+}
+
+// 配置表快照
+type snapshot interface {
+	// 检测主键是否存在
+	has(key any) bool
+}
+
+type reference[T any] struct {
+	target string             // 被引用的配置表名称
+	keys   func(row *T) []any // 引用的主键；零值等无效引用应由调用方过滤
+}
+
+type Table[K comparable, T any] struct {
+	tables  *Tables
+	name    string
+	key     func(row *T) K
+	indexes map[string]func(row *T) any
+	refs    []reference[T]
+}
+
+type data[K comparable, T any] struct {
+	rows    []*T
+	primary map[K]*T
+	indexes map[string]map[any][]*T
+}
+
+// NewTable 定义配置表；name 为表格文件名（不含扩展名），key 为主键提取函数
+// 配置表须在配置表集合加载前定义
+func NewTable[K comparable, T any](tables *Tables, name string, key func(row *T) K) *Table[K, T] {
+	t := &Table[K, T]{
+		tables:  tables,
+		name:    name,
+		key:     key,
+		indexes: make(map[string]func(row *T) any),
+	}
+
+	tables.define(name, t)
+
+	return t
+}
+
+// Name 配置表名称
+func (t *Table[K, T]) Name() string {
+	return t.name
+}
+
+// AddReference 添加跨表引用；加载时校验 keys 返回的主键均存在于 target 配置表中
+func (t *Table[K, T]) AddReference(target string, keys func(row *T) []any) *Table[K, T] {
+	t.refs = append(t.refs, reference[T]{target: target, keys: keys})
+
+	return t
+}
+
+// Get 通过主键获取行
+func (t *Table[K, T]) Get(key K) (*T, bool) {
+	d := t.load()
+	if d == nil {
+		return nil, false
+	}
+
+	row, ok := d.primary[key]
+
+	return row, ok
+}
+
+// All 获取所有行；顺序与表格中的行顺序一致
+func (t *Table[K, T]) All() []*T {
+	if d := t.load(); d != nil {
+		return d.rows
+	}
+
+	return nil
+}
+
+// Len 获取行数
+func (t *Table[K, T]) Len() int {
+	return len(t.All())
+}
+
+// 加载当前快照
+func (t *Table[K, T]) load() *data[K, T] {
+	if s, ok := t.tables.load(t.name); ok {
+		return s.(*data[K, T])
+	}
+
+	return nil
+}
+
+// 解析表格内容
+func (t *Table[K, T]) parse(rows [][]string) (snapshot, error) {
+	records, err := decodeRows[T](rows, t.tables.opts.comment, t.tables.opts.separator)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &data[K, T]{
+		rows:    records,
+		primary: make(map[K]*T, len(records)),
+		indexes: make(map[string]map[any][]*T, len(t.indexes)),
+	}
+
+	for _, row := range records {
+		key := t.key(row)
+
+		if _, ok := d.primary[key]; ok {
+			return nil, gerrors.NewError(fmt.Sprintf("key %v", key), ErrDuplicateKey)
+		}
+
+		d.primary[key] = row
+	}
+
+	for name, fn := range t.indexes {
+		index := make(map[any][]*T)
+
+		for _, row := range records {
+			v := fn(row)
+			index[v] = append(index[v], row)
+		}
+
+		d.indexes[name] = index
+	}
+
+	return d, nil
+}
+
+// 校验跨表引用
+func (t *Table[K, T]) check(self snapshot, set map[string]snapshot) error {
+	d := self.(*data[K, T])
+
+	for _, ref := range t.refs {
+		target, ok := set[ref.target]
+		if !ok {
+			return gerrors.NewError(fmt.Sprintf("references %s", ref.target), ErrNotFoundTable)
+		}
+
+		for _, row := range d.rows {
+			for _, key := range ref.keys(row) {
+				if !target.has(key) {
+					return gerrors.NewError(fmt.Sprintf("row %v references %s key %v", t.key(row), ref.target, key), ErrInvalidReference)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// 检测主键是否存在
+func (d *data[K, T]) has(key any) bool {
+	k, ok := key.(K)
+	if !ok {
+		return false
+	}
+
+	_, ok = d.primary[k]
+
+	return ok
+}
+
+type Index[V comparable, K comparable, T any] struct {
+	table *Table[K, T]
+	name  string
+}
+
+// NewIndex 为配置表定义二级索引；索引值可重复
+// 索引须在配置表集合加载前定义
+func NewIndex[V comparable, K comparable, T any](table *Table[K, T], name string, fn func(row *T) V) *Index[V, K, T] {
+	if _, ok := table.indexes[name]; ok {
+		glog.Fatalf("the index %s of table %s is already defined", name, table.name)
+	}
+
+	table.indexes[name] = func(row *T) any { return fn(row) }
+
+	return &Index[V, K, T]{table: table, name: name}
+}
+
+// Find 通过索引值查找行；顺序与表格中的行顺序一致
+func (i *Index[V, K, T]) Find(value V) []*T {
+	d := i.table.load()
+	if d == nil {
+		return nil
+	}
+
+	return d.indexes[i.name][value]
+}
+
+// First 通过索引值查找首行
+func (i *Index[V, K, T]) First(value V) (*T, bool) {
+	rows := i.Find(value)
+	if len(rows) == 0 {
+		return nil, false
+	}
+
+	return rows[0], true
+}
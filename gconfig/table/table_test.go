@@ -0,0 +1,181 @@
+package table_test
+
+import (
+	"archive/zip"
+	"context"
+	"github.com/goodluck0107/gcore/gconfig"
+	"github.com/goodluck0107/gcore/gconfig/file/core"
+	"github.com/goodluck0107/gcore/gconfig/table"
+	"github.com/goodluck0107/gcore/gerrors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type Item struct {
+	ID    int32    `table:"id"`
+	Name  string   `table:"name"`
+	Kind  string   `table:"kind"`
+	Price float64  `table:"price"`
+	Tags  []string `table:"tags"`
+}
+
+type Drop struct {
+	ID    int32   `table:"id"`
+	Items []int32 `table:"items"`
+	Rate  float32 `table:"rate"`
+}
+
+const itemCSV = "\xef\xbb\xbfid,name,kind,price,tags\n" +
+	"#编号,名称,类型,价格,标签\n" +
+	"1,sword,weapon,10.5,sharp|iron\n" +
+	"2,shield,armor,8,iron\n" +
+	"3,bow,weapon,12,\n"
+
+func writeFile(t *testing.T, dir, name string, content []byte) {
+	if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 生成仅包含共享字符串与单个工作表的最小XLSX文件
+func writeXLSX(t *testing.T, dir, name string, sheet string) {
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="drop" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>id</t></si><si><t>items</t></si><si><r><t>ra</t></r><r><t>te</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml":   sheet,
+	}
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func dropSheet(items string) string {
+	return `<worksheet><sheetData>` +
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>` +
+		`<row r="2"><c r="A2"><v>100</v></c><c r="B2" t="inlineStr"><is><t>` + items + `</t></is></c><c r="C2"><v>0.5</v></c></row>` +
+		`</sheetData></worksheet>`
+}
+
+func newTables(dir string) (*table.Tables, *table.Table[int32, Item], *table.Index[string, int32, Item], *table.Table[int32, Drop]) {
+	tables := table.NewTables(table.WithSource(core.NewSource(dir, gconfig.ReadOnly)))
+
+	items := table.NewTable(tables, "item", func(row *Item) int32 { return row.ID })
+	kinds := table.NewIndex(items, "kind", func(row *Item) string { return row.Kind })
+	drops := table.NewTable(tables, "drop", func(row *Drop) int32 { return row.ID }).
+		AddReference("item", func(row *Drop) []any {
+			keys := make([]any, 0, len(row.Items))
+			for _, id := range row.Items {
+				keys = append(keys, id)
+			}
+			return keys
+		})
+
+	return tables, items, kinds, drops
+}
+
+func TestTables_Load(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "item.csv", []byte(itemCSV))
+	writeXLSX(t, dir, "drop.xlsx", dropSheet("1|3"))
+
+	tables, items, kinds, drops := newTables(dir)
+	defer tables.Close()
+
+	if err := tables.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if items.Len() != 3 {
+		t.Fatalf("unexpected item count: %d", items.Len())
+	}
+
+	sword, ok := items.Get(1)
+	if !ok || sword.Name != "sword" || sword.Price != 10.5 || len(sword.Tags) != 2 {
+		t.Fatalf("unexpected item: %+v", sword)
+	}
+
+	if weapons := kinds.Find("weapon"); len(weapons) != 2 || weapons[1].Name != "bow" {
+		t.Fatalf("unexpected weapons: %v", weapons)
+	}
+
+	drop, ok := drops.Get(100)
+	if !ok || len(drop.Items) != 2 || drop.Items[1] != 3 || drop.Rate != 0.5 {
+		t.Fatalf("unexpected drop: %+v", drop)
+	}
+}
+
+func TestTables_InvalidReference(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "item.csv", []byte(itemCSV))
+	writeXLSX(t, dir, "drop.xlsx", dropSheet("1|4"))
+
+	tables, _, _, _ := newTables(dir)
+	defer tables.Close()
+
+	if err := tables.Load(context.Background()); !gerrors.Is(err, table.ErrInvalidReference) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTables_Watch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "item.csv", []byte(itemCSV))
+	writeXLSX(t, dir, "drop.xlsx", dropSheet("1|3"))
+
+	tables, items, _, _ := newTables(dir)
+	defer tables.Close()
+
+	if err := tables.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan []string, 10)
+	tables.Watch(func(names ...string) { changed <- names }, "item")
+
+	// 删除被引用的行时校验失败，保留原有配置表
+	writeFile(t, dir, "item.csv", []byte("id,name,kind\n1,sword,weapon\n"))
+
+	select {
+	case names := <-changed:
+		t.Fatalf("unexpected change: %v", names)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	if items.Len() != 3 {
+		t.Fatalf("unexpected item count: %d", items.Len())
+	}
+
+	writeFile(t, dir, "item.csv", []byte(itemCSV+"4,axe,weapon,20,\n"))
+
+	timeout := time.After(3 * time.Second)
+	for items.Len() != 4 {
+		select {
+		case <-changed:
+		case <-timeout:
+			t.Fatalf("table is not reloaded, item count: %d", items.Len())
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package table
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gconfig"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/glog"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrNotFoundTable    = gerrors.New("not found table")
+	ErrDuplicateKey     = gerrors.New("duplicate primary key")
+	ErrInvalidReference = gerrors.New("invalid table reference")
+)
+
+type watcher struct {
+	names    map[string]struct{}
+	callback gconfig.WatchCallbackFunc
+}
+
+// Tables 配置表集合；整体加载、校验并原子替换所有配置表
+type Tables struct {
+	opts     *options
+	ctx      context.Context
+	cancel   context.CancelFunc
+	defs     map[string]definition
+	order    []string
+	raws     map[string]*gconfig.Configuration
+	values   atomic.Pointer[map[string]snapshot]
+	mu       sync.Mutex
+	rw       sync.RWMutex
+	watchers []*watcher
+	watched  bool
+}
+
+func NewTables(opts ...Option) *Tables {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.source == nil {
+		o.source = defaultSource()
+	}
+
+	ts := &Tables{}
+	ts.opts = o
+	ts.ctx, ts.cancel = context.WithCancel(o.ctx)
+	ts.defs = make(map[string]definition)
+	ts.raws = make(map[string]*gconfig.Configuration)
+	ts.watchers = make([]*watcher, 0)
+
+	return ts
+}
+
+// Load 加载所有已定义的配置表，并开始监听配置表变化
+// 任一配置表解析或跨表引用校验失败时，不替换当前配置表
+func (ts *Tables) Load(ctx context.Context) error {
+	cs, err := ts.opts.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	raws := make(map[string]*gconfig.Configuration, len(ts.defs))
+	for _, c := range cs {
+		if _, ok := ts.defs[c.Name]; ok && isTableFormat(c.Format) {
+			raws[c.Name] = c
+		}
+	}
+
+	if err = ts.swap(raws); err != nil {
+		return err
+	}
+
+	if !ts.watched {
+		ts.watched = true
+		ts.watch()
+	}
+
+	return nil
+}
+
+// Watch 设置监听回调；配置表集合替换成功后回调发生变化的配置表名称
+func (ts *Tables) Watch(cb gconfig.WatchCallbackFunc, names ...string) {
+	w := &watcher{
+		names:    make(map[string]struct{}, len(names)),
+		callback: cb,
+	}
+
+	for _, name := range names {
+		w.names[name] = struct{}{}
+	}
+
+	ts.rw.Lock()
+	ts.watchers = append(ts.watchers, w)
+	ts.rw.Unlock()
+}
+
+// Close 关闭配置表监听
+func (ts *Tables) Close() {
+	ts.cancel()
+
+	_ = ts.opts.source.Close()
+}
+
+// 定义配置表
+func (ts *Tables) define(name string, def definition) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if _, ok := ts.defs[name]; ok {
+		glog.Fatalf("the table %s is already defined", name)
+	}
+
+	ts.defs[name] = def
+	ts.order = append(ts.order, name)
+}
+
+// 加载配置表快照
+func (ts *Tables) load(name string) (snapshot, bool) {
+	values := ts.values.Load()
+	if values == nil {
+		return nil, false
+	}
+
+	s, ok := (*values)[name]
+
+	return s, ok
+}
+
+// 解析校验并原子替换配置表集合；调用方需持有锁
+func (ts *Tables) swap(raws map[string]*gconfig.Configuration) error {
+	values := make(map[string]snapshot, len(ts.defs))
+
+	for _, name := range ts.order {
+		c, ok := raws[name]
+		if !ok {
+			return gerrors.NewError(name, ErrNotFoundTable)
+		}
+
+		rows, err := readRows(c.Format, c.Content)
+		if err != nil {
+			return gerrors.NewError("table "+name, err)
+		}
+
+		s, err := ts.defs[name].parse(rows)
+		if err != nil {
+			return gerrors.NewError("table "+name, err)
+		}
+
+		values[name] = s
+	}
+
+	for _, name := range ts.order {
+		if err := ts.defs[name].check(values[name], values); err != nil {
+			return gerrors.NewError("table "+name, err)
+		}
+	}
+
+	ts.raws = raws
+	ts.values.Store(&values)
+
+	return nil
+}
+
+// 监听配置表变化
+func (ts *Tables) watch() {
+	w, err := ts.opts.source.Watch(ts.ctx)
+	if err != nil {
+		glog.Warnf("watching table change failed: %v", err)
+		return
+	}
+
+	go func() {
+		defer w.Stop()
+
+		for {
+			select {
+			case <-ts.ctx.Done():
+				return
+			default:
+				// exec watch
+			}
+
+			cs, err := w.Next()
+			if err != nil {
+				continue
+			}
+
+			if names := ts.reload(cs); len(names) > 0 {
+				go ts.notify(names...)
+			}
+		}
+	}()
+}
+
+// 重新加载发生变化的配置表；返回替换成功的配置表名称
+func (ts *Tables) reload(cs []*gconfig.Configuration) []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	names := make([]string, 0, len(cs))
+	raws := make(map[string]*gconfig.Configuration, len(ts.raws))
+	for name, c := range ts.raws {
+		raws[name] = c
+	}
+
+	for _, c := range cs {
+		if _, ok := ts.defs[c.Name]; !ok || !isTableFormat(c.Format) || len(c.Content) == 0 {
+			continue
+		}
+
+		raws[c.Name] = c
+		names = append(names, c.Name)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	if err := ts.swap(raws); err != nil {
+		glog.Errorf("reload table failed: %v", err)
+		return nil
+	}
+
+	return names
+}
+
+// 通知给监听器
+func (ts *Tables) notify(names ...string) {
+	ts.rw.RLock()
+	defer ts.rw.RUnlock()
+
+	for _, w := range ts.watchers {
+		if len(w.names) == 0 {
+			w.callback(names...)
+			continue
+		}
+
+		validNames := make([]string, 0, len(names))
+		for _, name := range names {
+			if _, ok := w.names[name]; ok {
+				validNames = append(validNames, name)
+			}
+		}
+
+		if len(validNames) > 0 {
+			w.callback(validNames...)
+		}
+	}
+}
+
+// 检测是否为配置表格式
+func isTableFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case CSV, XLSX:
+		return true
+	default:
+		return false
+	}
+}
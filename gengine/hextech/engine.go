@@ -2,19 +2,21 @@ package hextech
 
 import (
 	"context"
+	"fmt"
 	"github.com/goodluck0107/gcore/gconfig"
+	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/getc"
 	"github.com/goodluck0107/gcore/geventbus"
 	"github.com/goodluck0107/gcore/glog"
 	"github.com/goodluck0107/gcore/gmodules"
 	"github.com/goodluck0107/gcore/gtask"
-	"github.com/goodluck0107/gcore/gutils/gcall"
 	"github.com/goodluck0107/gcore/gutils/gfile"
 	"github.com/goodluck0107/gcore/gwrap/info"
 	"os"
 	"os/signal"
 	"runtime"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -24,12 +26,30 @@ const (
 	defaultHextechShutdownMaxWaitTimeKey = "etc.engine.shutdownMaxWaitTime" // 容器关闭最大等待时间
 )
 
+var (
+	ErrModuleTimeout       = gerrors.New("module lifecycle timeout")
+	ErrDuplicateModule     = gerrors.New("duplicate module name")
+	ErrNotFoundDependency  = gerrors.New("not found module dependency")
+	ErrCircularDependency  = gerrors.New("circular module dependency")
+	ErrEngineAlreadyBooted = gerrors.New("engine is already booted")
+)
+
 type Engine struct {
-	mods []gmodules.Module
+	opts    *options
+	mods    []gmodules.Module
+	levels  [][]gmodules.Module // 按依赖层级分组的组件，同层组件间无依赖关系
+	inited  []gmodules.Module   // 已初始化的组件
+	started []gmodules.Module   // 已启动的组件
+	mu      sync.Mutex
 }
 
-func NewEngine() *Engine {
-	return &Engine{}
+func NewEngine(opts ...Option) *Engine {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Engine{opts: o}
 }
 
 // Injection 给引擎注入模块
@@ -43,53 +63,144 @@ func (c *Engine) Up() {
 
 	c.doPrintFrameworkInfo()
 
-	c.doInitModules()
-
-	c.doStartModules()
+	if err := c.Boot(); err != nil {
+		glog.Fatalf("engine boot failed: %v", err)
+	}
 
 	c.doWaitSystemSignal()
 
+	c.Shutdown()
+
+	c.doClearInnerModules()
+}
+
+// Boot 按依赖顺序初始化并启动所有组件；任一组件失败时回滚已启动与已初始化的组件
+func (c *Engine) Boot() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.levels != nil {
+		return ErrEngineAlreadyBooted
+	}
+
+	levels, err := sortModules(c.mods)
+	if err != nil {
+		return err
+	}
+
+	c.levels = levels
+
+	if err = c.doInitModules(); err != nil {
+		c.doDestroyModules()
+		return err
+	}
+
+	if err = c.doStartModules(); err != nil {
+		c.doCloseModules()
+		c.doDestroyModules()
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown 按依赖的相反顺序关闭并销毁所有组件
+func (c *Engine) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.doCloseModules()
 
 	c.doDestroyModules()
-
-	c.doClearInnerModules()
 }
 
 // 初始化所有组件
-func (c *Engine) doInitModules() {
-	for _, mod := range c.mods {
-		mod.Init()
+func (c *Engine) doInitModules() error {
+	for _, level := range c.levels {
+		for _, mod := range level {
+			if err := invoke(context.Background(), mod, "init", c.opts.initTimeout, initFunc(mod)); err != nil {
+				return err
+			}
+
+			c.inited = append(c.inited, mod)
+		}
 	}
+
+	return nil
 }
 
 // 启动所有组件
-func (c *Engine) doStartModules() {
-	for _, mod := range c.mods {
-		mod.Start()
+func (c *Engine) doStartModules() error {
+	for _, level := range c.levels {
+		for _, mod := range level {
+			if err := invoke(context.Background(), mod, "start", c.opts.startTimeout, startFunc(mod)); err != nil {
+				return err
+			}
+
+			c.started = append(c.started, mod)
+		}
 	}
+
+	return nil
 }
 
-// 关闭所有组件
+// 关闭已启动的组件；同层组件并发关闭，全部层级共享关闭最大等待时间
 func (c *Engine) doCloseModules() {
-	g := gcall.NewGoroutines()
-
-	for _, mod := range c.mods {
-		g.Add(mod.Close)
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if c.opts.shutdownWait > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.opts.shutdownWait)
 	}
+	defer cancel()
+
+	c.doReverse(ctx, c.started, "close", c.opts.closeTimeout, closeFunc)
 
-	g.Run(context.Background(), getc.Get(defaultHextechShutdownMaxWaitTimeKey).Duration())
+	c.started = nil
 }
 
-// 销毁所有组件
+// 销毁已初始化的组件；同层组件并发销毁
 func (c *Engine) doDestroyModules() {
-	g := gcall.NewGoroutines()
+	c.doReverse(context.Background(), c.inited, "destroy", c.opts.destroyTimeout, destroyFunc)
+
+	c.inited = nil
+}
+
+// 按依赖层级的相反顺序执行；上下文结束后跳过剩余的组件
+func (c *Engine) doReverse(ctx context.Context, mods []gmodules.Module, phase string, timeout time.Duration, fn func(mod gmodules.Module) func(ctx context.Context) error) {
+	if len(mods) == 0 {
+		return
+	}
 
-	for _, mod := range c.mods {
-		g.Add(mod.Destroy)
+	set := make(map[gmodules.Module]struct{}, len(mods))
+	for _, mod := range mods {
+		set[mod] = struct{}{}
 	}
 
-	g.Run(context.Background(), 5*time.Second)
+	for i := len(c.levels) - 1; i >= 0; i-- {
+		wg := &sync.WaitGroup{}
+
+		for _, mod := range c.levels[i] {
+			if _, ok := set[mod]; !ok {
+				continue
+			}
+
+			if ctx.Err() != nil {
+				glog.Warnf("module %s %s skipped: engine shutdown max wait time exceeded", mod.Name(), phase)
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(mod gmodules.Module) {
+				defer wg.Done()
+
+				if err := invoke(ctx, mod, phase, timeout, fn(mod)); err != nil {
+					glog.Errorf("%v", err)
+				}
+			}(mod)
+		}
+
+		wg.Wait()
+	}
 }
 
 // 等待系统信号
@@ -143,3 +254,156 @@ func (c *Engine) doPrintFrameworkInfo() {
 
 	info.PrintGlobalInfo()
 }
+
+// 执行组件的生命周期函数；超时或发生panic时返回错误
+// 超时后生命周期函数所在的协程无法被强制结束，将继续运行直至其自行返回
+func invoke(ctx context.Context, mod gmodules.Module, phase string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				done <- gerrors.NewError(fmt.Sprintf("panic: %v", e))
+			}
+		}()
+
+		done <- fn(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ErrModuleTimeout
+
+		glog.Warnf("module %s %s timed out, its goroutine keeps running until it returns", mod.Name(), phase)
+
+		go func() {
+			<-done
+			glog.Warnf("module %s %s returned after timeout", mod.Name(), phase)
+		}()
+	}
+
+	if err != nil {
+		return gerrors.NewError(fmt.Sprintf("module %s %s failed", mod.Name(), phase), err)
+	}
+
+	return nil
+}
+
+func initFunc(mod gmodules.Module) func(ctx context.Context) error {
+	if m, ok := mod.(gmodules.Initializer); ok {
+		return m.TryInit
+	}
+
+	return func(ctx context.Context) error { mod.Init(); return nil }
+}
+
+func startFunc(mod gmodules.Module) func(ctx context.Context) error {
+	if m, ok := mod.(gmodules.Starter); ok {
+		return m.TryStart
+	}
+
+	return func(ctx context.Context) error { mod.Start(); return nil }
+}
+
+func closeFunc(mod gmodules.Module) func(ctx context.Context) error {
+	if m, ok := mod.(gmodules.Closer); ok {
+		return m.TryClose
+	}
+
+	return func(ctx context.Context) error { mod.Close(); return nil }
+}
+
+func destroyFunc(mod gmodules.Module) func(ctx context.Context) error {
+	if m, ok := mod.(gmodules.Destroyer); ok {
+		return m.TryDestroy
+	}
+
+	return func(ctx context.Context) error { mod.Destroy(); return nil }
+}
+
+// 按依赖关系对组件进行分层排序；同层组件保持注入顺序
+func sortModules(mods []gmodules.Module) ([][]gmodules.Module, error) {
+	names := make(map[string]int, len(mods))
+	index := make(map[string]int, len(mods))
+	for i, mod := range mods {
+		names[mod.Name()]++
+		index[mod.Name()] = i
+	}
+
+	depends := make([][]int, len(mods))
+	for i, mod := range mods {
+		d, ok := mod.(gmodules.Dependent)
+		if !ok {
+			continue
+		}
+
+		for _, name := range d.Depends() {
+			j, ok := index[name]
+			if !ok {
+				return nil, gerrors.NewError(fmt.Sprintf("%s depends on %s", mod.Name(), name), ErrNotFoundDependency)
+			}
+
+			if names[name] > 1 {
+				return nil, gerrors.NewError(name, ErrDuplicateModule)
+			}
+
+			depends[i] = append(depends[i], j)
+		}
+	}
+
+	levels := make([]int, len(mods))
+	states := make([]int8, len(mods)) // 0:未访问 1:访问中 2:已完成
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch states[i] {
+		case 1:
+			return gerrors.NewError(mods[i].Name(), ErrCircularDependency)
+		case 2:
+			return nil
+		}
+
+		states[i] = 1
+
+		for _, j := range depends[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+
+			if levels[j]+1 > levels[i] {
+				levels[i] = levels[j] + 1
+			}
+		}
+
+		states[i] = 2
+
+		return nil
+	}
+
+	depth := 0
+	for i := range mods {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+
+		if levels[i]+1 > depth {
+			depth = levels[i] + 1
+		}
+	}
+
+	sorted := make([][]gmodules.Module, depth)
+	for i, mod := range mods {
+		sorted[levels[i]] = append(sorted[levels[i]], mod)
+	}
+
+	return sorted, nil
+}
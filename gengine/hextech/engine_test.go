@@ -0,0 +1,167 @@
+package hextech_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gengine/hextech"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gmodules"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) add(call string) {
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+}
+
+type module struct {
+	gmodules.Base
+	name     string
+	depends  []string
+	rec      *recorder
+	startErr error
+	hang     bool
+	linger   time.Duration
+}
+
+func (m *module) Name() string { return m.name }
+
+func (m *module) Depends() []string { return m.depends }
+
+func (m *module) Init() { m.rec.add("init:" + m.name) }
+
+func (m *module) TryStart(ctx context.Context) error {
+	if m.hang {
+		<-ctx.Done()
+	}
+
+	if m.startErr != nil {
+		return m.startErr
+	}
+
+	m.rec.add("start:" + m.name)
+
+	return nil
+}
+
+func (m *module) Close() {
+	time.Sleep(m.linger)
+	m.rec.add("close:" + m.name)
+}
+
+func (m *module) Destroy() { m.rec.add("destroy:" + m.name) }
+
+func TestEngine_DependencyOrder(t *testing.T) {
+	rec := &recorder{}
+
+	engine := hextech.NewEngine()
+	engine.Injection(
+		&module{name: "node", depends: []string{"registry", "locator"}, rec: rec},
+		&module{name: "locator", rec: rec},
+		&module{name: "registry", depends: []string{"locator"}, rec: rec},
+	)
+
+	if err := engine.Boot(); err != nil {
+		t.Fatal(err)
+	}
+
+	engine.Shutdown()
+
+	expected := []string{
+		"init:locator", "init:registry", "init:node",
+		"start:locator", "start:registry", "start:node",
+		"close:node", "close:registry", "close:locator",
+		"destroy:node", "destroy:registry", "destroy:locator",
+	}
+
+	if !reflect.DeepEqual(rec.calls, expected) {
+		t.Fatalf("unexpected calls: %v", rec.calls)
+	}
+}
+
+func TestEngine_Rollback(t *testing.T) {
+	rec := &recorder{}
+	fail := gerrors.New("start failed")
+
+	engine := hextech.NewEngine()
+	engine.Injection(
+		&module{name: "a", rec: rec},
+		&module{name: "b", depends: []string{"a"}, rec: rec, startErr: fail},
+		&module{name: "c", depends: []string{"b"}, rec: rec},
+	)
+
+	if err := engine.Boot(); !gerrors.Is(err, fail) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"init:a", "init:b", "init:c",
+		"start:a",
+		"close:a",
+		"destroy:c", "destroy:b", "destroy:a",
+	}
+
+	if !reflect.DeepEqual(rec.calls, expected) {
+		t.Fatalf("unexpected calls: %v", rec.calls)
+	}
+}
+
+func TestEngine_Timeout(t *testing.T) {
+	rec := &recorder{}
+
+	engine := hextech.NewEngine(hextech.WithStartTimeout(50 * time.Millisecond))
+	engine.Injection(&module{name: "a", rec: rec, hang: true})
+
+	if err := engine.Boot(); !gerrors.Is(err, hextech.ErrModuleTimeout) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEngine_ShutdownMaxWaitTime(t *testing.T) {
+	rec := &recorder{}
+
+	engine := hextech.NewEngine(hextech.WithShutdownMaxWaitTime(100 * time.Millisecond))
+	engine.Injection(
+		&module{name: "a", rec: rec, linger: 80 * time.Millisecond},
+		&module{name: "b", depends: []string{"a"}, rec: rec, linger: 80 * time.Millisecond},
+	)
+
+	if err := engine.Boot(); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	engine.Shutdown()
+
+	// 关闭最大等待时间为全部层级共享的总时限，而非每个层级各自的时限
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("shutdown took %v", elapsed)
+	}
+}
+
+func TestEngine_InvalidDependency(t *testing.T) {
+	engine := hextech.NewEngine()
+	engine.Injection(
+		&module{name: "a", depends: []string{"b"}, rec: &recorder{}},
+		&module{name: "b", depends: []string{"a"}, rec: &recorder{}},
+	)
+
+	if err := engine.Boot(); !gerrors.Is(err, hextech.ErrCircularDependency) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine = hextech.NewEngine()
+	engine.Injection(&module{name: "a", depends: []string{"c"}, rec: &recorder{}})
+
+	if err := engine.Boot(); !gerrors.Is(err, hextech.ErrNotFoundDependency) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
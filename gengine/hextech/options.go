@@ -0,0 +1,63 @@
+package hextech
+
+import (
+	"github.com/goodluck0107/gcore/getc"
+	"time"
+)
+
+const (
+	defaultInitTimeoutKey    = "etc.engine.initTimeout"    // 单个组件初始化超时时间
+	defaultStartTimeoutKey   = "etc.engine.startTimeout"   // 单个组件启动超时时间
+	defaultCloseTimeoutKey   = "etc.engine.closeTimeout"   // 单个组件关闭超时时间
+	defaultDestroyTimeoutKey = "etc.engine.destroyTimeout" // 单个组件销毁超时时间
+)
+
+const (
+	defaultDestroyTimeout = "5s"
+)
+
+type Option func(o *options)
+
+type options struct {
+	initTimeout    time.Duration // 单个组件初始化超时时间，默认不限制
+	startTimeout   time.Duration // 单个组件启动超时时间，默认不限制
+	closeTimeout   time.Duration // 单个组件关闭超时时间，默认不限制
+	destroyTimeout time.Duration // 单个组件销毁超时时间，默认5s
+	shutdownWait   time.Duration // 关闭全部组件的最大等待时间，默认不限制
+}
+
+func defaultOptions() *options {
+	return &options{
+		initTimeout:    getc.Get(defaultInitTimeoutKey).Duration(),
+		startTimeout:   getc.Get(defaultStartTimeoutKey).Duration(),
+		closeTimeout:   getc.Get(defaultCloseTimeoutKey).Duration(),
+		destroyTimeout: getc.Get(defaultDestroyTimeoutKey, defaultDestroyTimeout).Duration(),
+		shutdownWait:   getc.Get(defaultHextechShutdownMaxWaitTimeKey).Duration(),
+	}
+}
+
+// WithInitTimeout 设置单个组件初始化超时时间；为0时不限制
+func WithInitTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.initTimeout = timeout }
+}
+
+// WithStartTimeout 设置单个组件启动超时时间；为0时不限制
+func WithStartTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.startTimeout = timeout }
+}
+
+// WithCloseTimeout 设置单个组件关闭超时时间；为0时不限制
+func WithCloseTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.closeTimeout = timeout }
+}
+
+// WithShutdownMaxWaitTime 设置关闭全部组件的最大等待时间；为0时不限制
+// 该时间为所有依赖层级共享的总时限，超出后尚未关闭的组件将被跳过
+func WithShutdownMaxWaitTime(wait time.Duration) Option {
+	return func(o *options) { o.shutdownWait = wait }
+}
+
+// WithDestroyTimeout 设置单个组件销毁超时时间；为0时不限制
+func WithDestroyTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.destroyTimeout = timeout }
+}
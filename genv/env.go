@@ -3,6 +3,7 @@ package genv
 import (
 	"github.com/goodluck0107/gcore/gwrap/value"
 	"os"
+	"strings"
 )
 
 // Get 获取环境变量值
@@ -29,3 +30,23 @@ func Has(key string) bool {
 	_, ok := os.LookupEnv(key)
 	return ok
 }
+
+// Values 获取指定前缀的所有环境变量
+func Values(prefix ...string) map[string]string {
+	values := make(map[string]string)
+
+	for _, env := range os.Environ() {
+		key, val, ok := strings.Cut(env, "=")
+		if !ok {
+			continue
+		}
+
+		if len(prefix) > 0 && !strings.HasPrefix(key, prefix[0]) {
+			continue
+		}
+
+		values[key] = val
+	}
+
+	return values
+}
@@ -36,6 +36,9 @@ var (
 	ErrNoOperationPermission = New("no operation permission")
 	ErrInvalidConfigContent  = New("invalid config content")
 	ErrNotFoundConfigSource  = New("not found config source")
+	ErrConfigVersionConflict = New("config version conflict")
+	ErrNotFoundConfigVersion = New("not found config version")
+	ErrNotSupportVersioning  = New("not support versioning")
 	ErrInvalidFormat         = New("invalid format")
 	ErrIllegalRequest        = New("illegal request")
 	ErrIllegalOperation      = New("illegal operation")
@@ -44,6 +47,7 @@ var (
 	ErrUnexpectedEOF         = New("unexpected EOF")
 	ErrMissTransporter       = New("miss transporter")
 	ErrMissDiscovery         = New("miss discovery")
+	ErrMissConfigurator      = New("miss configurator")
 	ErrNotFoundDirectAddress = New("not found direct address")
 	ErrUnknownError          = New("unknown error")
 	ErrClientClosed          = New("client is closed")
@@ -52,7 +56,21 @@ var (
 	ErrUnregisterRoute       = New("unregistered route")
 	ErrNotBindActor          = New("not bind actor")
 	ErrNotFoundActor         = New("not found actor")
+	ErrNotFoundTimerHandler  = New("not found timer handler")
+	ErrActorDestroyed        = New("actor destroyed")
 	ErrWriterClosing         = New("writer is closing")
+	ErrMissIDGenerator       = New("miss id generator")
+	ErrClockBackwards        = New("clock moved backwards")
+	ErrWorkerLeaseLost       = New("worker lease lost")
+	ErrNoAvailableWorker     = New("no available worker")
+	ErrIDExhausted           = New("id exhausted")
+	ErrNotFoundMember        = New("not found member")
+	ErrScoreOverflow         = New("score overflow")
+	ErrResyncRequired        = New("resync required")
+	ErrEventbusClosed        = New("eventbus is closed")
+	ErrLockNotHeld           = New("lock not held")
+	ErrVersionConflict       = New("version conflict")
+	ErrInvalidValidateRule   = New("invalid validate rule")
 )
 
 // NewError 新建一个错误
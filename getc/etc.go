@@ -2,7 +2,6 @@ package getc
 
 import (
 	"github.com/goodluck0107/gcore/gconfig"
-	"github.com/goodluck0107/gcore/gconfig/file/core"
 	"github.com/goodluck0107/gcore/genv"
 	"github.com/goodluck0107/gcore/gflag"
 	"github.com/goodluck0107/gcore/gwrap/value"
@@ -24,7 +23,7 @@ var globalConfigurator gconfig.Configurator
 func init() {
 	path := genv.Get(gcoreEtcEnvName, defaultEtcPath).String()
 	path = gflag.String(gcoreEtcArgName, path)
-	globalConfigurator = gconfig.NewConfigurator(gconfig.WithSources(core.NewSource(path, gconfig.ReadOnly)))
+	globalConfigurator = NewConfigurator(path)
 }
 
 // SetConfigurator 设置配置器
@@ -61,6 +60,15 @@ func Match(patterns ...string) gconfig.Matcher {
 	return globalConfigurator.Match(patterns...)
 }
 
+// Dump 导出配置键及其来源层级；配置器不支持自省时返回空
+func Dump() []*Item {
+	if dumper, ok := globalConfigurator.(Dumper); ok {
+		return dumper.Dump()
+	}
+
+	return nil
+}
+
 // Close 关闭配置监听
 func Close() {
 	globalConfigurator.Close()
@@ -1,11 +1,89 @@
 package getc_test
 
 import (
+	"github.com/goodluck0107/gcore/genv"
 	"github.com/goodluck0107/gcore/getc"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func Test_Get(t *testing.T) {
 	v := getc.Get("c.redis.addrs.1A", "192.168.0.1:3308").String()
 	t.Log(v)
 }
+
+func TestLayered(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"cluster":{"node":{"id":"file-id","name":"lobby","heartbeatInterval":"10s"}}}`
+
+	if err := os.WriteFile(filepath.Join(dir, "etc.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	envs := map[string]string{
+		"GCORE_ETC_CLUSTER_NODE_ID":                "env-id",
+		"GCORE_ETC_CLUSTER_NODE_HEARTBEATINTERVAL": "3s",
+		"GCORE_ETC_CLUSTER_GATE_ADDR":              ":3553",
+	}
+
+	for key, val := range envs {
+		if err := genv.Set(key, val); err != nil {
+			t.Fatal(err)
+		}
+		defer genv.Del(key)
+	}
+
+	c := getc.NewConfigurator(dir)
+	defer c.Close()
+
+	if id := c.Get("etc.cluster.node.id").String(); id != "env-id" {
+		t.Fatalf("unexpected id: %s", id)
+	}
+
+	if d := c.Get("etc.cluster.node.heartbeatInterval").Duration(); d != 3*time.Second {
+		t.Fatalf("unexpected heartbeat interval: %v", d)
+	}
+
+	if addr := c.Get("etc.cluster.gate.addr").String(); addr != ":3553" {
+		t.Fatalf("unexpected addr: %s", addr)
+	}
+
+	if weight := c.Get("etc.cluster.node.weight", 1).Int(); weight != 1 {
+		t.Fatalf("unexpected weight: %d", weight)
+	}
+
+	node := struct {
+		ID                string `json:"id"`
+		Name              string `json:"name"`
+		HeartbeatInterval string `json:"heartbeatInterval"`
+	}{}
+
+	if err := c.Match("etc.cluster.node").Scan(&node); err != nil {
+		t.Fatal(err)
+	}
+
+	if node.ID != "env-id" || node.Name != "lobby" || node.HeartbeatInterval != "3s" {
+		t.Fatalf("unexpected node: %+v", node)
+	}
+
+	layers := make(map[string]string)
+	for _, item := range c.(getc.Dumper).Dump() {
+		layers[item.Key] = item.Layer
+	}
+
+	expected := map[string]string{
+		"etc.cluster.node.id":                getc.LayerEnv,
+		"etc.cluster.node.name":              getc.LayerFile,
+		"etc.cluster.node.heartbeatInterval": getc.LayerEnv,
+		"etc.cluster.gate.addr":              getc.LayerEnv,
+		"etc.cluster.node.weight":            getc.LayerDefault,
+	}
+
+	for key, layer := range expected {
+		if layers[key] != layer {
+			t.Fatalf("unexpected layer of %s: %s", key, layers[key])
+		}
+	}
+}
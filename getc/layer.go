@@ -0,0 +1,369 @@
+package getc
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gconfig"
+	"github.com/goodluck0107/gcore/gconfig/file/core"
+	"github.com/goodluck0107/gcore/genv"
+	"github.com/goodluck0107/gcore/gflag"
+	"github.com/goodluck0107/gcore/gwrap/value"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	LayerDefault = "default" // 调用方提供的默认值
+	LayerFile    = "file"    // 配置文件
+	LayerEnv     = "env"     // 环境变量
+	LayerFlag    = "flag"    // 命令行参数
+)
+
+const (
+	gcoreEnvPrefix     = "GCORE_"     // 环境变量覆盖层前缀
+	gcoreEtcEnvPrefix  = "GCORE_ETC_" // 环境变量覆盖层的配置前缀；如 GCORE_ETC_CLUSTER_NODE_ID 对应 etc.cluster.node.id
+	gcoreEtcFlagPrefix = "etc."       // 命令行覆盖层的配置前缀；如 --etc.cluster.node.id=1 对应 etc.cluster.node.id
+	gcoreEtcRoot       = "etc"        // 配置根节点
+)
+
+// Item 配置项来源信息
+type Item struct {
+	Key   string      // 配置键
+	Value interface{} // 配置值
+	Layer string      // 提供配置值的层级
+}
+
+// Dumper 配置来源自省
+type Dumper interface {
+	// Dump 导出配置键及其来源层级
+	Dump() []*Item
+}
+
+// 覆盖层；键均为小写，按不区分大小写的方式匹配
+type layer struct {
+	name   string
+	values map[string]string
+}
+
+type layeredConfigurator struct {
+	files    gconfig.Configurator
+	layers   []*layer // 按优先级从高到低排列
+	defaults sync.Map
+}
+
+var (
+	_ gconfig.Configurator = &layeredConfigurator{}
+	_ Dumper               = &layeredConfigurator{}
+)
+
+// NewConfigurator 新建分层配置器；按 命令行参数 > 环境变量 > 配置文件 > 默认值 的优先级解析配置
+func NewConfigurator(path string) gconfig.Configurator {
+	envs := make(map[string]string)
+	for key, val := range genv.Values(gcoreEtcEnvPrefix) {
+		key = strings.TrimPrefix(key, gcoreEnvPrefix)
+		envs[strings.ToLower(strings.ReplaceAll(key, "_", "."))] = val
+	}
+
+	flags := make(map[string]string)
+	for key, val := range gflag.Values() {
+		if key = strings.ToLower(key); strings.HasPrefix(key, gcoreEtcFlagPrefix) {
+			flags[key] = val
+		}
+	}
+
+	return &layeredConfigurator{
+		files: gconfig.NewConfigurator(gconfig.WithSources(core.NewSource(path, gconfig.ReadOnly))),
+		layers: []*layer{
+			{name: LayerFlag, values: flags},
+			{name: LayerEnv, values: envs},
+		},
+	}
+}
+
+// Has 检测多个匹配规则中是否存在配置
+func (c *layeredConfigurator) Has(pattern string) bool {
+	if _, _, ok := c.lookup(pattern); ok {
+		return true
+	}
+
+	return c.files.Has(pattern) || len(c.children(pattern)) > 0
+}
+
+// Get 获取配置值
+func (c *layeredConfigurator) Get(pattern string, def ...interface{}) value.Value {
+	if val, ok := c.doGet(pattern); ok {
+		return val
+	}
+
+	if len(def) > 0 {
+		c.defaults.Store(pattern, def[0])
+	}
+
+	return value.NewValue(def...)
+}
+
+// Set 设置配置值；设置到配置文件层，覆盖层中的同名配置仍然优先
+func (c *layeredConfigurator) Set(pattern string, value interface{}) error {
+	return c.files.Set(pattern, value)
+}
+
+// Match 匹配多个规则
+func (c *layeredConfigurator) Match(patterns ...string) gconfig.Matcher {
+	return &layeredMatcher{c: c, patterns: patterns}
+}
+
+// Watch 设置监听回调
+func (c *layeredConfigurator) Watch(cb gconfig.WatchCallbackFunc, names ...string) {
+	c.files.Watch(cb, names...)
+}
+
+// WatchCancelable 设置监听回调；返回的函数用于取消监听
+func (c *layeredConfigurator) WatchCancelable(cb gconfig.WatchCallbackFunc, names ...string) func() {
+	if wc, ok := c.files.(gconfig.WatchCanceler); ok {
+		return wc.WatchCancelable(cb, names...)
+	}
+
+	c.files.Watch(cb, names...)
+
+	return func() {}
+}
+
+// Load 加载配置项
+func (c *layeredConfigurator) Load(ctx context.Context, source string, file ...string) ([]*gconfig.Configuration, error) {
+	return c.files.Load(ctx, source, file...)
+}
+
+// Store 保存配置项
+func (c *layeredConfigurator) Store(ctx context.Context, source string, file string, content interface{}, override ...bool) error {
+	return c.files.Store(ctx, source, file, content, override...)
+}
+
+// Commit 保存配置项并记录修订版本
+func (c *layeredConfigurator) Commit(ctx context.Context, source string, file string, content interface{}, opts ...gconfig.CommitOption) (*gconfig.Revision, error) {
+	return c.files.Commit(ctx, source, file, content, opts...)
+}
+
+// Revisions 获取配置项的修订记录列表
+func (c *layeredConfigurator) Revisions(ctx context.Context, source string, file string) ([]*gconfig.Revision, error) {
+	return c.files.Revisions(ctx, source, file)
+}
+
+// Diff 比较配置项两个版本的差异
+func (c *layeredConfigurator) Diff(ctx context.Context, source string, file string, from, to int64) (string, error) {
+	return c.files.Diff(ctx, source, file, from, to)
+}
+
+// Rollback 将配置项回滚到指定版本
+func (c *layeredConfigurator) Rollback(ctx context.Context, source string, file string, version int64, opts ...gconfig.CommitOption) (*gconfig.Revision, error) {
+	return c.files.Rollback(ctx, source, file, version, opts...)
+}
+
+// Close 关闭配置监听
+func (c *layeredConfigurator) Close() {
+	c.files.Close()
+}
+
+// Dump 导出配置键及其来源层级；包含配置文件与覆盖层中的配置，以及实际被使用过的默认值
+func (c *layeredConfigurator) Dump() []*Item {
+	items := make(map[string]*Item)
+
+	c.defaults.Range(func(key, val any) bool {
+		items[strings.ToLower(key.(string))] = &Item{Key: key.(string), Value: val, Layer: LayerDefault}
+		return true
+	})
+
+	flatten(gcoreEtcRoot, c.files.Get(gcoreEtcRoot).Value(), func(key string, val interface{}) {
+		items[strings.ToLower(key)] = &Item{Key: key, Value: val, Layer: LayerFile}
+	})
+
+	for i := len(c.layers) - 1; i >= 0; i-- {
+		for key, val := range c.layers[i].values {
+			if item, ok := items[key]; ok {
+				item.Value, item.Layer = val, c.layers[i].name
+			} else {
+				items[key] = &Item{Key: key, Value: val, Layer: c.layers[i].name}
+			}
+		}
+	}
+
+	list := make([]*Item, 0, len(items))
+	for _, item := range items {
+		list = append(list, item)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+
+	return list
+}
+
+// 执行获取配置操作
+func (c *layeredConfigurator) doGet(pattern string) (value.Value, bool) {
+	if val, _, ok := c.lookup(pattern); ok {
+		return value.NewValue(val), true
+	}
+
+	children := c.children(pattern)
+
+	if c.files.Has(pattern) {
+		val := c.files.Get(pattern)
+
+		if len(children) == 0 {
+			return val, true
+		}
+
+		if node, ok := val.Value().(map[string]interface{}); ok {
+			return value.NewValue(merge(copyMap(node), children)), true
+		}
+
+		return val, true
+	}
+
+	if len(children) > 0 {
+		return value.NewValue(merge(make(map[string]interface{}), children)), true
+	}
+
+	return nil, false
+}
+
+// 在覆盖层中查找配置
+func (c *layeredConfigurator) lookup(pattern string) (string, string, bool) {
+	key := strings.ToLower(pattern)
+
+	for _, l := range c.layers {
+		if val, ok := l.values[key]; ok {
+			return val, l.name, true
+		}
+	}
+
+	return "", "", false
+}
+
+// 获取覆盖层中位于匹配规则下的子配置；返回相对键路径到值的映射
+func (c *layeredConfigurator) children(pattern string) map[string]string {
+	var (
+		prefix   = strings.ToLower(pattern) + "."
+		children map[string]string
+	)
+
+	for i := len(c.layers) - 1; i >= 0; i-- {
+		for key, val := range c.layers[i].values {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+
+			if children == nil {
+				children = make(map[string]string)
+			}
+
+			children[strings.TrimPrefix(key, prefix)] = val
+		}
+	}
+
+	return children
+}
+
+type layeredMatcher struct {
+	c        *layeredConfigurator
+	patterns []string
+}
+
+// Has 检测多个匹配规则中是否存在配置
+func (m *layeredMatcher) Has() bool {
+	for _, pattern := range m.patterns {
+		if m.c.Has(pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get 获取配置值
+func (m *layeredMatcher) Get(def ...interface{}) value.Value {
+	for _, pattern := range m.patterns {
+		if val, ok := m.c.doGet(pattern); ok {
+			return val
+		}
+	}
+
+	if len(def) > 0 && len(m.patterns) > 0 {
+		m.c.defaults.Store(m.patterns[0], def[0])
+	}
+
+	return value.NewValue(def...)
+}
+
+// Scan 扫描读取配置值
+func (m *layeredMatcher) Scan(dest interface{}) error {
+	for _, pattern := range m.patterns {
+		if val, ok := m.c.doGet(pattern); ok {
+			return val.Scan(dest)
+		}
+	}
+
+	return nil
+}
+
+// 将覆盖层的子配置合并到配置节点中；键名按不区分大小写的方式匹配已有配置
+func merge(node map[string]interface{}, children map[string]string) map[string]interface{} {
+	for path, val := range children {
+		keys := strings.Split(path, ".")
+		curr := node
+
+		for i, key := range keys {
+			for k := range curr {
+				if strings.EqualFold(k, key) {
+					key = k
+					break
+				}
+			}
+
+			if i == len(keys)-1 {
+				curr[key] = val
+				break
+			}
+
+			next, ok := curr[key].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				curr[key] = next
+			}
+
+			curr = next
+		}
+	}
+
+	return node
+}
+
+// 深拷贝配置节点
+func copyMap(src map[string]interface{}) map[string]interface{} {
+	dst := make(map[string]interface{}, len(src))
+
+	for key, val := range src {
+		if node, ok := val.(map[string]interface{}); ok {
+			dst[key] = copyMap(node)
+		} else {
+			dst[key] = val
+		}
+	}
+
+	return dst
+}
+
+// 展开配置节点为叶子配置
+func flatten(prefix string, node interface{}, fn func(key string, val interface{})) {
+	vs, ok := node.(map[string]interface{})
+	if !ok {
+		if node != nil {
+			fn(prefix, node)
+		}
+		return
+	}
+
+	for key, val := range vs {
+		flatten(prefix+"."+key, val, fn)
+	}
+}
@@ -1,53 +1,33 @@
 package geventbus
 
 import (
-	"github.com/goodluck0107/gcore/gtask"
-	"reflect"
-	"sync"
+	"context"
+	"github.com/goodluck0107/gcore/glog"
 )
 
 type consumer struct {
-	rw       sync.RWMutex
-	handlers map[uintptr][]EventHandler
+	handlers Handlers
 }
 
-// 添加处理器
-func (c *consumer) addHandler(handler EventHandler) int {
-	pointer := reflect.ValueOf(handler).Pointer()
-
-	c.rw.Lock()
-	defer c.rw.Unlock()
-
-	if _, ok := c.handlers[pointer]; !ok {
-		c.handlers[pointer] = make([]EventHandler, 0, 1)
-	}
-
-	c.handlers[pointer] = append(c.handlers[pointer], handler)
-
-	return len(c.handlers[pointer])
+// 分发数据
+func (c *consumer) dispatch(event *Event) {
+	c.handlers.Dispatch(event)
 }
 
-// 移除处理器
-func (c *consumer) remHandler(handler EventHandler) int {
-	pointer := reflect.ValueOf(handler).Pointer()
-
-	c.rw.Lock()
-	defer c.rw.Unlock()
-
-	delete(c.handlers, pointer)
-
-	return len(c.handlers)
+type group struct {
+	opts    *SubscribeOptions
+	handler AckHandler
 }
 
-// 分发数据
-func (c *consumer) dispatch(event *Event) {
-	c.rw.RLock()
-	defer c.rw.RUnlock()
+// 分发数据；处理失败时在独立协程内退避重试
+func (g *group) dispatch(ctx context.Context, event *Event, deadLetter func(topic string, event *Event)) {
+	go func() {
+		if err := g.opts.Retry(ctx, event, g.handler); err != nil {
+			glog.Warnf("event handle failed, topic: %s id: %s err: %v", event.Topic, event.ID, err)
 
-	for _, handlers := range c.handlers {
-		for i := range handlers {
-			handler := handlers[i]
-			gtask.AddTask(func() { handler(event) })
+			if g.opts.DeadLetter != "" && ctx.Err() == nil {
+				deadLetter(g.opts.DeadLetter, event)
+			}
 		}
-	}
+	}()
 }
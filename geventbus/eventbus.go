@@ -19,10 +19,30 @@ func init() {
 type EventHandler func(event *Event)
 
 type Event struct {
-	ID        string      // 事件ID
-	Topic     string      // 事件主题
-	Payload   value.Value // 事件载荷
-	Timestamp time.Time   // 事件时间
+	ID        string            // 事件ID
+	Topic     string            // 事件主题
+	Payload   value.Value       // 事件载荷
+	Headers   map[string]string // 事件头
+	Timestamp time.Time         // 事件时间
+}
+
+// Message 携带事件头的事件载荷
+// 作为Publish的载荷发布时，事件头随事件一同投递，事件载荷为Payload
+type Message struct {
+	Headers map[string]string // 事件头
+	Payload interface{}       // 事件载荷
+}
+
+// Unwrap 拆分载荷中的事件头与事件载荷
+func Unwrap(payload interface{}) (map[string]string, interface{}) {
+	switch msg := payload.(type) {
+	case *Message:
+		return msg.Headers, msg.Payload
+	case Message:
+		return msg.Headers, msg.Payload
+	default:
+		return nil, payload
+	}
 }
 
 type Eventbus interface {
@@ -34,6 +54,11 @@ type Eventbus interface {
 	Subscribe(ctx context.Context, topic string, handler EventHandler) error
 	// Unsubscribe 取消订阅
 	Unsubscribe(ctx context.Context, topic string, handler EventHandler) error
+	// SubscribeGroup 以消费组订阅事件
+	// 同一消费组内每个事件至少被成功处理一次，且仅投递给组内的一个订阅者；处理失败时按退避策略重试，超过最大重试次数后投递至死信主题
+	SubscribeGroup(ctx context.Context, topic, group string, handler AckHandler, opts ...SubscribeOption) error
+	// UnsubscribeGroup 取消消费组订阅
+	UnsubscribeGroup(ctx context.Context, topic, group string) error
 }
 
 type defaultEventbus struct {
@@ -42,11 +67,14 @@ type defaultEventbus struct {
 
 	rw        sync.RWMutex
 	consumers map[string]*consumer
+	groups    map[string]map[string]*group
 }
 
 func NewEventbus() *defaultEventbus {
 	eb := &defaultEventbus{}
+	eb.ctx, eb.cancel = context.WithCancel(context.Background())
 	eb.consumers = make(map[string]*consumer)
+	eb.groups = make(map[string]map[string]*group)
 
 	return eb
 }
@@ -56,17 +84,23 @@ func (eb *defaultEventbus) Publish(ctx context.Context, topic string, payload in
 	eb.rw.RLock()
 	defer eb.rw.RUnlock()
 
-	c, ok := eb.consumers[topic]
-	if !ok {
-		return nil
-	}
+	headers, payload := Unwrap(payload)
 
-	c.dispatch(&Event{
+	event := &Event{
 		ID:        guuid.UUID(),
 		Topic:     topic,
 		Payload:   value.NewValue(payload),
+		Headers:   headers,
 		Timestamp: gtime.UnixNano(gtime.Now().UnixNano()),
-	})
+	}
+
+	if c, ok := eb.consumers[topic]; ok {
+		c.dispatch(event)
+	}
+
+	for _, g := range eb.groups[topic] {
+		g.dispatch(eb.ctx, event, eb.deadLetter)
+	}
 
 	return nil
 }
@@ -78,11 +112,11 @@ func (eb *defaultEventbus) Subscribe(ctx context.Context, topic string, handler
 
 	c, ok := eb.consumers[topic]
 	if !ok {
-		c = &consumer{handlers: make(map[uintptr][]EventHandler)}
+		c = &consumer{}
 		eb.consumers[topic] = c
 	}
 
-	c.addHandler(handler)
+	c.handlers.Add(handler)
 
 	return nil
 }
@@ -93,7 +127,7 @@ func (eb *defaultEventbus) Unsubscribe(ctx context.Context, topic string, handle
 	defer eb.rw.Unlock()
 
 	if c, ok := eb.consumers[topic]; ok {
-		if c.remHandler(handler) != 0 {
+		if c.handlers.Remove(handler) != 0 {
 			return nil
 		}
 
@@ -103,11 +137,52 @@ func (eb *defaultEventbus) Unsubscribe(ctx context.Context, topic string, handle
 	return nil
 }
 
+// SubscribeGroup 以消费组订阅事件
+// 进程内事件总线中每个消费组仅有一个处理器，重复订阅时替换为最后一次订阅的处理器
+func (eb *defaultEventbus) SubscribeGroup(ctx context.Context, topic, name string, handler AckHandler, opts ...SubscribeOption) error {
+	eb.rw.Lock()
+	defer eb.rw.Unlock()
+
+	groups, ok := eb.groups[topic]
+	if !ok {
+		groups = make(map[string]*group)
+		eb.groups[topic] = groups
+	}
+
+	groups[name] = &group{opts: NewSubscribeOptions(topic, name, opts...), handler: handler}
+
+	return nil
+}
+
+// UnsubscribeGroup 取消消费组订阅
+func (eb *defaultEventbus) UnsubscribeGroup(ctx context.Context, topic, name string) error {
+	eb.rw.Lock()
+	defer eb.rw.Unlock()
+
+	if groups, ok := eb.groups[topic]; ok {
+		delete(groups, name)
+
+		if len(groups) == 0 {
+			delete(eb.groups, topic)
+		}
+	}
+
+	return nil
+}
+
 // Close 停止监听
 func (eb *defaultEventbus) Close() error {
+	eb.cancel()
 	return nil
 }
 
+// 投递死信
+func (eb *defaultEventbus) deadLetter(topic string, event *Event) {
+	if err := eb.Publish(eb.ctx, topic, &Message{Headers: event.Headers, Payload: event.Payload.Value()}); err != nil {
+		glog.Errorf("dead letter publish failed, topic: %s err: %v", topic, err)
+	}
+}
+
 // SetEventbus 设置事件总线
 func SetEventbus(eb Eventbus) {
 	if eb == nil {
@@ -144,6 +219,16 @@ func Unsubscribe(ctx context.Context, topic string, handler EventHandler) error
 	return globalEventbus.Unsubscribe(ctx, topic, handler)
 }
 
+// SubscribeGroup 以消费组订阅事件
+func SubscribeGroup(ctx context.Context, topic, group string, handler AckHandler, opts ...SubscribeOption) error {
+	return globalEventbus.SubscribeGroup(ctx, topic, group, handler, opts...)
+}
+
+// UnsubscribeGroup 取消消费组订阅
+func UnsubscribeGroup(ctx context.Context, topic, group string) error {
+	return globalEventbus.UnsubscribeGroup(ctx, topic, group)
+}
+
 // Close 关闭事件总线
 func Close() error {
 	return globalEventbus.Close()
@@ -55,3 +55,29 @@ func TestEventbus_Subscribe(t *testing.T) {
 
 	time.Sleep(30 * time.Second)
 }
+
+func TestHandlers_Remove(t *testing.T) {
+	var (
+		handlers geventbus.Handlers
+		build    = func(n int) geventbus.EventHandler {
+			return func(event *geventbus.Event) { log.Println(n) }
+		}
+		first  = build(1)
+		second = build(2)
+	)
+
+	handlers.Add(first)
+	handlers.Add(second)
+
+	if n := handlers.Remove(build(3)); n != 2 {
+		t.Fatalf("got %d handlers after removing a missing handler, want 2", n)
+	}
+
+	if n := handlers.Remove(first); n != 1 {
+		t.Fatalf("got %d handlers after removing first, want 1", n)
+	}
+
+	if n := handlers.Remove(second); n != 0 {
+		t.Fatalf("got %d handlers after removing second, want 0", n)
+	}
+}
@@ -0,0 +1,95 @@
+package geventbus
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = time.Second
+	defaultMaxBackoff = time.Minute
+)
+
+// AckHandler 可靠事件处理器
+// 返回nil表示事件处理完成；返回错误时事件将按退避策略重新投递，超过最大重试次数后投递至死信主题
+type AckHandler func(ctx context.Context, event *Event) error
+
+type SubscribeOption func(o *SubscribeOptions)
+
+// SubscribeOptions 消费组订阅选项
+type SubscribeOptions struct {
+	MaxRetries int           // 最大重试次数，默认为3次
+	Backoff    time.Duration // 首次重试的退避时间，之后逐次翻倍，默认为1秒
+	MaxBackoff time.Duration // 最大退避时间，默认为1分钟
+	DeadLetter string        // 死信主题，默认为DeadLetterTopic(topic, group)，为空时丢弃处理失败的事件
+}
+
+// NewSubscribeOptions 创建消费组订阅选项
+func NewSubscribeOptions(topic, group string, opts ...SubscribeOption) *SubscribeOptions {
+	o := &SubscribeOptions{
+		MaxRetries: defaultMaxRetries,
+		Backoff:    defaultBackoff,
+		MaxBackoff: defaultMaxBackoff,
+		DeadLetter: DeadLetterTopic(topic, group),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+
+	return o
+}
+
+// Delay 获取第attempt次重试前的退避时间，attempt从1开始
+func (o *SubscribeOptions) Delay(attempt int) time.Duration {
+	delay := o.Backoff
+	for i := 1; i < attempt && delay < o.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if o.MaxBackoff > 0 && delay > o.MaxBackoff {
+		delay = o.MaxBackoff
+	}
+
+	return delay
+}
+
+// Retry 在当前协程内执行处理器，失败时按退避策略重试，返回最后一次处理的错误
+func (o *SubscribeOptions) Retry(ctx context.Context, event *Event, handler AckHandler) (err error) {
+	for attempt := 0; ; attempt++ {
+		if err = handler(ctx, event); err == nil || attempt >= o.MaxRetries {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(o.Delay(attempt + 1)):
+		}
+	}
+}
+
+// DeadLetterTopic 获取消费组的默认死信主题
+func DeadLetterTopic(topic, group string) string {
+	return topic + "." + group + ".dlq"
+}
+
+// WithMaxRetries 设置最大重试次数
+func WithMaxRetries(maxRetries int) SubscribeOption {
+	return func(o *SubscribeOptions) { o.MaxRetries = maxRetries }
+}
+
+// WithBackoff 设置重试退避时间
+func WithBackoff(backoff, maxBackoff time.Duration) SubscribeOption {
+	return func(o *SubscribeOptions) { o.Backoff, o.MaxBackoff = backoff, maxBackoff }
+}
+
+// WithDeadLetter 设置死信主题
+func WithDeadLetter(topic string) SubscribeOption {
+	return func(o *SubscribeOptions) { o.DeadLetter = topic }
+}
@@ -0,0 +1,82 @@
+package geventbus
+
+import (
+	"github.com/goodluck0107/gcore/gtask"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// Handlers 事件处理器集合
+// 供各事件总线实现管理同一主题下的处理器，零值可直接使用
+type Handlers struct {
+	rw       sync.RWMutex
+	count    int
+	handlers map[uintptr][]EventHandler
+}
+
+// Add 添加处理器，返回处理器数量
+func (h *Handlers) Add(handler EventHandler) int {
+	pointer := reflect.ValueOf(handler).Pointer()
+
+	h.rw.Lock()
+	defer h.rw.Unlock()
+
+	if h.handlers == nil {
+		h.handlers = make(map[uintptr][]EventHandler, 1)
+	}
+
+	h.handlers[pointer] = append(h.handlers[pointer], handler)
+	h.count++
+
+	return h.count
+}
+
+// Remove 移除处理器，返回剩余处理器数量
+// 同一函数字面量或方法生成的处理器代码指针相同，需按闭包对象区分；未找到对应的处理器时不做任何移除
+func (h *Handlers) Remove(handler EventHandler) int {
+	pointer := reflect.ValueOf(handler).Pointer()
+
+	h.rw.Lock()
+	defer h.rw.Unlock()
+
+	handlers, ok := h.handlers[pointer]
+	if !ok {
+		return h.count
+	}
+
+	remains := make([]EventHandler, 0, len(handlers))
+	for _, fn := range handlers {
+		if closure(fn) != closure(handler) {
+			remains = append(remains, fn)
+		}
+	}
+
+	h.count -= len(handlers) - len(remains)
+
+	if len(remains) == 0 {
+		delete(h.handlers, pointer)
+	} else {
+		h.handlers[pointer] = remains
+	}
+
+	return h.count
+}
+
+// Dispatch 分发事件，每个处理器作为独立任务执行
+func (h *Handlers) Dispatch(event *Event) {
+	h.rw.RLock()
+	defer h.rw.RUnlock()
+
+	for _, handlers := range h.handlers {
+		for i := range handlers {
+			handler := handlers[i]
+			gtask.AddTask(func() { handler(event) })
+		}
+	}
+}
+
+// 获取处理器的闭包对象地址
+func closure(handler EventHandler) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&handler))
+}
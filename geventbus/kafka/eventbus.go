@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 	"github.com/IBM/sarama"
+	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/geventbus"
 	"sync"
 )
@@ -15,12 +16,19 @@ type Eventbus struct {
 	err      error
 	err1     error
 	err2     error
+	config   *sarama.Config
 	consumer sarama.Consumer
 	producer sarama.AsyncProducer
 	builtin  bool
+	tracked  bool          // 是否可追踪每条消息的发布结果
+	acked    chan struct{} // 发布结果分发结束时关闭
+
+	mu     sync.RWMutex
+	closed bool
 
 	rw        sync.RWMutex
 	consumers map[string]*consumer
+	groups    map[string]map[string]*group
 }
 
 func NewEventbus(opts ...Option) *Eventbus {
@@ -32,11 +40,13 @@ func NewEventbus(opts ...Option) *Eventbus {
 	eb := &Eventbus{}
 	eb.opts = o
 	eb.consumers = make(map[string]*consumer)
+	eb.groups = make(map[string]map[string]*group)
 	eb.ctx, eb.cancel = context.WithCancel(o.ctx)
 
 	if o.client != nil {
 		eb.consumer, eb.err1 = sarama.NewConsumerFromClient(o.client)
 		eb.producer, eb.err2 = sarama.NewAsyncProducerFromClient(o.client)
+		eb.tracked = o.client.Config().Producer.Return.Successes && o.client.Config().Producer.Return.Errors
 	} else {
 		eb.builtin = true
 		config := sarama.NewConfig()
@@ -46,48 +56,116 @@ func NewEventbus(opts ...Option) *Eventbus {
 		config.Producer.RequiredAcks = sarama.WaitForAll
 		config.Producer.Return.Successes = true
 		config.Producer.Return.Errors = true
+		config.Producer.Flush.Frequency = o.linger
+		config.Producer.Flush.Messages = o.batchSize
+		eb.tracked = true
 
 		if o.version != "" {
 			config.Version, eb.err = sarama.ParseKafkaVersion(o.version)
 		}
 
+		eb.config = config
+
 		if eb.err == nil {
 			eb.consumer, eb.err1 = sarama.NewConsumer(o.addrs, config)
 			eb.producer, eb.err2 = sarama.NewAsyncProducer(o.addrs, config)
 		}
 	}
 
+	if eb.err == nil && eb.err2 == nil {
+		eb.acked = make(chan struct{})
+		go eb.ack()
+	}
+
 	return eb
 }
 
 // Publish 发布事件
+// 并发安全，阻塞等待至当前事件发布成功或失败
 func (eb *Eventbus) Publish(ctx context.Context, topic string, payload interface{}) error {
-	if eb.err != nil {
-		return eb.err
+	return eb.PublishAsync(ctx, topic, payload).Wait(ctx)
+}
+
+// PublishAsync 异步发布事件
+// 事件写入生产者队列后立即返回，可通过返回的Future或WithCallback设置的回调获取发布结果
+func (eb *Eventbus) PublishAsync(ctx context.Context, topic string, payload interface{}, opts ...PublishOption) *Future {
+	o := &publishOptions{}
+	for _, opt := range opts {
+		opt(o)
 	}
 
-	if eb.err2 != nil {
-		return eb.err2
+	if err := eb.producerErr(); err != nil {
+		f := newFuture(o.callback)
+		f.resolve(err)
+		return f
 	}
 
 	buf, err := serialize(topic, payload)
 	if err != nil {
-		return err
+		f := newFuture(o.callback)
+		f.resolve(err)
+		return f
+	}
+
+	return eb.publishAsync(ctx, topic, buf, o)
+}
+
+// PublishBatch 批量发布事件
+// 所有事件写入生产者队列后统一等待发布结果，返回首个发布失败的错误；批量大小与等待时间由WithBatchSize与WithLinger控制
+func (eb *Eventbus) PublishBatch(ctx context.Context, topic string, payloads []interface{}, opts ...PublishOption) error {
+	futures := make([]*Future, 0, len(payloads))
+	for _, payload := range payloads {
+		futures = append(futures, eb.PublishAsync(ctx, topic, payload, opts...))
+	}
+
+	var err error
+	for _, f := range futures {
+		if e := f.Wait(ctx); e != nil && err == nil {
+			err = e
+		}
 	}
 
-	eb.producer.Input() <- &sarama.ProducerMessage{
-		Topic: topic,
-		Value: sarama.ByteEncoder(buf),
+	return err
+}
+
+// 发布事件数据
+func (eb *Eventbus) publish(ctx context.Context, topic string, buf []byte) error {
+	return eb.publishAsync(ctx, topic, buf, &publishOptions{}).Wait(ctx)
+}
+
+// 异步发布事件数据
+func (eb *Eventbus) publishAsync(ctx context.Context, topic string, buf []byte, o *publishOptions) *Future {
+	f := newFuture(o.callback)
+
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	if eb.closed {
+		f.resolve(gerrors.ErrEventbusClosed)
+		return f
 	}
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
-	case <-eb.producer.Successes():
-		return nil
-	case err = <-eb.producer.Errors():
-		return err
+		f.resolve(ctx.Err())
+		return f
+	case eb.producer.Input() <- buildMessage(topic, buf, o, f, eb.tracked):
+	}
+
+	// 未开启发布结果返回时，写入生产者队列即视为发布成功
+	if !eb.tracked {
+		f.resolve(nil)
 	}
+
+	return f
+}
+
+func (eb *Eventbus) producerErr() error {
+	if eb.err != nil {
+		return eb.err
+	}
+
+	return eb.err2
 }
 
 // Subscribe 订阅事件
@@ -103,11 +181,11 @@ func (eb *Eventbus) Subscribe(_ context.Context, topic string, handler geventbus
 	eb.rw.Lock()
 	c, ok := eb.consumers[topic]
 	if !ok {
-		c = &consumer{handlers: make(map[uintptr][]geventbus.EventHandler)}
+		c = &consumer{}
 		c.ctx, c.cancel = context.WithCancel(eb.ctx)
 		eb.consumers[topic] = c
 	}
-	c.addHandler(handler)
+	c.handlers.Add(handler)
 	eb.rw.Unlock()
 
 	if !ok {
@@ -131,7 +209,7 @@ func (eb *Eventbus) Unsubscribe(_ context.Context, topic string, handler geventb
 	defer eb.rw.Unlock()
 
 	if c, ok := eb.consumers[topic]; ok {
-		if c.remHandler(handler) != 0 {
+		if c.handlers.Remove(handler) != 0 {
 			return nil
 		}
 		c.cancel()
@@ -141,6 +219,59 @@ func (eb *Eventbus) Unsubscribe(_ context.Context, topic string, handler geventb
 	return nil
 }
 
+// SubscribeGroup 以消费组订阅事件
+// 基于kafka消费组实现，事件处理完成或转入死信主题后才提交位移；同一进程内重复订阅同名消费组时，替换为最后一次订阅的处理器
+func (eb *Eventbus) SubscribeGroup(ctx context.Context, topic, name string, handler geventbus.AckHandler, opts ...geventbus.SubscribeOption) error {
+	if eb.err != nil {
+		return eb.err
+	}
+
+	if eb.err2 != nil {
+		return eb.err2
+	}
+
+	g, err := newGroup(eb, topic, name, handler, opts...)
+	if err != nil {
+		return err
+	}
+
+	eb.rw.Lock()
+	groups, ok := eb.groups[topic]
+	if !ok {
+		groups = make(map[string]*group)
+		eb.groups[topic] = groups
+	}
+	old := groups[name]
+	groups[name] = g
+	eb.rw.Unlock()
+
+	if old != nil {
+		return old.stop()
+	}
+
+	return nil
+}
+
+// UnsubscribeGroup 取消消费组订阅
+func (eb *Eventbus) UnsubscribeGroup(_ context.Context, topic, name string) error {
+	eb.rw.Lock()
+	g, ok := eb.groups[topic][name]
+	if ok {
+		delete(eb.groups[topic], name)
+
+		if len(eb.groups[topic]) == 0 {
+			delete(eb.groups, topic)
+		}
+	}
+	eb.rw.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return g.stop()
+}
+
 // Close 停止监听
 func (eb *Eventbus) Close() error {
 	if eb.err != nil {
@@ -157,18 +288,28 @@ func (eb *Eventbus) Close() error {
 
 	eb.cancel()
 
-	if !eb.builtin {
-		return nil
+	eb.rw.Lock()
+	for _, groups := range eb.groups {
+		for _, g := range groups {
+			_ = g.stop()
+		}
 	}
+	eb.groups = make(map[string]map[string]*group)
+	eb.rw.Unlock()
+
+	// 等待已写入队列的事件发布完成，并将结果分发至各自的Future
+	eb.mu.Lock()
+	eb.closed = true
+	eb.mu.Unlock()
 
-	err1 := eb.consumer.Close()
-	err2 := eb.producer.Close()
+	eb.producer.AsyncClose()
+	<-eb.acked
 
-	if err1 != nil {
-		return err1
+	if !eb.builtin {
+		return nil
 	}
 
-	return err2
+	return eb.consumer.Close()
 }
 
 func (eb *Eventbus) watch(c *consumer, topic string) error {
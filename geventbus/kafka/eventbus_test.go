@@ -101,3 +101,30 @@ func TestEventbus_Publish(t *testing.T) {
 
 	t.Log("publish success")
 }
+
+func TestEventbus_PublishAsync(t *testing.T) {
+	var (
+		eb  = kafka.NewEventbus(kafka.WithLinger(10*time.Millisecond), kafka.WithBatchSize(100))
+		ctx = context.Background()
+	)
+
+	defer eb.Close()
+
+	futures := make([]*kafka.Future, 0, 10)
+	for i := 0; i < 10; i++ {
+		futures = append(futures, eb.PublishAsync(ctx, loginTopic, i, kafka.WithKey("10001")))
+	}
+
+	for _, f := range futures {
+		if err := f.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := eb.PublishBatch(ctx, paidTopic, []interface{}{"paid1", "paid2"}, kafka.WithKey("10001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("publish success")
+}
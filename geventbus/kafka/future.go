@@ -0,0 +1,128 @@
+package kafka
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/goodluck0107/gcore/glog"
+	"sync"
+)
+
+type PublishOption func(o *publishOptions)
+
+type publishOptions struct {
+	key      string
+	callback func(err error)
+}
+
+// WithKey 设置分区键
+// 分区键相同的事件写入同一分区并保持发布顺序，例如以用户UID作为分区键
+func WithKey(key string) PublishOption {
+	return func(o *publishOptions) { o.key = key }
+}
+
+// WithCallback 设置发布结果回调
+// 回调在发布结果分发协程中执行，不应阻塞或在回调内同步等待其他发布结果
+func WithCallback(callback func(err error)) PublishOption {
+	return func(o *publishOptions) { o.callback = callback }
+}
+
+// Future 异步发布结果
+type Future struct {
+	once     sync.Once
+	done     chan struct{}
+	err      error
+	callback func(err error)
+}
+
+func newFuture(callback func(err error)) *Future {
+	return &Future{done: make(chan struct{}), callback: callback}
+}
+
+// Done 发布完成时关闭的通道
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Err 获取发布结果，发布未完成时返回nil
+func (f *Future) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Wait 等待发布完成并返回发布结果
+func (f *Future) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-f.done:
+		return f.err
+	}
+}
+
+// 设置发布结果；仅首次设置生效
+func (f *Future) resolve(err error) {
+	f.once.Do(func() {
+		f.err = err
+		close(f.done)
+
+		if f.callback != nil {
+			f.callback(err)
+		}
+	})
+}
+
+// 分发生产者的发布结果
+// 每条消息通过Metadata携带自身的发布结果，避免并发发布时相互获取到其他消息的确认或错误；
+// 结果不可追踪的消息不携带Future，其发布错误仅记录日志
+func (eb *Eventbus) ack() {
+	defer close(eb.acked)
+
+	successes, errors := eb.producer.Successes(), eb.producer.Errors()
+
+	for successes != nil || errors != nil {
+		select {
+		case msg, ok := <-successes:
+			if !ok {
+				successes = nil
+				continue
+			}
+
+			if f, ok := msg.Metadata.(*Future); ok {
+				f.resolve(nil)
+			}
+		case pe, ok := <-errors:
+			if !ok {
+				errors = nil
+				continue
+			}
+
+			if f, ok := pe.Msg.Metadata.(*Future); ok {
+				f.resolve(pe.Err)
+			} else {
+				glog.Errorf("event publish failed, topic: %s err: %v", pe.Msg.Topic, pe.Err)
+			}
+		}
+	}
+}
+
+// 构建生产者消息；仅发布结果可追踪时携带Future
+func buildMessage(topic string, buf []byte, o *publishOptions, f *Future, tracked bool) *sarama.ProducerMessage {
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(buf),
+	}
+
+	if tracked {
+		msg.Metadata = f
+	}
+
+	if o.key != "" {
+		msg.Key = sarama.StringEncoder(o.key)
+	}
+
+	return msg
+}
@@ -0,0 +1,135 @@
+package kafka
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/goodluck0107/gcore/geventbus"
+	"github.com/goodluck0107/gcore/glog"
+	"time"
+)
+
+const retryWait = 3 * time.Second // 消费组会话异常后的重试间隔
+
+// 消费组
+// 基于kafka消费组实现，分区内的事件顺序处理，处理失败时在原位置退避重试，超过最大重试次数后转入死信主题，之后才标记位移
+type group struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	eb      *Eventbus
+	topic   string
+	cg      sarama.ConsumerGroup
+	handler geventbus.AckHandler
+	opts    *geventbus.SubscribeOptions
+	done    chan struct{}
+}
+
+func newGroup(eb *Eventbus, topic, name string, handler geventbus.AckHandler, opts ...geventbus.SubscribeOption) (*group, error) {
+	var (
+		cg  sarama.ConsumerGroup
+		err error
+	)
+
+	if eb.builtin {
+		cg, err = sarama.NewConsumerGroup(eb.opts.addrs, name, eb.config)
+	} else {
+		cg, err = sarama.NewConsumerGroupFromClient(name, eb.opts.client)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	g := &group{}
+	g.ctx, g.cancel = context.WithCancel(eb.ctx)
+	g.eb = eb
+	g.topic = topic
+	g.cg = cg
+	g.handler = handler
+	g.opts = geventbus.NewSubscribeOptions(topic, name, opts...)
+	g.done = make(chan struct{})
+
+	go g.consume()
+
+	return g, nil
+}
+
+// 停止消费
+func (g *group) stop() error {
+	g.cancel()
+	<-g.done
+
+	return g.cg.Close()
+}
+
+func (g *group) consume() {
+	defer close(g.done)
+
+	for {
+		if err := g.cg.Consume(g.ctx, []string{g.topic}, g); err != nil {
+			if g.ctx.Err() != nil {
+				return
+			}
+
+			glog.Warnf("consumer group session failed, topic: %s err: %v", g.topic, err)
+
+			select {
+			case <-g.ctx.Done():
+			case <-time.After(retryWait):
+			}
+		}
+
+		if g.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// Setup 会话开始
+func (g *group) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup 会话结束
+func (g *group) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim 消费分区
+func (g *group) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := session.Context()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			event, err := deserialize(message.Value)
+			if err != nil {
+				glog.Errorf("invalid event data, topic: %s offset: %d", message.Topic, message.Offset)
+				session.MarkMessage(message, "")
+				continue
+			}
+
+			if err = g.opts.Retry(ctx, event, g.handler); err != nil {
+				// 会话结束时不标记位移，由下一个会话重新处理
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				glog.Warnf("event handle failed, topic: %s id: %s err: %v", event.Topic, event.ID, err)
+
+				if g.opts.DeadLetter != "" {
+					if err = g.eb.publish(ctx, g.opts.DeadLetter, message.Value); err != nil {
+						glog.Errorf("dead letter publish failed, topic: %s err: %v", g.opts.DeadLetter, err)
+						return err
+					}
+				}
+			}
+
+			session.MarkMessage(message, "")
+		}
+	}
+}
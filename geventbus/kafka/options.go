@@ -4,17 +4,21 @@ import (
 	"context"
 	"github.com/IBM/sarama"
 	"github.com/goodluck0107/gcore/getc"
+	"time"
 )
 
 const (
 	defaultAddr   = "127.0.0.1:9092"
 	defaultPrefix = "gcore"
+	defaultLinger = "0s"
 )
 
 const (
-	defaultAddrsKey   = "etc.eventbus.kafka.addrs"
-	defaultPrefixKey  = "etc.eventbus.kafka.prefix"
-	defaultVersionKey = "etc.eventbus.kafka.version"
+	defaultAddrsKey     = "etc.eventbus.kafka.addrs"
+	defaultPrefixKey    = "etc.eventbus.kafka.prefix"
+	defaultVersionKey   = "etc.eventbus.kafka.version"
+	defaultLingerKey    = "etc.eventbus.kafka.linger"
+	defaultBatchSizeKey = "etc.eventbus.kafka.batchSize"
 )
 
 type Option func(o *options)
@@ -33,6 +37,14 @@ type options struct {
 	// key前缀，默认为gcore
 	prefix string
 
+	// 批量发布的最大等待时间
+	// 内建客户端配置，默认为0，即不等待
+	linger time.Duration
+
+	// 批量发布的消息数量阈值
+	// 内建客户端配置，达到阈值时立即发送，默认为0，即不限制
+	batchSize int
+
 	// 客户端
 	// 外部客户端配置，存在外部客户端时，优先使用外部客户端，默认为nil
 	client sarama.Client
@@ -40,10 +52,12 @@ type options struct {
 
 func defaultOptions() *options {
 	return &options{
-		ctx:     context.Background(),
-		addrs:   getc.Get(defaultAddrsKey, []string{defaultAddr}).Strings(),
-		prefix:  getc.Get(defaultPrefixKey, defaultPrefix).String(),
-		version: getc.Get(defaultVersionKey).String(),
+		ctx:       context.Background(),
+		addrs:     getc.Get(defaultAddrsKey, []string{defaultAddr}).Strings(),
+		prefix:    getc.Get(defaultPrefixKey, defaultPrefix).String(),
+		version:   getc.Get(defaultVersionKey).String(),
+		linger:    getc.Get(defaultLingerKey, defaultLinger).Duration(),
+		batchSize: getc.Get(defaultBatchSizeKey).Int(),
 	}
 }
 
@@ -67,6 +81,16 @@ func WithVersion(version string) Option {
 	return func(o *options) { o.version = version }
 }
 
+// WithLinger 设置批量发布的最大等待时间
+func WithLinger(linger time.Duration) Option {
+	return func(o *options) { o.linger = linger }
+}
+
+// WithBatchSize 设置批量发布的消息数量阈值
+func WithBatchSize(batchSize int) Option {
+	return func(o *options) { o.batchSize = batchSize }
+}
+
 // WithClient 设置外部客户端
 func WithClient(client sarama.Client) Option {
 	return func(o *options) { o.client = client }
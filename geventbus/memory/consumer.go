@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/geventbus"
+	"github.com/goodluck0107/gcore/glog"
+)
+
+type consumer struct {
+	handlers geventbus.Handlers
+}
+
+// 分发数据
+func (c *consumer) dispatch(data []byte) {
+	event, err := deserialize(data)
+	if err != nil {
+		glog.Error("invalid event data")
+		return
+	}
+
+	c.handlers.Dispatch(event)
+}
+
+type group struct {
+	opts    *geventbus.SubscribeOptions
+	handler geventbus.AckHandler
+}
+
+// 分发数据；处理失败时在独立协程内退避重试，最终失败时将原始数据投递至死信主题
+func (g *group) dispatch(ctx context.Context, data []byte, deadLetter func(topic string, data []byte)) {
+	event, err := deserialize(data)
+	if err != nil {
+		glog.Error("invalid event data")
+		return
+	}
+
+	go func() {
+		if err := g.opts.Retry(ctx, event, g.handler); err != nil {
+			glog.Warnf("event handle failed, topic: %s id: %s err: %v", event.Topic, event.ID, err)
+
+			if g.opts.DeadLetter != "" && ctx.Err() == nil {
+				deadLetter(g.opts.DeadLetter, data)
+			}
+		}
+	}()
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/geventbus"
+	"sync"
+)
+
+// Eventbus 进程内事件总线
+// 与geventbus的默认事件总线不同，事件载荷会经过与远端事件总线一致的序列化过程，
+// 以保证单进程部署及集成测试中订阅方收到的载荷与生产环境一致
+type Eventbus struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	opts   *options
+
+	rw        sync.RWMutex
+	consumers map[string]*consumer
+	groups    map[string]map[string]*group
+}
+
+func NewEventbus(opts ...Option) *Eventbus {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	eb := &Eventbus{}
+	eb.ctx, eb.cancel = context.WithCancel(o.ctx)
+	eb.opts = o
+	eb.consumers = make(map[string]*consumer)
+	eb.groups = make(map[string]map[string]*group)
+
+	return eb
+}
+
+// Publish 发布事件
+func (eb *Eventbus) Publish(ctx context.Context, topic string, payload interface{}) error {
+	if err := eb.ctx.Err(); err != nil {
+		return err
+	}
+
+	buf, err := serialize(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	eb.dispatch(topic, buf)
+
+	return nil
+}
+
+// Subscribe 订阅事件
+func (eb *Eventbus) Subscribe(ctx context.Context, topic string, handler geventbus.EventHandler) error {
+	if err := eb.ctx.Err(); err != nil {
+		return err
+	}
+
+	eb.rw.Lock()
+	defer eb.rw.Unlock()
+
+	c, ok := eb.consumers[topic]
+	if !ok {
+		c = &consumer{}
+		eb.consumers[topic] = c
+	}
+
+	c.handlers.Add(handler)
+
+	return nil
+}
+
+// Unsubscribe 取消订阅
+func (eb *Eventbus) Unsubscribe(ctx context.Context, topic string, handler geventbus.EventHandler) error {
+	eb.rw.Lock()
+	defer eb.rw.Unlock()
+
+	if c, ok := eb.consumers[topic]; ok {
+		if c.handlers.Remove(handler) != 0 {
+			return nil
+		}
+
+		delete(eb.consumers, topic)
+	}
+
+	return nil
+}
+
+// SubscribeGroup 以消费组订阅事件
+// 进程内事件总线中每个消费组仅有一个处理器，重复订阅时替换为最后一次订阅的处理器
+func (eb *Eventbus) SubscribeGroup(ctx context.Context, topic, name string, handler geventbus.AckHandler, opts ...geventbus.SubscribeOption) error {
+	if err := eb.ctx.Err(); err != nil {
+		return err
+	}
+
+	eb.rw.Lock()
+	defer eb.rw.Unlock()
+
+	groups, ok := eb.groups[topic]
+	if !ok {
+		groups = make(map[string]*group)
+		eb.groups[topic] = groups
+	}
+
+	groups[name] = &group{opts: geventbus.NewSubscribeOptions(topic, name, opts...), handler: handler}
+
+	return nil
+}
+
+// UnsubscribeGroup 取消消费组订阅
+func (eb *Eventbus) UnsubscribeGroup(ctx context.Context, topic, name string) error {
+	eb.rw.Lock()
+	defer eb.rw.Unlock()
+
+	if groups, ok := eb.groups[topic]; ok {
+		delete(groups, name)
+
+		if len(groups) == 0 {
+			delete(eb.groups, topic)
+		}
+	}
+
+	return nil
+}
+
+// 分发数据
+func (eb *Eventbus) dispatch(topic string, buf []byte) {
+	eb.rw.RLock()
+	defer eb.rw.RUnlock()
+
+	if c, ok := eb.consumers[topic]; ok {
+		c.dispatch(buf)
+	}
+
+	for _, g := range eb.groups[topic] {
+		g.dispatch(eb.ctx, buf, eb.dispatch)
+	}
+}
+
+// Close 停止监听
+func (eb *Eventbus) Close() error {
+	eb.cancel()
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package memory_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gencoding/msgpack"
+	"github.com/goodluck0107/gcore/geventbus"
+	"github.com/goodluck0107/gcore/geventbus/memory"
+	"testing"
+	"time"
+)
+
+const loginTopic = "login"
+
+func TestEventbus_Publish(t *testing.T) {
+	var (
+		eb  = memory.NewEventbus()
+		ctx = context.Background()
+		ch  = make(chan *geventbus.Event, 1)
+	)
+	defer eb.Close()
+
+	err := eb.Subscribe(ctx, loginTopic, func(event *geventbus.Event) {
+		ch <- event
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = eb.Publish(ctx, loginTopic, 10001); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-ch:
+		if event.Topic != loginTopic || event.Payload.Int64() != 10001 {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("event not received")
+	}
+}
+
+func TestEventbus_SubscribeGroup(t *testing.T) {
+	var (
+		eb       = memory.NewEventbus()
+		ctx      = context.Background()
+		attempts = make(chan int, 3)
+		dlq      = make(chan *geventbus.Event, 1)
+		count    int
+	)
+	defer eb.Close()
+
+	err := eb.SubscribeGroup(ctx, loginTopic, "stat", func(ctx context.Context, event *geventbus.Event) error {
+		count++
+		attempts <- count
+		return context.DeadlineExceeded
+	}, geventbus.WithMaxRetries(2), geventbus.WithBackoff(10*time.Millisecond, 20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = eb.Subscribe(ctx, geventbus.DeadLetterTopic(loginTopic, "stat"), func(event *geventbus.Event) {
+		dlq <- event
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = eb.Publish(ctx, loginTopic, 10001); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-dlq:
+		if event.Topic != loginTopic || event.Payload.Int64() != 10001 {
+			t.Fatalf("unexpected dead letter: %+v", event)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("dead letter not received")
+	}
+
+	if n := len(attempts); n != 3 {
+		t.Fatalf("got %d attempts, want 3", n)
+	}
+}
+
+type loginEvent struct {
+	UID  int64
+	Name string
+}
+
+func TestTopic_Subscribe(t *testing.T) {
+	var (
+		eb    = memory.NewEventbus()
+		ctx   = context.Background()
+		ch    = make(chan *loginEvent, 1)
+		topic = geventbus.NewTopic[*loginEvent](loginTopic,
+			geventbus.WithEventbus(eb),
+			geventbus.WithCodec(msgpack.DefaultCodec),
+			geventbus.WithSchema("login", 2),
+		)
+	)
+	defer eb.Close()
+
+	err := topic.Subscribe(ctx, func(ctx context.Context, v *loginEvent) error {
+		if event, ok := geventbus.EventFromContext(ctx); !ok || event.Headers[geventbus.HeaderVersion] != "2" {
+			t.Errorf("unexpected event headers: %+v", event)
+		}
+
+		ch <- v
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = topic.Publish(ctx, &loginEvent{UID: 10001, Name: "fuxiao"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case v := <-ch:
+		if v.UID != 10001 || v.Name != "fuxiao" {
+			t.Fatalf("unexpected payload: %+v", v)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("event not received")
+	}
+
+	// 结构名称不一致的主题无法解码事件
+	other := geventbus.NewTopic[*loginEvent](loginTopic, geventbus.WithCodec(msgpack.DefaultCodec))
+	msg, err := topic.Encode(&loginEvent{UID: 10002})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = other.Decode(&geventbus.Event{Headers: msg.Headers}); err == nil {
+		t.Fatal("decode with mismatched schema succeeded")
+	}
+}
+
+func TestTopic_Unsubscribe(t *testing.T) {
+	var (
+		eb     = memory.NewEventbus()
+		ctx    = context.Background()
+		ch     = make(chan *loginEvent, 2)
+		first  = geventbus.NewTopic[*loginEvent](loginTopic, geventbus.WithEventbus(eb))
+		second = geventbus.NewTopic[*loginEvent](loginTopic, geventbus.WithEventbus(eb))
+	)
+	defer eb.Close()
+
+	for _, topic := range []*geventbus.Topic[*loginEvent]{first, second} {
+		if err := topic.Subscribe(ctx, func(ctx context.Context, v *loginEvent) error {
+			ch <- v
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 取消一个主题对象的订阅不影响同名的其他主题对象
+	if err := first.Unsubscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := first.Publish(ctx, &loginEvent{UID: 10001}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case v := <-ch:
+		if v.UID != 10001 {
+			t.Fatalf("unexpected payload: %+v", v)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("event not received")
+	}
+
+	select {
+	case v := <-ch:
+		t.Fatalf("unsubscribed topic received event: %+v", v)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -3,44 +3,12 @@ package nats
 import (
 	"github.com/goodluck0107/gcore/geventbus"
 	"github.com/goodluck0107/gcore/glog"
-	"github.com/goodluck0107/gcore/gtask"
 	"github.com/nats-io/nats.go"
-	"reflect"
-	"sync"
 )
 
 type consumer struct {
 	sub      *nats.Subscription
-	rw       sync.RWMutex
-	handlers map[uintptr][]geventbus.EventHandler
-}
-
-// 添加处理器
-func (c *consumer) addHandler(handler geventbus.EventHandler) int {
-	pointer := reflect.ValueOf(handler).Pointer()
-
-	c.rw.Lock()
-	defer c.rw.Unlock()
-
-	if _, ok := c.handlers[pointer]; !ok {
-		c.handlers[pointer] = make([]geventbus.EventHandler, 0, 1)
-	}
-
-	c.handlers[pointer] = append(c.handlers[pointer], handler)
-
-	return len(c.handlers[pointer])
-}
-
-// 移除处理器
-func (c *consumer) remHandler(handler geventbus.EventHandler) int {
-	pointer := reflect.ValueOf(handler).Pointer()
-
-	c.rw.Lock()
-	defer c.rw.Unlock()
-
-	delete(c.handlers, pointer)
-
-	return len(c.handlers)
+	handlers geventbus.Handlers
 }
 
 // 分发数据
@@ -51,13 +19,5 @@ func (c *consumer) dispatch(data []byte) {
 		return
 	}
 
-	c.rw.RLock()
-	defer c.rw.RUnlock()
-
-	for _, handlers := range c.handlers {
-		for i := range handlers {
-			handler := handlers[i]
-			gtask.AddTask(func() { handler(event) })
-		}
-	}
+	c.handlers.Dispatch(event)
 }
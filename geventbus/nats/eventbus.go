@@ -4,15 +4,18 @@ import (
 	"context"
 	"github.com/goodluck0107/gcore/geventbus"
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"sync"
 )
 
 type Eventbus struct {
 	err  error
 	opts *options
+	js   jetstream.JetStream
 
 	rw        sync.RWMutex
 	consumers map[string]*consumer
+	groups    map[string]map[string]*group
 }
 
 func NewEventbus(opts ...Option) *Eventbus {
@@ -24,11 +27,16 @@ func NewEventbus(opts ...Option) *Eventbus {
 	eb := &Eventbus{opts: o}
 	eb.opts = o
 	eb.consumers = make(map[string]*consumer)
+	eb.groups = make(map[string]map[string]*group)
 
 	if o.conn == nil {
 		o.conn, eb.err = nats.Connect(o.url, nats.Timeout(o.timeout))
 	}
 
+	if eb.err == nil {
+		eb.js, eb.err = jetstream.New(o.conn)
+	}
+
 	return eb
 }
 
@@ -57,7 +65,7 @@ func (eb *Eventbus) Subscribe(ctx context.Context, topic string, handler geventb
 
 	c, ok := eb.consumers[topic]
 	if !ok {
-		c = &consumer{handlers: make(map[uintptr][]geventbus.EventHandler)}
+		c = &consumer{}
 		sub, err := eb.opts.conn.Subscribe(topic, func(msg *nats.Msg) {
 			c.dispatch(msg.Data)
 		})
@@ -68,7 +76,7 @@ func (eb *Eventbus) Subscribe(ctx context.Context, topic string, handler geventb
 		eb.consumers[topic] = c
 	}
 
-	c.addHandler(handler)
+	c.handlers.Add(handler)
 
 	return nil
 }
@@ -83,7 +91,7 @@ func (eb *Eventbus) Unsubscribe(ctx context.Context, topic string, handler geven
 	defer eb.rw.Unlock()
 
 	if c, ok := eb.consumers[topic]; ok {
-		if c.remHandler(handler) != 0 {
+		if c.handlers.Remove(handler) != 0 {
 			return nil
 		}
 
@@ -97,12 +105,72 @@ func (eb *Eventbus) Unsubscribe(ctx context.Context, topic string, handler geven
 	return nil
 }
 
+// SubscribeGroup 以消费组订阅事件
+// 基于JetStream实现，首次订阅时为主题创建流与持久化消费者；同一进程内重复订阅同名消费组时，替换为最后一次订阅的处理器
+func (eb *Eventbus) SubscribeGroup(ctx context.Context, topic, name string, handler geventbus.AckHandler, opts ...geventbus.SubscribeOption) error {
+	if eb.err != nil {
+		return eb.err
+	}
+
+	g, err := newGroup(ctx, eb, topic, name, handler, opts...)
+	if err != nil {
+		return err
+	}
+
+	eb.rw.Lock()
+	defer eb.rw.Unlock()
+
+	groups, ok := eb.groups[topic]
+	if !ok {
+		groups = make(map[string]*group)
+		eb.groups[topic] = groups
+	}
+
+	if old, ok := groups[name]; ok {
+		old.stop()
+	}
+
+	groups[name] = g
+
+	return nil
+}
+
+// UnsubscribeGroup 取消消费组订阅
+func (eb *Eventbus) UnsubscribeGroup(ctx context.Context, topic, name string) error {
+	if eb.err != nil {
+		return eb.err
+	}
+
+	eb.rw.Lock()
+	defer eb.rw.Unlock()
+
+	if g, ok := eb.groups[topic][name]; ok {
+		g.stop()
+		delete(eb.groups[topic], name)
+
+		if len(eb.groups[topic]) == 0 {
+			delete(eb.groups, topic)
+		}
+	}
+
+	return nil
+}
+
 // Close 停止监听
 func (eb *Eventbus) Close() error {
 	if eb.err != nil {
 		return eb.err
 	}
 
+	eb.rw.Lock()
+	for _, groups := range eb.groups {
+		for _, g := range groups {
+			g.stop()
+		}
+	}
+	eb.groups = make(map[string]map[string]*group)
+	eb.rw.Unlock()
+
 	eb.opts.conn.Close()
 
 	return nil
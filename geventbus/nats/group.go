@@ -0,0 +1,132 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"github.com/goodluck0107/gcore/geventbus"
+	"github.com/goodluck0107/gcore/glog"
+	"github.com/nats-io/nats.go/jetstream"
+	"strings"
+)
+
+const streamPrefix = "EVENTBUS_"
+
+var streamNameReplacer = strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_")
+
+// 消费组
+// 基于JetStream持久化消费者实现，处理成功后确认事件；处理失败时按退避策略延迟重投，投递次数超过上限后通过JetStream转入死信主题，死信主题不存在对应的流时自动创建
+type group struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	eb      *Eventbus
+	handler geventbus.AckHandler
+	opts    *geventbus.SubscribeOptions
+	cc      jetstream.ConsumeContext
+}
+
+func newGroup(ctx context.Context, eb *Eventbus, topic, name string, handler geventbus.AckHandler, opts ...geventbus.SubscribeOption) (*group, error) {
+	g := &group{}
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+	g.eb = eb
+	g.handler = handler
+	g.opts = geventbus.NewSubscribeOptions(topic, name, opts...)
+
+	stream, err := eb.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     streamName(topic),
+		Subjects: []string{topic},
+	})
+	if err != nil {
+		g.cancel()
+		return nil, err
+	}
+
+	if g.opts.DeadLetter != "" {
+		if err = ensureStream(ctx, eb.js, g.opts.DeadLetter); err != nil {
+			g.cancel()
+			return nil, err
+		}
+	}
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       name,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		FilterSubject: topic,
+	})
+	if err != nil {
+		g.cancel()
+		return nil, err
+	}
+
+	if g.cc, err = consumer.Consume(g.handle); err != nil {
+		g.cancel()
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// 停止消费
+func (g *group) stop() {
+	g.cancel()
+	g.cc.Stop()
+}
+
+// 处理事件
+func (g *group) handle(msg jetstream.Msg) {
+	event, err := deserialize(msg.Data())
+	if err != nil {
+		glog.Errorf("invalid event data, subject: %s", msg.Subject())
+		_ = msg.Term()
+		return
+	}
+
+	if err = g.handler(g.ctx, event); err == nil {
+		if err = msg.Ack(); err != nil {
+			glog.Errorf("event ack failed, topic: %s id: %s err: %v", event.Topic, event.ID, err)
+		}
+		return
+	}
+
+	glog.Warnf("event handle failed, topic: %s id: %s err: %v", event.Topic, event.ID, err)
+
+	metadata, err := msg.Metadata()
+	if err != nil {
+		_ = msg.Nak()
+		return
+	}
+
+	if metadata.NumDelivered <= uint64(g.opts.MaxRetries) {
+		_ = msg.NakWithDelay(g.opts.Delay(int(metadata.NumDelivered)))
+		return
+	}
+
+	if g.opts.DeadLetter != "" {
+		if _, err = g.eb.js.Publish(g.ctx, g.opts.DeadLetter, msg.Data()); err != nil {
+			glog.Errorf("dead letter publish failed, topic: %s err: %v", g.opts.DeadLetter, err)
+			_ = msg.NakWithDelay(g.opts.MaxBackoff)
+			return
+		}
+	}
+
+	_ = msg.Term()
+}
+
+// 确保存在捕获该主题的流，不存在时创建
+func ensureStream(ctx context.Context, js jetstream.JetStream, subject string) error {
+	_, err := js.StreamNameBySubject(ctx, subject)
+	if err == nil || !errors.Is(err, jetstream.ErrStreamNotFound) {
+		return err
+	}
+
+	_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     streamName(subject),
+		Subjects: []string{subject},
+	})
+
+	return err
+}
+
+// 获取主题对应的流名称
+func streamName(topic string) string {
+	return streamPrefix + streamNameReplacer.Replace(topic)
+}
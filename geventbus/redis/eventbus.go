@@ -17,6 +17,7 @@ type Eventbus struct {
 
 	rw        sync.RWMutex
 	consumers map[string]*consumer
+	groups    map[string]map[string]*group
 }
 
 func NewEventbus(opts ...Option) *Eventbus {
@@ -44,6 +45,7 @@ func NewEventbus(opts ...Option) *Eventbus {
 	eb.opts = o
 	eb.sub = eb.opts.client.Subscribe(eb.ctx)
 	eb.consumers = make(map[string]*consumer)
+	eb.groups = make(map[string]map[string]*group)
 	go eb.watch()
 
 	return eb
@@ -56,7 +58,23 @@ func (eb *Eventbus) Publish(ctx context.Context, topic string, payload interface
 		return err
 	}
 
-	return eb.opts.client.Publish(ctx, eb.buildChannelKey(topic), buf).Err()
+	return eb.publish(ctx, topic, buf)
+}
+
+// 发布事件数据；同时广播给订阅者并写入事件流供消费组消费
+func (eb *Eventbus) publish(ctx context.Context, topic string, buf []byte) error {
+	_, err := eb.opts.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Publish(ctx, eb.buildChannelKey(topic), buf)
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: eb.buildStreamKey(topic),
+			MaxLen: eb.opts.streamSize,
+			Approx: true,
+			Values: []interface{}{dataField, buf},
+		})
+		return nil
+	})
+
+	return err
 }
 
 // Subscribe 订阅事件
@@ -71,11 +89,11 @@ func (eb *Eventbus) Subscribe(ctx context.Context, topic string, handler geventb
 
 	c, ok := eb.consumers[topic]
 	if !ok {
-		c = &consumer{handlers: make(map[uintptr][]geventbus.EventHandler, 1)}
+		c = &consumer{}
 		eb.consumers[topic] = c
 	}
 
-	c.addHandler(handler)
+	c.handlers.Add(handler)
 
 	return nil
 }
@@ -86,7 +104,7 @@ func (eb *Eventbus) Unsubscribe(ctx context.Context, topic string, handler geven
 	defer eb.rw.Unlock()
 
 	if c, ok := eb.consumers[topic]; ok {
-		if c.remHandler(handler) != 0 {
+		if c.handlers.Remove(handler) != 0 {
 			return nil
 		}
 
@@ -101,6 +119,52 @@ func (eb *Eventbus) Unsubscribe(ctx context.Context, topic string, handler geven
 	return nil
 }
 
+// SubscribeGroup 以消费组订阅事件
+// 基于redis Streams实现；同一进程内重复订阅同名消费组时，替换为最后一次订阅的处理器
+func (eb *Eventbus) SubscribeGroup(ctx context.Context, topic, name string, handler geventbus.AckHandler, opts ...geventbus.SubscribeOption) error {
+	g := newGroup(eb, topic, name, handler, opts...)
+
+	if err := g.start(ctx); err != nil {
+		return err
+	}
+
+	eb.rw.Lock()
+	groups, ok := eb.groups[topic]
+	if !ok {
+		groups = make(map[string]*group)
+		eb.groups[topic] = groups
+	}
+	old := groups[name]
+	groups[name] = g
+	eb.rw.Unlock()
+
+	if old != nil {
+		return old.stop(ctx)
+	}
+
+	return nil
+}
+
+// UnsubscribeGroup 取消消费组订阅
+func (eb *Eventbus) UnsubscribeGroup(ctx context.Context, topic, name string) error {
+	eb.rw.Lock()
+	g, ok := eb.groups[topic][name]
+	if ok {
+		delete(eb.groups[topic], name)
+
+		if len(eb.groups[topic]) == 0 {
+			delete(eb.groups, topic)
+		}
+	}
+	eb.rw.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return g.stop(ctx)
+}
+
 // watch 监听事件
 func (eb *Eventbus) watch() {
 	for {
@@ -138,6 +202,11 @@ func (eb *Eventbus) buildChannelKey(topic string) string {
 	}
 }
 
+// build stream key pass by topic
+func (eb *Eventbus) buildStreamKey(topic string) string {
+	return eb.buildChannelKey(topic) + ":stream"
+}
+
 // parse to topic from channel key
 func (eb *Eventbus) parseChannelKey(channel string) string {
 	if eb.opts.prefix == "" {
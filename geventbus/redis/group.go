@@ -0,0 +1,225 @@
+package redis
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/goodluck0107/gcore/geventbus"
+	"github.com/goodluck0107/gcore/glog"
+	"github.com/goodluck0107/gcore/gutils/guuid"
+	"strings"
+	"time"
+)
+
+const (
+	dataField    = "data"          // 事件流中存放事件数据的字段
+	readCount    = 16              // 单次读取的最大事件数
+	readBlock    = time.Second     // 单次读取的最大阻塞时间
+	pendingCount = 100             // 单次分页检查的最大待确认事件数
+	retryWait    = 3 * time.Second // 读取失败后的重试间隔
+)
+
+// 消费组
+// 基于redis Streams的消费组实现，处理成功后确认事件；未确认的事件按退避策略重新认领处理，投递次数超过上限后转入死信主题
+type group struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	eb       *Eventbus
+	name     string
+	consumer string
+	stream   string
+	handler  geventbus.AckHandler
+	opts     *geventbus.SubscribeOptions
+	done     chan struct{}
+}
+
+func newGroup(eb *Eventbus, topic, name string, handler geventbus.AckHandler, opts ...geventbus.SubscribeOption) *group {
+	g := &group{}
+	g.ctx, g.cancel = context.WithCancel(eb.ctx)
+	g.eb = eb
+	g.name = name
+	g.consumer = guuid.UUID()
+	g.stream = eb.buildStreamKey(topic)
+	g.handler = handler
+	g.opts = geventbus.NewSubscribeOptions(topic, name, opts...)
+	g.done = make(chan struct{})
+
+	return g
+}
+
+// 创建消费组并开始消费
+func (g *group) start(ctx context.Context) error {
+	err := g.eb.opts.client.XGroupCreateMkStream(ctx, g.stream, g.name, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	go g.consume()
+
+	return nil
+}
+
+// 停止消费；存在未确认的事件时保留消费者，以便其他消费者认领
+func (g *group) stop(ctx context.Context) error {
+	g.cancel()
+	<-g.done
+
+	pending, err := g.eb.opts.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream:   g.stream,
+		Group:    g.name,
+		Start:    "-",
+		End:      "+",
+		Count:    1,
+		Consumer: g.consumer,
+	}).Result()
+	if err != nil || len(pending) > 0 {
+		return err
+	}
+
+	return g.eb.opts.client.XGroupDelConsumer(ctx, g.stream, g.name, g.consumer).Err()
+}
+
+func (g *group) consume() {
+	defer close(g.done)
+
+	for {
+		if g.ctx.Err() != nil {
+			return
+		}
+
+		if err := g.reclaim(); err != nil {
+			g.wait(err)
+			continue
+		}
+
+		streams, err := g.eb.opts.client.XReadGroup(g.ctx, &redis.XReadGroupArgs{
+			Group:    g.name,
+			Consumer: g.consumer,
+			Streams:  []string{g.stream, ">"},
+			Count:    readCount,
+			Block:    readBlock,
+		}).Result()
+		if err != nil {
+			if err != redis.Nil {
+				g.wait(err)
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				g.handle(message)
+			}
+		}
+	}
+}
+
+// 认领退避时间已到的待确认事件；投递次数超过上限的事件转入死信主题
+// 按事件ID分页遍历全部待确认事件，避免头部事件长期处于退避中时阻塞其后事件的认领
+func (g *group) reclaim() error {
+	start := "-"
+
+	for {
+		pending, err := g.eb.opts.client.XPendingExt(g.ctx, &redis.XPendingExtArgs{
+			Stream: g.stream,
+			Group:  g.name,
+			Idle:   g.opts.Delay(1),
+			Start:  start,
+			End:    "+",
+			Count:  pendingCount,
+		}).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, p := range pending {
+			if err = g.claim(p); err != nil {
+				return err
+			}
+		}
+
+		if len(pending) < pendingCount || g.ctx.Err() != nil {
+			return nil
+		}
+
+		start = "(" + pending[len(pending)-1].ID
+	}
+}
+
+// 认领单个待确认事件
+func (g *group) claim(p redis.XPendingExt) error {
+	delay := g.opts.Delay(int(p.RetryCount))
+	if p.Idle < delay {
+		return nil
+	}
+
+	messages, err := g.eb.opts.client.XClaim(g.ctx, &redis.XClaimArgs{
+		Stream:   g.stream,
+		Group:    g.name,
+		Consumer: g.consumer,
+		MinIdle:  delay,
+		Messages: []string{p.ID},
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		if p.RetryCount > int64(g.opts.MaxRetries) {
+			g.deadLetter(message)
+		} else {
+			g.handle(message)
+		}
+	}
+
+	return nil
+}
+
+// 处理事件，处理成功后确认
+func (g *group) handle(message redis.XMessage) {
+	data, _ := message.Values[dataField].(string)
+
+	event, err := deserialize([]byte(data))
+	if err != nil {
+		glog.Errorf("invalid event data, id: %s", message.ID)
+		g.ack(message.ID)
+		return
+	}
+
+	if err = g.handler(g.ctx, event); err != nil {
+		glog.Warnf("event handle failed, topic: %s id: %s err: %v", event.Topic, event.ID, err)
+		return
+	}
+
+	g.ack(message.ID)
+}
+
+// 将事件原始数据投递至死信主题
+func (g *group) deadLetter(message redis.XMessage) {
+	if data, ok := message.Values[dataField].(string); ok && g.opts.DeadLetter != "" {
+		if err := g.eb.publish(g.ctx, g.opts.DeadLetter, []byte(data)); err != nil {
+			glog.Errorf("dead letter publish failed, topic: %s err: %v", g.opts.DeadLetter, err)
+			return
+		}
+	}
+
+	g.ack(message.ID)
+}
+
+func (g *group) ack(id string) {
+	if err := g.eb.opts.client.XAck(g.ctx, g.stream, g.name, id).Err(); err != nil && g.ctx.Err() == nil {
+		glog.Errorf("event ack failed, stream: %s id: %s err: %v", g.stream, id, err)
+	}
+}
+
+func (g *group) wait(err error) {
+	if g.ctx.Err() != nil {
+		return
+	}
+
+	glog.Warnf("event stream read failed, stream: %s err: %v", g.stream, err)
+
+	select {
+	case <-g.ctx.Done():
+	case <-time.After(retryWait):
+	}
+}
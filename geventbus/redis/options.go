@@ -11,6 +11,7 @@ const (
 	defaultDB         = 0
 	defaultMaxRetries = 3
 	defaultPrefix     = "gcore"
+	defaultStreamSize = 10000
 )
 
 const (
@@ -20,6 +21,7 @@ const (
 	defaultPrefixKey     = "etc.eventbus.redis.prefix"
 	defaultUsernameKey   = "etc.eventbus.redis.username"
 	defaultPasswordKey   = "etc.eventbus.redis.password"
+	defaultStreamSizeKey = "etc.eventbus.redis.streamSize"
 )
 
 type Option func(o *options)
@@ -54,6 +56,10 @@ type options struct {
 	// 前缀
 	// key前缀，默认为gcore
 	prefix string
+
+	// 事件流长度
+	// 每个主题的事件流保留的近似最大事件数，供消费组消费，默认为10000
+	streamSize int64
 }
 
 func defaultOptions() *options {
@@ -65,6 +71,7 @@ func defaultOptions() *options {
 		prefix:     getc.Get(defaultPrefixKey, defaultPrefix).String(),
 		username:   getc.Get(defaultUsernameKey).String(),
 		password:   getc.Get(defaultPasswordKey).String(),
+		streamSize: getc.Get(defaultStreamSizeKey, defaultStreamSize).Int64(),
 	}
 }
 
@@ -107,3 +114,8 @@ func WithClient(client redis.UniversalClient) Option {
 func WithPrefix(prefix string) Option {
 	return func(o *options) { o.prefix = prefix }
 }
+
+// WithStreamSize 设置事件流长度
+func WithStreamSize(size int64) Option {
+	return func(o *options) { o.streamSize = size }
+}
@@ -10,18 +10,22 @@ import (
 )
 
 type data struct {
-	ID        string `json:"id"`        // 事件ID
-	Topic     string `json:"topic"`     // 事件主题
-	Payload   string `json:"payload"`   // 事件载荷
-	Timestamp int64  `json:"timestamp"` // 事件时间
+	ID        string            `json:"id"`                // 事件ID
+	Topic     string            `json:"topic"`             // 事件主题
+	Payload   string            `json:"payload"`           // 事件载荷
+	Headers   map[string]string `json:"headers,omitempty"` // 事件头
+	Timestamp int64             `json:"timestamp"`         // 事件时间
 }
 
 // 序列化
 func serialize(topic string, payload interface{}) ([]byte, error) {
+	headers, payload := geventbus.Unwrap(payload)
+
 	return json.Marshal(&data{
 		ID:        guuid.UUID(),
 		Topic:     topic,
 		Payload:   gconv.String(payload),
+		Headers:   headers,
 		Timestamp: gtime.Now().UnixNano(),
 	})
 }
@@ -39,6 +43,7 @@ func deserialize(v []byte) (*geventbus.Event, error) {
 		ID:        d.ID,
 		Topic:     d.Topic,
 		Payload:   value.NewValue(d.Payload),
+		Headers:   d.Headers,
 		Timestamp: gtime.UnixNano(d.Timestamp),
 	}, nil
 }
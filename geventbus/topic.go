@@ -0,0 +1,254 @@
+package geventbus
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/goodluck0107/gcore/gencoding"
+	"github.com/goodluck0107/gcore/gencoding/json"
+	"github.com/goodluck0107/gcore/glog"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+const (
+	HeaderSchema   = "schema"         // 载荷结构名称
+	HeaderVersion  = "schema-version" // 载荷结构版本
+	HeaderCodec    = "codec"          // 载荷编解码器
+	HeaderEncoding = "encoding"       // 载荷文本编码，二进制编解码器的载荷以base64编码传输
+)
+
+const encodingBase64 = "base64"
+
+type eventKey struct{}
+
+type TopicOption func(o *topicOptions)
+
+type topicOptions struct {
+	eventbus Eventbus        // 事件总线，默认为全局事件总线
+	codec    gencoding.Codec // 编解码器，默认为json
+	schema   string          // 载荷结构名称，默认为载荷类型名称
+	version  int             // 载荷结构版本，默认为1
+}
+
+// Topic 类型化事件主题
+// 载荷使用主题的编解码器编码，并通过事件头携带结构名称与版本，便于生产者与消费者独立演进
+type Topic[T any] struct {
+	name    string
+	opts    *topicOptions
+	handler EventHandler // 底层订阅的处理器；事件总线按处理器指针区分订阅，每个主题对象需持有独立的闭包
+
+	rw         sync.RWMutex
+	handlers   []func(ctx context.Context, v T) error
+	subscribed bool
+}
+
+// NewTopic 创建类型化事件主题
+func NewTopic[T any](name string, opts ...TopicOption) *Topic[T] {
+	o := &topicOptions{
+		codec:   json.DefaultCodec,
+		schema:  schemaName[T](),
+		version: 1,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	t := &Topic[T]{name: name, opts: o}
+	t.handler = func(event *Event) { t.dispatch(event) }
+
+	return t
+}
+
+// Name 获取主题名称
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish 发布事件
+func (t *Topic[T]) Publish(ctx context.Context, v T) error {
+	msg, err := t.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	return t.eventbus().Publish(ctx, t.name, msg)
+}
+
+// Subscribe 订阅事件
+// 同一主题对象的多个处理器共享一个底层订阅，处理器返回的错误仅记录日志
+func (t *Topic[T]) Subscribe(ctx context.Context, handler func(ctx context.Context, v T) error) error {
+	t.rw.Lock()
+	defer t.rw.Unlock()
+
+	t.handlers = append(t.handlers, handler)
+
+	if t.subscribed {
+		return nil
+	}
+
+	if err := t.eventbus().Subscribe(ctx, t.name, t.handler); err != nil {
+		t.handlers = t.handlers[:len(t.handlers)-1]
+		return err
+	}
+
+	t.subscribed = true
+
+	return nil
+}
+
+// Unsubscribe 取消主题对象的全部订阅
+func (t *Topic[T]) Unsubscribe(ctx context.Context) error {
+	t.rw.Lock()
+	defer t.rw.Unlock()
+
+	if !t.subscribed {
+		return nil
+	}
+
+	if err := t.eventbus().Unsubscribe(ctx, t.name, t.handler); err != nil {
+		return err
+	}
+
+	t.handlers = nil
+	t.subscribed = false
+
+	return nil
+}
+
+// SubscribeGroup 以消费组订阅事件
+// 载荷解码失败的事件与处理失败的事件一样按退避策略重试，最终投递至死信主题
+func (t *Topic[T]) SubscribeGroup(ctx context.Context, group string, handler func(ctx context.Context, v T) error, opts ...SubscribeOption) error {
+	return t.eventbus().SubscribeGroup(ctx, t.name, group, func(ctx context.Context, event *Event) error {
+		v, err := t.Decode(event)
+		if err != nil {
+			return err
+		}
+
+		return handler(context.WithValue(ctx, eventKey{}, event), v)
+	}, opts...)
+}
+
+// UnsubscribeGroup 取消消费组订阅
+func (t *Topic[T]) UnsubscribeGroup(ctx context.Context, group string) error {
+	return t.eventbus().UnsubscribeGroup(ctx, t.name, group)
+}
+
+// Encode 编码载荷，生成携带事件头的事件载荷
+func (t *Topic[T]) Encode(v T) (*Message, error) {
+	buf, err := t.opts.codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{
+		HeaderSchema:  t.opts.schema,
+		HeaderVersion: strconv.Itoa(t.opts.version),
+		HeaderCodec:   t.opts.codec.Name(),
+	}
+
+	if t.opts.codec.Name() == json.Name {
+		return &Message{Headers: headers, Payload: string(buf)}, nil
+	}
+
+	headers[HeaderEncoding] = encodingBase64
+
+	return &Message{Headers: headers, Payload: base64.StdEncoding.EncodeToString(buf)}, nil
+}
+
+// Decode 解码事件载荷
+// 事件的结构名称与主题不一致或编解码器不一致时返回错误；结构版本的兼容由编解码器自身处理
+func (t *Topic[T]) Decode(event *Event) (v T, err error) {
+	if schema, ok := event.Headers[HeaderSchema]; ok && schema != t.opts.schema {
+		err = fmt.Errorf("mismatched event schema, got %s want %s", schema, t.opts.schema)
+		return
+	}
+
+	if codec, ok := event.Headers[HeaderCodec]; ok && codec != t.opts.codec.Name() {
+		err = fmt.Errorf("mismatched event codec, got %s want %s", codec, t.opts.codec.Name())
+		return
+	}
+
+	buf := []byte(event.Payload.String())
+
+	if event.Headers[HeaderEncoding] == encodingBase64 {
+		if buf, err = base64.StdEncoding.DecodeString(string(buf)); err != nil {
+			return
+		}
+	}
+
+	// 指针类型的载荷需先分配内存，以便proto等编解码器直接解码
+	if rt := reflect.TypeOf(v); rt != nil && rt.Kind() == reflect.Ptr {
+		v = reflect.New(rt.Elem()).Interface().(T)
+		err = t.opts.codec.Unmarshal(buf, v)
+	} else {
+		err = t.opts.codec.Unmarshal(buf, &v)
+	}
+
+	return
+}
+
+// 分发事件至类型化处理器
+func (t *Topic[T]) dispatch(event *Event) {
+	v, err := t.Decode(event)
+	if err != nil {
+		glog.Errorf("event decode failed, topic: %s id: %s err: %v", event.Topic, event.ID, err)
+		return
+	}
+
+	t.rw.RLock()
+	handlers := t.handlers
+	t.rw.RUnlock()
+
+	ctx := context.WithValue(context.Background(), eventKey{}, event)
+
+	for _, handler := range handlers {
+		if err = handler(ctx, v); err != nil {
+			glog.Warnf("event handle failed, topic: %s id: %s err: %v", event.Topic, event.ID, err)
+		}
+	}
+}
+
+func (t *Topic[T]) eventbus() Eventbus {
+	if t.opts.eventbus != nil {
+		return t.opts.eventbus
+	}
+
+	return globalEventbus
+}
+
+// EventFromContext 从类型化处理器的上下文中获取原始事件，可用于读取事件头
+func EventFromContext(ctx context.Context) (*Event, bool) {
+	event, ok := ctx.Value(eventKey{}).(*Event)
+	return event, ok
+}
+
+// WithEventbus 设置主题使用的事件总线
+func WithEventbus(eb Eventbus) TopicOption {
+	return func(o *topicOptions) { o.eventbus = eb }
+}
+
+// WithCodec 设置主题使用的编解码器
+func WithCodec(codec gencoding.Codec) TopicOption {
+	return func(o *topicOptions) { o.codec = codec }
+}
+
+// WithSchema 设置载荷结构名称与版本
+func WithSchema(schema string, version int) TopicOption {
+	return func(o *topicOptions) { o.schema, o.version = schema, version }
+}
+
+// 获取载荷类型名称
+func schemaName[T any]() string {
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt.PkgPath() == "" {
+		return rt.String()
+	}
+
+	return rt.PkgPath() + "." + rt.Name()
+}
@@ -19,6 +19,16 @@ func Has(key string) bool {
 	return commandLine.has(key)
 }
 
+// Values 获取所有命令行参数
+func Values() map[string]string {
+	values := make(map[string]string, len(commandLine.values))
+	for key, val := range commandLine.values {
+		values[key] = val
+	}
+
+	return values
+}
+
 func String(key string, def ...string) string {
 	return commandLine.string(key, def...)
 }
@@ -0,0 +1,43 @@
+package gidgen
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+)
+
+var globalGenerator Generator
+
+type Generator interface {
+	// Next 生成下一个ID
+	Next(ctx context.Context) (int64, error)
+	// Close 关闭生成器；释放租用的资源
+	Close() error
+}
+
+// SetGenerator 设置ID生成器
+func SetGenerator(generator Generator) {
+	globalGenerator = generator
+}
+
+// GetGenerator 获取ID生成器
+func GetGenerator() Generator {
+	return globalGenerator
+}
+
+// Next 生成下一个ID
+func Next(ctx context.Context) (int64, error) {
+	if globalGenerator == nil {
+		return 0, gerrors.ErrMissIDGenerator
+	}
+
+	return globalGenerator.Next(ctx)
+}
+
+// Close 关闭ID生成器
+func Close() error {
+	if globalGenerator == nil {
+		return nil
+	}
+
+	return globalGenerator.Close()
+}
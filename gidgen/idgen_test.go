@@ -0,0 +1,319 @@
+package gidgen_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gidgen"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"github.com/goodluck0107/gcore/gregistry"
+	"github.com/goodluck0107/gcore/gregistry/memory"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 基于内存的简易缓存；仅实现ID生成器所需的方法
+type memoryKvDB struct {
+	gkvdb.KvDB
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+func newMemoryKvDB() *memoryKvDB {
+	return &memoryKvDB{values: make(map[string]interface{})}
+}
+
+func (db *memoryKvDB) Get(_ context.Context, key string, _ ...interface{}) gkvdb.Result {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if val, ok := db.values[key]; ok {
+		return gkvdb.NewResult(val)
+	}
+
+	return gkvdb.NewResult(nil, gerrors.ErrNil)
+}
+
+func (db *memoryKvDB) Set(_ context.Context, key string, value interface{}, _ ...time.Duration) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.values[key] = value
+
+	return nil
+}
+
+func (db *memoryKvDB) SetNX(_ context.Context, key string, value interface{}, _ ...time.Duration) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.values[key]; ok {
+		return false, nil
+	}
+
+	db.values[key] = value
+
+	return true, nil
+}
+
+func (db *memoryKvDB) CompareAndSwap(_ context.Context, key string, old, new interface{}, _ ...time.Duration) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if val, ok := db.values[key]; !ok || val != old {
+		return false, nil
+	}
+
+	db.values[key] = new
+
+	return true, nil
+}
+
+func (db *memoryKvDB) CompareAndDelete(_ context.Context, key string, old interface{}) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if val, ok := db.values[key]; !ok || val != old {
+		return false, nil
+	}
+
+	delete(db.values, key)
+
+	return true, nil
+}
+
+func (db *memoryKvDB) Delete(_ context.Context, keys ...string) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, key := range keys {
+		delete(db.values, key)
+	}
+
+	return true, nil
+}
+
+func (db *memoryKvDB) IncrInt(_ context.Context, key string, value int64) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	val, _ := db.values[key].(int64)
+	val += value
+	db.values[key] = val
+
+	return val, nil
+}
+
+func TestSnowflake(t *testing.T) {
+	generator, err := gidgen.NewSnowflake(gidgen.WithWorkerID(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last int64
+	for i := 0; i < 100000; i++ {
+		id, err := generator.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id <= last {
+			t.Fatalf("id is not increasing: %d <= %d", id, last)
+		}
+
+		last = id
+	}
+
+	at, workerID, _ := generator.Decompose(last)
+	if workerID != 5 || time.Since(at) > time.Second {
+		t.Fatalf("unexpected decomposition: %s %d", at, workerID)
+	}
+
+	if _, err = gidgen.NewSnowflake(gidgen.WithWorkerID(1024)); !gerrors.Is(err, gerrors.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestSnowflakeLeaser(t *testing.T) {
+	db, registry := newMemoryKvDB(), memory.NewRegistry()
+
+	for _, newLeaser := range []func() gidgen.Leaser{
+		func() gidgen.Leaser { return gidgen.NewKvDBLeaser(db) },
+		func() gidgen.Leaser { return gidgen.NewRegistryLeaser(registry) },
+	} {
+		workers := make(map[int64]bool)
+		generators := make([]*gidgen.Snowflake, 0, 4)
+
+		for i := 0; i < 4; i++ {
+			generator, err := gidgen.NewSnowflake(gidgen.WithWorkerBits(2), gidgen.WithLeaser(newLeaser()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if workers[generator.WorkerID()] {
+				t.Fatalf("worker id %d is leased twice", generator.WorkerID())
+			}
+
+			workers[generator.WorkerID()] = true
+			generators = append(generators, generator)
+		}
+
+		if _, err := gidgen.NewSnowflake(gidgen.WithWorkerBits(2), gidgen.WithLeaser(newLeaser())); !gerrors.Is(err, gerrors.ErrNoAvailableWorker) {
+			t.Fatalf("expected ErrNoAvailableWorker, got %v", err)
+		}
+
+		if err := generators[0].Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		generator, err := gidgen.NewSnowflake(gidgen.WithWorkerBits(2), gidgen.WithLeaser(newLeaser()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if generator.WorkerID() != generators[0].WorkerID() {
+			t.Fatalf("released worker id %d is not reused", generators[0].WorkerID())
+		}
+
+		for _, g := range append(generators[1:], generator) {
+			if err = g.Close(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// 首次拉取实例列表时隐藏全部实例，模拟并发注册时读取到的过期列表
+type staleRegistry struct {
+	*memory.Registry
+	stale atomic.Bool
+}
+
+func (r *staleRegistry) Services(ctx context.Context, serviceName string) ([]*gregistry.ServiceInstance, error) {
+	if r.stale.Swap(false) {
+		return nil, nil
+	}
+
+	return r.Registry.Services(ctx, serviceName)
+}
+
+func TestRegistryLeaser_Conflict(t *testing.T) {
+	registry := &staleRegistry{Registry: memory.NewRegistry()}
+	registry.stale.Store(true)
+
+	// 已持有工作节点ID的实例，其实例ID大于任何UUID
+	holder := &gregistry.ServiceInstance{ID: "~", Name: "idgen-worker", Kind: "idgen", Alias: "0", State: "work", Endpoint: "idgen://worker"}
+	if err := registry.Register(context.Background(), holder); err != nil {
+		t.Fatal(err)
+	}
+
+	leaser := gidgen.NewRegistryLeaser(registry)
+	if workerID, err := leaser.Lease(context.Background(), 0); !gerrors.Is(err, gerrors.ErrNoAvailableWorker) {
+		t.Fatalf("expected ErrNoAvailableWorker, got worker id %d and %v", workerID, err)
+	}
+}
+
+func TestRegistryLeaser_Lost(t *testing.T) {
+	registry := memory.NewRegistry()
+
+	leaser := gidgen.NewRegistryLeaser(registry)
+	if _, err := leaser.Lease(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	defer leaser.Release(context.Background())
+
+	if !leaser.Alive() {
+		t.Fatal("lease is not alive")
+	}
+
+	// 模拟注册中心因健康检查失败移除实例
+	services, err := registry.Services(context.Background(), "idgen-worker")
+	if err != nil || len(services) != 1 {
+		t.Fatalf("unexpected services: %v %v", services, err)
+	}
+
+	if err = registry.Deregister(context.Background(), services[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for leaser.Alive() {
+		if time.Now().After(deadline) {
+			t.Fatal("lease is still alive after instance is removed")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestModule(t *testing.T) {
+	generator, err := gidgen.NewSnowflake(gidgen.WithWorkerID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	module := gidgen.NewModule(generator)
+	module.Init()
+
+	if _, err = gidgen.Next(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = module.TryDestroy(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = gidgen.Next(context.Background()); !gerrors.Is(err, gerrors.ErrMissIDGenerator) {
+		t.Fatalf("expected ErrMissIDGenerator, got %v", err)
+	}
+}
+
+func TestSegment(t *testing.T) {
+	db := newMemoryKvDB()
+
+	generators := make([]*gidgen.Segment, 3)
+	for i := range generators {
+		generator, err := gidgen.NewSegment("order", gidgen.WithKvDB(db), gidgen.WithStep(10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		generators[i] = generator
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ids = make(map[int64]bool)
+	)
+
+	for _, generator := range generators {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(generator *gidgen.Segment) {
+				defer wg.Done()
+
+				for j := 0; j < 250; j++ {
+					id, err := generator.Next(context.Background())
+					if err != nil {
+						t.Error(err)
+						return
+					}
+
+					mu.Lock()
+					if ids[id] {
+						t.Errorf("duplicate id %d", id)
+					}
+					ids[id] = true
+					mu.Unlock()
+				}
+			}(generator)
+		}
+	}
+
+	wg.Wait()
+
+	if len(ids) != 3000 {
+		t.Fatalf("expected 3000 ids, got %d", len(ids))
+	}
+}
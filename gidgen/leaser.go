@@ -0,0 +1,339 @@
+package gidgen
+
+import (
+	"context"
+	"fmt"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"github.com/goodluck0107/gcore/glog"
+	"github.com/goodluck0107/gcore/gregistry"
+	"github.com/goodluck0107/gcore/gutils/guuid"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultLeaseTTL     = 30 * time.Second // 默认工作节点ID租约时长
+	defaultLeaseService = "idgen-worker"   // 默认工作节点ID注册的服务名称
+	maxLeaseAttempts    = 10               // 基于服务注册的最大租用尝试次数
+)
+
+// Leaser 工作节点ID租约器
+type Leaser interface {
+	// Lease 租用工作节点ID；maxWorkerID 为允许的最大工作节点ID
+	Lease(ctx context.Context, maxWorkerID int64) (int64, error)
+	// Alive 租约是否仍然有效
+	Alive() bool
+	// Release 释放工作节点ID
+	Release(ctx context.Context) error
+}
+
+type kvdbLeaser struct {
+	db      gkvdb.KvDB
+	ttl     time.Duration
+	token   string
+	key     string
+	alive   atomic.Bool
+	cancel  context.CancelFunc
+	chRenew chan struct{}
+}
+
+// NewKvDBLeaser 新建基于gkvdb的工作节点ID租约器
+// 通过 SetNX 抢占工作节点ID，并在后台按租约时长的1/3周期以 CompareAndSwap 续租
+func NewKvDBLeaser(db gkvdb.KvDB, ttl ...time.Duration) Leaser {
+	l := &kvdbLeaser{db: db, ttl: defaultLeaseTTL, token: guuid.UUID()}
+
+	if len(ttl) > 0 && ttl[0] > 0 {
+		l.ttl = ttl[0]
+	}
+
+	return l
+}
+
+// Lease 租用工作节点ID
+func (l *kvdbLeaser) Lease(ctx context.Context, maxWorkerID int64) (int64, error) {
+	offset := rand.Int64N(maxWorkerID + 1)
+
+	for i := int64(0); i <= maxWorkerID; i++ {
+		workerID := (offset + i) % (maxWorkerID + 1)
+		key := "idgen:worker:" + strconv.FormatInt(workerID, 10)
+
+		ok, err := l.db.SetNX(ctx, key, l.token, l.ttl)
+		if err != nil {
+			return 0, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		l.key = key
+		l.alive.Store(true)
+		l.chRenew = make(chan struct{})
+
+		var renewCtx context.Context
+		renewCtx, l.cancel = context.WithCancel(context.Background())
+
+		go l.renew(renewCtx)
+
+		return workerID, nil
+	}
+
+	return 0, gerrors.ErrNoAvailableWorker
+}
+
+// Alive 租约是否仍然有效
+func (l *kvdbLeaser) Alive() bool {
+	return l.alive.Load()
+}
+
+// Release 释放工作节点ID
+func (l *kvdbLeaser) Release(ctx context.Context) error {
+	if l.cancel == nil {
+		return nil
+	}
+
+	l.cancel()
+	<-l.chRenew
+
+	if !l.alive.Swap(false) {
+		return nil
+	}
+
+	_, err := l.db.CompareAndDelete(ctx, l.key, l.token)
+
+	return err
+}
+
+// 续租；连续续租失败超过租约时长的2/3时租约失效，为时钟偏差与网络延迟预留安全余量，避免在键过期后仍继续发号
+func (l *kvdbLeaser) renew(ctx context.Context) {
+	defer close(l.chRenew)
+
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ok, err := l.db.CompareAndSwap(ctx, l.key, l.token, l.token, l.ttl)
+
+		switch {
+		case err == nil && ok:
+			renewed = time.Now()
+		case err == nil:
+			glog.Errorf("worker lease %s is taken by another instance", l.key)
+			l.alive.Store(false)
+			return
+		case time.Since(renewed) >= l.ttl*2/3:
+			glog.Errorf("worker lease %s expired: %v", l.key, err)
+			l.alive.Store(false)
+			return
+		default:
+			glog.Warnf("renew worker lease %s failed: %v", l.key, err)
+		}
+	}
+}
+
+type registryLeaser struct {
+	registry gregistry.Registry
+	name     string
+	mu       sync.Mutex
+	instance *gregistry.ServiceInstance
+	alive    atomic.Bool
+	cancel   context.CancelFunc
+	chWatch  chan struct{}
+}
+
+// NewRegistryLeaser 新建基于服务注册的工作节点ID租约器
+// 工作节点ID以服务实例的形式注册，租约有效期由注册中心的健康检查维持；
+// 租用成功后持续监听服务实例列表，实例被注册中心移除时租约失效
+func NewRegistryLeaser(registry gregistry.Registry, name ...string) Leaser {
+	l := &registryLeaser{registry: registry, name: defaultLeaseService}
+
+	if len(name) > 0 && name[0] != "" {
+		l.name = name[0]
+	}
+
+	return l
+}
+
+// Lease 租用工作节点ID
+// 注册后再次拉取实例列表，发现其他实例注册了相同的工作节点ID时主动退让并重新选择；
+// 已确认持有的实例不再复查，因此冲突总是以先注册者保留的方式解决，同时注册的实例均退让后重试
+func (l *registryLeaser) Lease(ctx context.Context, maxWorkerID int64) (int64, error) {
+	ins := &gregistry.ServiceInstance{
+		ID:       guuid.UUID(),
+		Name:     l.name,
+		Kind:     "idgen",
+		State:    "work",
+		Endpoint: "idgen://worker",
+	}
+
+	for attempt := 0; attempt < maxLeaseAttempts; attempt++ {
+		used, err := l.used(ctx, ins.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		workerID := int64(-1)
+		offset := rand.Int64N(maxWorkerID + 1)
+		for i := int64(0); i <= maxWorkerID; i++ {
+			if id := (offset + i) % (maxWorkerID + 1); !used[id] {
+				workerID = id
+				break
+			}
+		}
+
+		if workerID < 0 {
+			return 0, gerrors.ErrNoAvailableWorker
+		}
+
+		ins.Alias = strconv.FormatInt(workerID, 10)
+
+		if err = l.registry.Register(ctx, ins); err != nil {
+			return 0, err
+		}
+
+		services, err := l.registry.Services(ctx, l.name)
+		if err != nil {
+			_ = l.registry.Deregister(ctx, ins)
+			return 0, err
+		}
+
+		conflicted := false
+		for _, service := range services {
+			if service.ID != ins.ID && service.Alias == ins.Alias {
+				conflicted = true
+				break
+			}
+		}
+
+		if !conflicted {
+			if err = l.hold(ins); err != nil {
+				_ = l.registry.Deregister(ctx, ins)
+				return 0, err
+			}
+
+			return workerID, nil
+		}
+
+		if err = l.registry.Deregister(ctx, ins); err != nil {
+			return 0, err
+		}
+
+		time.Sleep(time.Duration(rand.Int64N(int64(50 * time.Millisecond))))
+	}
+
+	return 0, gerrors.NewError(fmt.Sprintf("lease worker id failed after %d attempts", maxLeaseAttempts), gerrors.ErrNoAvailableWorker)
+}
+
+// Alive 租约是否仍然有效
+func (l *registryLeaser) Alive() bool {
+	return l.alive.Load()
+}
+
+// Release 释放工作节点ID
+func (l *registryLeaser) Release(ctx context.Context) error {
+	l.mu.Lock()
+	ins, cancel, chWatch := l.instance, l.cancel, l.chWatch
+	l.instance, l.cancel, l.chWatch = nil, nil, nil
+	l.mu.Unlock()
+
+	if ins == nil {
+		return nil
+	}
+
+	cancel()
+	<-chWatch
+
+	l.alive.Store(false)
+
+	return l.registry.Deregister(ctx, ins)
+}
+
+// 持有已注册的实例，并监听实例是否仍在注册中心中
+func (l *registryLeaser) hold(ins *gregistry.ServiceInstance) error {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	watcher, err := l.registry.Watch(ctx, l.name)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	chWatch := make(chan struct{})
+
+	l.mu.Lock()
+	l.instance, l.cancel, l.chWatch = ins, cancel, chWatch
+	l.mu.Unlock()
+
+	l.alive.Store(true)
+
+	go l.watch(ctx, watcher, ins, chWatch)
+
+	return nil
+}
+
+// 监听服务实例列表；实例不在列表中时租约失效
+func (l *registryLeaser) watch(ctx context.Context, watcher gregistry.Watcher, ins *gregistry.ServiceInstance, chWatch chan struct{}) {
+	defer close(chWatch)
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			// exec watch
+		}
+
+		services, err := watcher.Next()
+		if err != nil {
+			continue
+		}
+
+		found := false
+		for _, service := range services {
+			if service.ID == ins.ID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			glog.Errorf("worker lease %s is lost: instance %s is removed from registry", ins.Alias, ins.ID)
+			l.alive.Store(false)
+			return
+		}
+	}
+}
+
+// 获取已被其他实例占用的工作节点ID
+func (l *registryLeaser) used(ctx context.Context, self string) (map[int64]bool, error) {
+	services, err := l.registry.Services(ctx, l.name)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[int64]bool, len(services))
+	for _, service := range services {
+		if service.ID == self {
+			continue
+		}
+
+		if workerID, err := strconv.ParseInt(service.Alias, 10, 64); err == nil {
+			used[workerID] = true
+		}
+	}
+
+	return used, nil
+}
@@ -0,0 +1,37 @@
+package gidgen
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gmodules"
+)
+
+var _ gmodules.Module = &Module{}
+
+// Module ID生成器组件；注入引擎后于初始化时设置为全局ID生成器，并于销毁时关闭以释放租用的工作节点ID
+type Module struct {
+	gmodules.Base
+	generator Generator
+}
+
+func NewModule(generator Generator) *Module {
+	return &Module{generator: generator}
+}
+
+// Name 组件名称
+func (m *Module) Name() string {
+	return "idgen"
+}
+
+// Init 初始化组件
+func (m *Module) Init() {
+	SetGenerator(m.generator)
+}
+
+// TryDestroy 销毁组件；关闭ID生成器
+func (m *Module) TryDestroy(ctx context.Context) error {
+	if GetGenerator() == m.generator {
+		SetGenerator(nil)
+	}
+
+	return m.generator.Close()
+}
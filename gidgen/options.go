@@ -0,0 +1,115 @@
+package gidgen
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/getc"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"time"
+)
+
+const (
+	defaultWorkerID     = -1          // 默认工作节点ID；小于0时从租约器租用
+	defaultWorkerBits   = 10          // 默认工作节点ID位数
+	defaultSequenceBits = 12          // 默认序列号位数
+	defaultMaxBackward  = time.Second // 默认可容忍的时钟回拨时长
+	defaultStep         = 1000        // 默认号段步长
+	defaultPrefetch     = 0.2         // 默认号段预取比例
+)
+
+const (
+	defaultWorkerIDKey     = "etc.idgen.workerID"
+	defaultEpochKey        = "etc.idgen.epoch"
+	defaultWorkerBitsKey   = "etc.idgen.workerBits"
+	defaultSequenceBitsKey = "etc.idgen.sequenceBits"
+	defaultMaxBackwardKey  = "etc.idgen.maxBackward"
+	defaultStepKey         = "etc.idgen.step"
+)
+
+// 默认起始时间
+var defaultEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type Option func(o *options)
+
+type options struct {
+	ctx          context.Context // 上下文
+	workerID     int64           // 雪花算法工作节点ID；小于0时从租约器租用
+	leaser       Leaser          // 雪花算法工作节点ID租约器
+	epoch        time.Time       // 雪花算法起始时间
+	workerBits   int             // 雪花算法工作节点ID位数
+	sequenceBits int             // 雪花算法序列号位数
+	maxBackward  time.Duration   // 雪花算法可容忍的时钟回拨时长；回拨时长不超过该值时等待时钟追上，否则返回错误
+	db           gkvdb.KvDB      // 号段模式存储，默认使用全局缓存
+	step         int64           // 号段模式步长
+	prefetch     float64         // 号段模式预取比例；当前号段剩余比例低于该值时异步预取下一号段
+}
+
+func defaultOptions() *options {
+	opts := &options{
+		ctx:          context.Background(),
+		workerID:     getc.Get(defaultWorkerIDKey, defaultWorkerID).Int64(),
+		epoch:        defaultEpoch,
+		workerBits:   getc.Get(defaultWorkerBitsKey, defaultWorkerBits).Int(),
+		sequenceBits: getc.Get(defaultSequenceBitsKey, defaultSequenceBits).Int(),
+		maxBackward:  getc.Get(defaultMaxBackwardKey, defaultMaxBackward).Duration(),
+		step:         getc.Get(defaultStepKey, defaultStep).Int64(),
+		prefetch:     defaultPrefetch,
+	}
+
+	if epoch := getc.Get(defaultEpochKey).String(); epoch != "" {
+		if t, err := time.Parse(time.DateOnly, epoch); err == nil {
+			opts.epoch = t
+		}
+	}
+
+	return opts
+}
+
+// WithContext 设置上下文
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithWorkerID 设置雪花算法工作节点ID
+func WithWorkerID(workerID int64) Option {
+	return func(o *options) { o.workerID = workerID }
+}
+
+// WithLeaser 设置雪花算法工作节点ID租约器
+func WithLeaser(leaser Leaser) Option {
+	return func(o *options) { o.workerID, o.leaser = defaultWorkerID, leaser }
+}
+
+// WithEpoch 设置雪花算法起始时间；生成ID后不可再修改
+func WithEpoch(epoch time.Time) Option {
+	return func(o *options) { o.epoch = epoch }
+}
+
+// WithWorkerBits 设置雪花算法工作节点ID位数
+func WithWorkerBits(bits int) Option {
+	return func(o *options) { o.workerBits = bits }
+}
+
+// WithSequenceBits 设置雪花算法序列号位数
+func WithSequenceBits(bits int) Option {
+	return func(o *options) { o.sequenceBits = bits }
+}
+
+// WithMaxBackward 设置雪花算法可容忍的时钟回拨时长
+func WithMaxBackward(maxBackward time.Duration) Option {
+	return func(o *options) { o.maxBackward = maxBackward }
+}
+
+// WithKvDB 设置号段模式存储
+func WithKvDB(db gkvdb.KvDB) Option {
+	return func(o *options) { o.db = db }
+}
+
+// WithStep 设置号段模式步长
+func WithStep(step int64) Option {
+	return func(o *options) { o.step = step }
+}
+
+// WithPrefetch 设置号段模式预取比例
+func WithPrefetch(prefetch float64) Option {
+	return func(o *options) { o.prefetch = prefetch }
+}
@@ -0,0 +1,129 @@
+package gidgen
+
+import (
+	"context"
+	"fmt"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"sync"
+)
+
+var _ Generator = &Segment{}
+
+// 号段；[next, end]
+type span struct {
+	next, end int64
+}
+
+// Segment 号段模式ID生成器
+// 通过 gkvdb.IncrInt 批量预分配ID，同一业务标识下的ID全局唯一且趋势递增
+type Segment struct {
+	opts      *options
+	key       string
+	threshold int64
+	mu        sync.Mutex
+	cur       span
+	buf       *span         // 预取的下一号段
+	loading   chan struct{} // 号段加载中；加载结束时关闭
+	err       error         // 最近一次加载错误
+}
+
+// NewSegment 新建号段模式ID生成器；name 为业务标识，如 player、item、order
+func NewSegment(name string, opts ...Option) (*Segment, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.db == nil {
+		o.db = gkvdb.GetCache()
+	}
+
+	if name == "" || o.db == nil || o.step <= 0 {
+		return nil, gerrors.NewError(fmt.Sprintf("invalid segment %q, step: %d", name, o.step), gerrors.ErrInvalidArgument)
+	}
+
+	s := &Segment{}
+	s.opts = o
+	s.key = "idgen:segment:" + name
+	s.threshold = int64(float64(o.step) * o.prefetch)
+	s.cur = span{next: 1, end: 0}
+
+	return s, nil
+}
+
+// Next 生成下一个ID
+func (s *Segment) Next(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+
+	for {
+		if s.cur.next <= s.cur.end {
+			id := s.cur.next
+			s.cur.next++
+
+			if s.buf == nil && s.loading == nil && s.cur.end-s.cur.next < s.threshold {
+				s.load()
+			}
+
+			s.mu.Unlock()
+
+			return id, nil
+		}
+
+		if s.buf != nil {
+			s.cur, s.buf = *s.buf, nil
+			continue
+		}
+
+		if s.loading == nil {
+			s.load()
+		}
+
+		loading := s.loading
+
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-loading:
+		}
+
+		s.mu.Lock()
+
+		if s.buf == nil && s.err != nil {
+			err := s.err
+			s.err = nil
+			s.mu.Unlock()
+			return 0, err
+		}
+	}
+}
+
+// Close 关闭生成器；未使用的号段将被丢弃
+func (s *Segment) Close() error {
+	return nil
+}
+
+// 异步加载下一号段；调用方须持有锁
+func (s *Segment) load() {
+	loading := make(chan struct{})
+	s.loading = loading
+
+	go func() {
+		defer close(loading)
+
+		end, err := s.opts.db.IncrInt(s.opts.ctx, s.key, s.opts.step)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if err != nil {
+			s.err = err
+		} else {
+			s.buf, s.err = &span{next: end - s.opts.step + 1, end: end}, nil
+		}
+
+		s.loading = nil
+	}()
+}
@@ -0,0 +1,152 @@
+package gidgen
+
+import (
+	"context"
+	"fmt"
+	"github.com/goodluck0107/gcore/gerrors"
+	"sync"
+	"time"
+)
+
+var _ Generator = &Snowflake{}
+
+// Snowflake 雪花算法ID生成器
+// ID由符号位、毫秒时间戳、工作节点ID与序列号组成，同一工作节点生成的ID单调递增
+type Snowflake struct {
+	opts      *options
+	mu        sync.Mutex
+	workerID  int64
+	sequence  int64
+	last      int64 // 上一次生成ID的时间戳
+	timeShift int
+	seqMask   int64
+	maxTime   int64
+}
+
+// NewSnowflake 新建雪花算法ID生成器
+// 未指定工作节点ID时从租约器租用，生成器关闭时释放
+func NewSnowflake(opts ...Option) (*Snowflake, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.workerBits <= 0 || o.sequenceBits <= 0 || o.workerBits+o.sequenceBits >= 63 {
+		return nil, gerrors.NewError(fmt.Sprintf("invalid bits, worker: %d, sequence: %d", o.workerBits, o.sequenceBits), gerrors.ErrInvalidArgument)
+	}
+
+	s := &Snowflake{}
+	s.opts = o
+	s.timeShift = o.workerBits + o.sequenceBits
+	s.seqMask = 1<<o.sequenceBits - 1
+	s.maxTime = 1<<(63-s.timeShift) - 1
+
+	maxWorkerID := int64(1<<o.workerBits - 1)
+
+	switch {
+	case o.workerID >= 0:
+		if o.workerID > maxWorkerID {
+			return nil, gerrors.NewError(fmt.Sprintf("worker id %d out of range [0, %d]", o.workerID, maxWorkerID), gerrors.ErrInvalidArgument)
+		}
+		s.workerID = o.workerID
+	case o.leaser != nil:
+		workerID, err := o.leaser.Lease(o.ctx, maxWorkerID)
+		if err != nil {
+			return nil, err
+		}
+		s.workerID = workerID
+	default:
+		return nil, gerrors.NewError("missing worker id or leaser", gerrors.ErrInvalidArgument)
+	}
+
+	return s, nil
+}
+
+// WorkerID 获取工作节点ID
+func (s *Snowflake) WorkerID() int64 {
+	return s.workerID
+}
+
+// Next 生成下一个ID
+// 时钟回拨不超过可容忍时长时等待时钟追上，否则返回 gerrors.ErrClockBackwards
+func (s *Snowflake) Next(ctx context.Context) (int64, error) {
+	if s.opts.leaser != nil && !s.opts.leaser.Alive() {
+		return 0, gerrors.ErrWorkerLeaseLost
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if now < s.last {
+		if backward := time.Duration(s.last-now) * time.Millisecond; backward > s.opts.maxBackward {
+			return 0, gerrors.NewError(fmt.Sprintf("clock moved backwards %s", backward), gerrors.ErrClockBackwards)
+		}
+
+		var err error
+		if now, err = s.wait(ctx, s.last); err != nil {
+			return 0, err
+		}
+	}
+
+	if now == s.last {
+		if s.sequence = (s.sequence + 1) & s.seqMask; s.sequence == 0 {
+			var err error
+			if now, err = s.wait(ctx, s.last+1); err != nil {
+				return 0, err
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+
+	if now > s.maxTime {
+		return 0, gerrors.NewError("snowflake timestamp overflow", gerrors.ErrIDExhausted)
+	}
+
+	s.last = now
+
+	return now<<s.timeShift | s.workerID<<s.opts.sequenceBits | s.sequence, nil
+}
+
+// Decompose 分解ID为生成时间、工作节点ID与序列号
+func (s *Snowflake) Decompose(id int64) (t time.Time, workerID int64, sequence int64) {
+	t = s.opts.epoch.Add(time.Duration(id>>s.timeShift) * time.Millisecond)
+	workerID = id >> s.opts.sequenceBits & (1<<s.opts.workerBits - 1)
+	sequence = id & s.seqMask
+	return
+}
+
+// Close 关闭生成器；释放租用的工作节点ID
+func (s *Snowflake) Close() error {
+	if s.opts.leaser == nil {
+		return nil
+	}
+
+	return s.opts.leaser.Release(s.opts.ctx)
+}
+
+// 获取当前时间戳
+func (s *Snowflake) now() int64 {
+	return time.Since(s.opts.epoch).Milliseconds()
+}
+
+// 等待时钟到达指定时间戳
+func (s *Snowflake) wait(ctx context.Context, target int64) (int64, error) {
+	for {
+		now := s.now()
+		if now >= target {
+			return now, nil
+		}
+
+		timer := time.NewTimer(time.Duration(target-now) * time.Millisecond)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
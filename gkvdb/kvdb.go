@@ -16,8 +16,18 @@ type KvDB interface {
 	Get(ctx context.Context, key string, def ...interface{}) Result
 	// Set 设置缓存值
 	Set(ctx context.Context, key string, value interface{}, expiration ...time.Duration) error
+	// SetNX 缓存不存在时设置缓存值；设置成功时返回true
+	SetNX(ctx context.Context, key string, value interface{}, expiration ...time.Duration) (bool, error)
+	// CompareAndSwap 缓存值等于old时设置为new；设置成功时返回true
+	CompareAndSwap(ctx context.Context, key string, old, new interface{}, expiration ...time.Duration) (bool, error)
+	// CompareAndDelete 缓存值等于old时删除缓存；删除成功时返回true
+	CompareAndDelete(ctx context.Context, key string, old interface{}) (bool, error)
 	// GetSet 获取设置缓存值
 	GetSet(ctx context.Context, key string, fn SetValueFunc) Result
+	// MGet 批量获取缓存值；返回结果与keys一一对应，缓存不存在时对应结果为gerrors.ErrNil
+	MGet(ctx context.Context, keys ...string) ([]Result, error)
+	// MSet 批量设置缓存值
+	MSet(ctx context.Context, values map[string]interface{}, expiration ...time.Duration) error
 	// Delete 删除缓存
 	Delete(ctx context.Context, keys ...string) (bool, error)
 	// IncrInt 整数自增
@@ -59,11 +69,36 @@ func Set(ctx context.Context, key string, value interface{}, expiration ...time.
 	return globalCache.Set(ctx, key, value, expiration...)
 }
 
+// SetNX 缓存不存在时设置缓存值
+func SetNX(ctx context.Context, key string, value interface{}, expiration ...time.Duration) (bool, error) {
+	return globalCache.SetNX(ctx, key, value, expiration...)
+}
+
 // GetSet 获取设置缓存值
 func GetSet(ctx context.Context, key string, fn SetValueFunc) Result {
 	return globalCache.GetSet(ctx, key, fn)
 }
 
+// MGet 批量获取缓存值
+func MGet(ctx context.Context, keys ...string) ([]Result, error) {
+	return globalCache.MGet(ctx, keys...)
+}
+
+// CompareAndSwap 缓存值等于old时设置为new
+func CompareAndSwap(ctx context.Context, key string, old, new interface{}, expiration ...time.Duration) (bool, error) {
+	return globalCache.CompareAndSwap(ctx, key, old, new, expiration...)
+}
+
+// CompareAndDelete 缓存值等于old时删除缓存
+func CompareAndDelete(ctx context.Context, key string, old interface{}) (bool, error) {
+	return globalCache.CompareAndDelete(ctx, key, old)
+}
+
+// MSet 批量设置缓存值
+func MSet(ctx context.Context, values map[string]interface{}, expiration ...time.Duration) error {
+	return globalCache.MSet(ctx, values, expiration...)
+}
+
 // Delete 删除缓存
 func Delete(ctx context.Context, keys ...string) (bool, error) {
 	return globalCache.Delete(ctx, keys...)
@@ -105,6 +105,85 @@ func (c *KvDB) Set(ctx context.Context, key string, value interface{}, expiratio
 	}
 }
 
+// SetNX 缓存不存在时设置缓存值
+func (c *KvDB) SetNX(ctx context.Context, key string, value interface{}, expiration ...time.Duration) (bool, error) {
+	item := &memcache.Item{
+		Key:   c.AddPrefix(key),
+		Value: gconv.Bytes(value),
+	}
+
+	if len(expiration) > 0 && expiration[0] > 0 {
+		item.Expiration = int32(expiration[0] / time.Second)
+	}
+
+	if err := c.opts.client.Add(item); err != nil {
+		if gerrors.Is(err, memcache.ErrNotStored) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CompareAndSwap 缓存值等于old时设置为new
+func (c *KvDB) CompareAndSwap(ctx context.Context, key string, old, new interface{}, expiration ...time.Duration) (bool, error) {
+	item, err := c.compare(c.AddPrefix(key), old)
+	if err != nil || item == nil {
+		return false, err
+	}
+
+	item.Value = gconv.Bytes(new)
+
+	if len(expiration) > 0 && expiration[0] > 0 {
+		item.Expiration = int32(expiration[0] / time.Second)
+	}
+
+	return c.swap(item)
+}
+
+// CompareAndDelete 缓存值等于old时删除缓存
+func (c *KvDB) CompareAndDelete(ctx context.Context, key string, old interface{}) (bool, error) {
+	item, err := c.compare(c.AddPrefix(key), old)
+	if err != nil || item == nil {
+		return false, err
+	}
+
+	// 以已过期的时间写回，使缓存在CAS校验通过时立即失效
+	item.Expiration = -1
+
+	return c.swap(item)
+}
+
+// 获取缓存项并比较缓存值；缓存不存在或不相等时返回nil
+func (c *KvDB) compare(key string, old interface{}) (*memcache.Item, error) {
+	item, err := c.opts.client.Get(key)
+	if err != nil {
+		if gerrors.Is(err, memcache.ErrCacheMiss) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if string(item.Value) != gconv.String(old) {
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+// 按获取时的CAS标识写回缓存项；期间缓存被修改或删除时返回false
+func (c *KvDB) swap(item *memcache.Item) (bool, error) {
+	if err := c.opts.client.CompareAndSwap(item); err != nil {
+		if gerrors.Is(err, memcache.ErrCASConflict) || gerrors.Is(err, memcache.ErrNotStored) || gerrors.Is(err, memcache.ErrCacheMiss) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
 // GetSet 获取设置缓存值
 func (c *KvDB) GetSet(ctx context.Context, key string, fn gkvdb.SetValueFunc) gkvdb.Result {
 	key = c.AddPrefix(key)
@@ -160,6 +239,46 @@ func (c *KvDB) GetSet(ctx context.Context, key string, fn gkvdb.SetValueFunc) gk
 	return rst.(gkvdb.Result)
 }
 
+// MGet 批量获取缓存值
+func (c *KvDB) MGet(ctx context.Context, keys ...string) ([]gkvdb.Result, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	prefixedKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixedKeys = append(prefixedKeys, c.AddPrefix(key))
+	}
+
+	items, err := c.opts.client.GetMulti(prefixedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]gkvdb.Result, 0, len(keys))
+	for _, key := range prefixedKeys {
+		item, ok := items[key]
+		if !ok || gconv.String(item.Value) == c.opts.nilValue {
+			results = append(results, gkvdb.NewResult(nil, gerrors.ErrNil))
+		} else {
+			results = append(results, gkvdb.NewResult(gconv.String(item.Value)))
+		}
+	}
+
+	return results, nil
+}
+
+// MSet 批量设置缓存值
+func (c *KvDB) MSet(ctx context.Context, values map[string]interface{}, expiration ...time.Duration) error {
+	for key, value := range values {
+		if err := c.Set(ctx, key, value, expiration...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Delete 删除缓存
 func (c *KvDB) Delete(ctx context.Context, key string) (bool, error) {
 	err := c.opts.client.Delete(c.AddPrefix(key))
@@ -0,0 +1,377 @@
+package multilevel
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/geventbus"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"github.com/goodluck0107/gcore/glog"
+	"github.com/goodluck0107/gcore/gutils/gconv"
+	"github.com/goodluck0107/gcore/gutils/greflect"
+	"github.com/goodluck0107/gcore/gutils/guuid"
+	"time"
+)
+
+// 缓存失效事件
+type invalidation struct {
+	Source string   `json:"source"`         // 事件来源
+	Keys   []string `json:"keys,omitempty"` // 失效的键
+	Tags   []string `json:"tags,omitempty"` // 失效的标签
+}
+
+// KvDB 多级缓存
+// 在远程缓存前增加一层进程内缓存，写入与删除时通过事件总线广播失效事件，各节点随之移除本地缓存
+type KvDB struct {
+	id    string
+	opts  *options
+	local *local
+	topic *geventbus.Topic[*invalidation]
+}
+
+func NewKvDB(opts ...Option) *KvDB {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.remote == nil {
+		glog.Fatal("multilevel cache requires a remote cache")
+	}
+
+	c := &KvDB{}
+	c.id = guuid.UUID()
+	c.opts = o
+	c.local = newLocal(o.policy, o.capacity, o.expiration)
+	c.topic = geventbus.NewTopic[*invalidation](o.topic, geventbus.WithEventbus(o.eventbus))
+
+	if err := c.topic.Subscribe(context.Background(), c.handleInvalidation); err != nil {
+		glog.Warnf("cache invalidation subscribe failed, topic: %s err: %v", o.topic, err)
+	}
+
+	return c
+}
+
+// Has 检测缓存是否存在
+func (c *KvDB) Has(ctx context.Context, key string) (bool, error) {
+	if _, ok := c.local.get(key); ok {
+		return true, nil
+	}
+
+	if _, err := c.load(ctx, key); err != nil {
+		if gerrors.Is(err, gerrors.ErrNil) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Get 获取缓存值
+func (c *KvDB) Get(ctx context.Context, key string, def ...interface{}) gkvdb.Result {
+	if val, ok := c.local.get(key); ok {
+		return gkvdb.NewResult(val)
+	}
+
+	val, err := c.load(ctx, key)
+	if err != nil {
+		if gerrors.Is(err, gerrors.ErrNil) && len(def) > 0 {
+			return gkvdb.NewResult(def[0])
+		}
+		return gkvdb.NewResult(nil, err)
+	}
+
+	return gkvdb.NewResult(val)
+}
+
+// Set 设置缓存值
+func (c *KvDB) Set(ctx context.Context, key string, value interface{}, expiration ...time.Duration) error {
+	if err := c.opts.remote.Set(ctx, key, value, expiration...); err != nil {
+		return err
+	}
+
+	c.invalidate(ctx, []string{key}, nil)
+
+	return nil
+}
+
+// SetNX 缓存不存在时设置缓存值
+func (c *KvDB) SetNX(ctx context.Context, key string, value interface{}, expiration ...time.Duration) (bool, error) {
+	ok, err := c.opts.remote.SetNX(ctx, key, value, expiration...)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	c.invalidate(ctx, []string{key}, nil)
+
+	return true, nil
+}
+
+// CompareAndSwap 缓存值等于old时设置为new
+func (c *KvDB) CompareAndSwap(ctx context.Context, key string, old, new interface{}, expiration ...time.Duration) (bool, error) {
+	ok, err := c.opts.remote.CompareAndSwap(ctx, key, old, new, expiration...)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	c.invalidate(ctx, []string{key}, nil)
+
+	return true, nil
+}
+
+// CompareAndDelete 缓存值等于old时删除缓存
+func (c *KvDB) CompareAndDelete(ctx context.Context, key string, old interface{}) (bool, error) {
+	ok, err := c.opts.remote.CompareAndDelete(ctx, key, old)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	c.invalidate(ctx, []string{key}, nil)
+
+	return true, nil
+}
+
+// GetSet 获取设置缓存值
+func (c *KvDB) GetSet(ctx context.Context, key string, fn gkvdb.SetValueFunc) gkvdb.Result {
+	return c.getSet(ctx, key, nil, fn)
+}
+
+// MGet 批量获取缓存值
+// 优先读取本地缓存，未命中的键通过一次批量请求从远程缓存获取
+func (c *KvDB) MGet(ctx context.Context, keys ...string) ([]gkvdb.Result, error) {
+	results := make([]gkvdb.Result, len(keys))
+	misses := make([]int, 0, len(keys))
+	missKeys := make([]string, 0, len(keys))
+
+	for i, key := range keys {
+		if val, ok := c.local.get(key); ok {
+			results[i] = gkvdb.NewResult(val)
+		} else {
+			misses = append(misses, i)
+			missKeys = append(missKeys, key)
+		}
+	}
+
+	if len(missKeys) == 0 {
+		return results, nil
+	}
+
+	rsts, err := c.opts.remote.MGet(ctx, missKeys...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rst := range rsts {
+		key := missKeys[i]
+
+		val, err := rst.String()
+		if err == nil {
+			val, err = c.cache(ctx, key, val)
+		}
+
+		if err != nil {
+			results[misses[i]] = gkvdb.NewResult(nil, err)
+		} else {
+			results[misses[i]] = gkvdb.NewResult(val)
+		}
+	}
+
+	return results, nil
+}
+
+// MSet 批量设置缓存值
+func (c *KvDB) MSet(ctx context.Context, values map[string]interface{}, expiration ...time.Duration) error {
+	if err := c.opts.remote.MSet(ctx, values, expiration...); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	c.invalidate(ctx, keys, nil)
+
+	return nil
+}
+
+// Delete 删除缓存
+func (c *KvDB) Delete(ctx context.Context, keys ...string) (bool, error) {
+	ok, err := c.opts.remote.Delete(ctx, keys...)
+	if err != nil {
+		return false, err
+	}
+
+	c.invalidate(ctx, keys, nil)
+
+	return ok, nil
+}
+
+// IncrInt 整数自增
+func (c *KvDB) IncrInt(ctx context.Context, key string, value int64) (int64, error) {
+	val, err := c.opts.remote.IncrInt(ctx, key, value)
+	if err != nil {
+		return 0, err
+	}
+
+	c.invalidate(ctx, []string{key}, nil)
+
+	return val, nil
+}
+
+// IncrFloat 浮点数自增
+func (c *KvDB) IncrFloat(ctx context.Context, key string, value float64) (float64, error) {
+	val, err := c.opts.remote.IncrFloat(ctx, key, value)
+	if err != nil {
+		return 0, err
+	}
+
+	c.invalidate(ctx, []string{key}, nil)
+
+	return val, nil
+}
+
+// DecrInt 整数自减
+func (c *KvDB) DecrInt(ctx context.Context, key string, value int64) (int64, error) {
+	return c.IncrInt(ctx, key, -value)
+}
+
+// DecrFloat 浮点数自减
+func (c *KvDB) DecrFloat(ctx context.Context, key string, value float64) (float64, error) {
+	return c.IncrFloat(ctx, key, -value)
+}
+
+// SetTagged 设置带标签的缓存值
+// 通过InvalidateTags使标签失效后，该缓存值在所有节点的本地缓存与远程缓存中均视为不存在
+func (c *KvDB) SetTagged(ctx context.Context, key string, value interface{}, tags []string, expiration ...time.Duration) error {
+	val, err := c.tag(ctx, tags, value)
+	if err != nil {
+		return err
+	}
+
+	return c.Set(ctx, key, val, expiration...)
+}
+
+// GetSetTagged 获取设置带标签的缓存值
+func (c *KvDB) GetSetTagged(ctx context.Context, key string, tags []string, fn gkvdb.SetValueFunc) gkvdb.Result {
+	return c.getSet(ctx, key, tags, fn)
+}
+
+// InvalidateTags 使标签下的全部缓存失效
+func (c *KvDB) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		if _, err := c.opts.remote.IncrInt(ctx, tagKey(tag), 1); err != nil {
+			return err
+		}
+	}
+
+	c.invalidate(ctx, nil, tags)
+
+	return nil
+}
+
+// AddPrefix 添加Key前缀
+func (c *KvDB) AddPrefix(key string) string {
+	return c.opts.remote.AddPrefix(key)
+}
+
+// Client 获取远程缓存的客户端
+func (c *KvDB) Client() interface{} {
+	return c.opts.remote.Client()
+}
+
+// Close 停止接收缓存失效事件
+func (c *KvDB) Close() error {
+	return c.topic.Unsubscribe(context.Background())
+}
+
+// 获取设置缓存值；远程缓存中的值因标签失效而过期时，删除后重新设置
+func (c *KvDB) getSet(ctx context.Context, key string, tags []string, fn gkvdb.SetValueFunc) gkvdb.Result {
+	if val, ok := c.local.get(key); ok {
+		return gkvdb.NewResult(val)
+	}
+
+	set := fn
+	if len(tags) > 0 {
+		set = func() (interface{}, error) {
+			val, err := fn()
+			if err != nil || val == nil || greflect.IsNil(val) {
+				return val, err
+			}
+
+			return c.tag(ctx, tags, val)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		val, err := c.opts.remote.GetSet(ctx, key, set).String()
+		if err != nil {
+			return gkvdb.NewResult(nil, err)
+		}
+
+		if val, err = c.cache(ctx, key, val); err == nil {
+			return gkvdb.NewResult(val)
+		}
+
+		if !gerrors.Is(err, gerrors.ErrNil) {
+			return gkvdb.NewResult(nil, err)
+		}
+	}
+
+	// 标签持续失效时不再缓存，直接返回数据源的值
+	val, err := fn()
+	if err != nil {
+		return gkvdb.NewResult(nil, err)
+	}
+
+	if val == nil || greflect.IsNil(val) {
+		return gkvdb.NewResult(nil, gerrors.ErrNil)
+	}
+
+	return gkvdb.NewResult(gconv.String(val))
+}
+
+// 从远程缓存加载缓存值
+func (c *KvDB) load(ctx context.Context, key string) (string, error) {
+	val, err := c.opts.remote.Get(ctx, key).String()
+	if err != nil {
+		return "", err
+	}
+
+	return c.cache(ctx, key, val)
+}
+
+// 校验远程缓存值的标签并写入本地缓存
+func (c *KvDB) cache(ctx context.Context, key, val string) (string, error) {
+	val, tags, err := c.untag(ctx, key, val)
+	if err != nil {
+		return "", err
+	}
+
+	c.local.set(key, val, tags...)
+
+	return val, nil
+}
+
+// 移除本地缓存并广播失效事件
+func (c *KvDB) invalidate(ctx context.Context, keys, tags []string) {
+	c.local.remove(keys...)
+	c.local.removeTags(tags...)
+
+	err := c.topic.Publish(ctx, &invalidation{Source: c.id, Keys: keys, Tags: tags})
+	if err != nil {
+		glog.Warnf("cache invalidation publish failed, topic: %s err: %v", c.opts.topic, err)
+	}
+}
+
+// 处理其他节点的失效事件
+func (c *KvDB) handleInvalidation(_ context.Context, msg *invalidation) error {
+	if msg.Source == c.id {
+		return nil
+	}
+
+	c.local.remove(msg.Keys...)
+	c.local.removeTags(msg.Tags...)
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package multilevel_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/geventbus"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"github.com/goodluck0107/gcore/gkvdb/multilevel"
+	"github.com/goodluck0107/gcore/gutils/gconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 基于map的远程缓存
+type remote struct {
+	gkvdb.KvDB
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newRemote() *remote {
+	return &remote{data: make(map[string]string)}
+}
+
+func (r *remote) Get(_ context.Context, key string, def ...interface{}) gkvdb.Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if val, ok := r.data[key]; ok {
+		return gkvdb.NewResult(val)
+	}
+
+	return gkvdb.NewResult(nil, gerrors.ErrNil)
+}
+
+func (r *remote) Set(_ context.Context, key string, value interface{}, _ ...time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data[key] = gconv.String(value)
+
+	return nil
+}
+
+func (r *remote) GetSet(ctx context.Context, key string, fn gkvdb.SetValueFunc) gkvdb.Result {
+	if rst := r.Get(ctx, key); rst.Err() == nil {
+		return rst
+	}
+
+	val, err := fn()
+	if err != nil {
+		return gkvdb.NewResult(nil, err)
+	}
+
+	_ = r.Set(ctx, key, val)
+
+	return gkvdb.NewResult(val)
+}
+
+func (r *remote) MGet(ctx context.Context, keys ...string) ([]gkvdb.Result, error) {
+	results := make([]gkvdb.Result, 0, len(keys))
+	for _, key := range keys {
+		results = append(results, r.Get(ctx, key))
+	}
+
+	return results, nil
+}
+
+func (r *remote) Delete(_ context.Context, keys ...string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		delete(r.data, key)
+	}
+
+	return true, nil
+}
+
+func (r *remote) IncrInt(_ context.Context, key string, value int64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	val := gconv.Int64(r.data[key]) + value
+	r.data[key] = gconv.String(val)
+
+	return val, nil
+}
+
+func TestKvDB_Invalidate(t *testing.T) {
+	var (
+		ctx = context.Background()
+		rdb = newRemote()
+		eb  = geventbus.NewEventbus()
+		db1 = multilevel.NewKvDB(multilevel.WithRemote(rdb), multilevel.WithEventbus(eb))
+		db2 = multilevel.NewKvDB(multilevel.WithRemote(rdb), multilevel.WithEventbus(eb))
+	)
+	defer eb.Close()
+
+	if err := db1.Set(ctx, "name", "fuxiao"); err != nil {
+		t.Fatal(err)
+	}
+
+	if val, _ := db2.Get(ctx, "name").String(); val != "fuxiao" {
+		t.Fatalf("got %s, want fuxiao", val)
+	}
+
+	// 远程缓存被直接修改时，本地缓存仍然命中
+	_ = rdb.Set(ctx, "name", "yuebanfuxiao")
+
+	if val, _ := db2.Get(ctx, "name").String(); val != "fuxiao" {
+		t.Fatalf("got %s, want local fuxiao", val)
+	}
+
+	// 其他节点写入后，广播的失效事件移除本地缓存
+	if err := db1.Set(ctx, "name", "gcore"); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if val, _ := db2.Get(ctx, "name").String(); val == "gcore" {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("local cache not invalidated")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestKvDB_Tags(t *testing.T) {
+	var (
+		ctx = context.Background()
+		rdb = newRemote()
+		db  = multilevel.NewKvDB(multilevel.WithRemote(rdb), multilevel.WithEventbus(geventbus.NewEventbus()))
+	)
+
+	rst := db.GetSetTagged(ctx, "user:1", []string{"users"}, func() (interface{}, error) {
+		return "fuxiao", nil
+	})
+	if val, err := rst.String(); err != nil || val != "fuxiao" {
+		t.Fatalf("got %s %v, want fuxiao", val, err)
+	}
+
+	results, err := db.MGet(ctx, "user:1", "user:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val, _ := results[0].String(); val != "fuxiao" {
+		t.Fatalf("got %s, want fuxiao", val)
+	}
+
+	if !gerrors.Is(results[1].Err(), gerrors.ErrNil) {
+		t.Fatalf("got %v, want nil error", results[1].Err())
+	}
+
+	if err = db.InvalidateTags(ctx, "users"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, _ := db.Has(ctx, "user:1"); ok {
+		t.Fatal("tagged cache not invalidated")
+	}
+}
+
+func TestKvDB_Evict(t *testing.T) {
+	for _, policy := range []string{multilevel.PolicyLRU, multilevel.PolicyLFU} {
+		var (
+			ctx = context.Background()
+			rdb = newRemote()
+			db  = multilevel.NewKvDB(
+				multilevel.WithRemote(rdb),
+				multilevel.WithEventbus(geventbus.NewEventbus()),
+				multilevel.WithPolicy(policy),
+				multilevel.WithCapacity(2),
+			)
+		)
+
+		_ = rdb.Set(ctx, "k1", "v1")
+		_ = rdb.Set(ctx, "k2", "v2")
+		_ = rdb.Set(ctx, "k3", "v3")
+
+		db.Get(ctx, "k1")
+		db.Get(ctx, "k2")
+		db.Get(ctx, "k1")
+		db.Get(ctx, "k3")
+
+		_ = rdb.Set(ctx, "k1", "new")
+		_ = rdb.Set(ctx, "k2", "new")
+
+		if val, _ := db.Get(ctx, "k1").String(); val != "v1" {
+			t.Fatalf("%s: got %s, want cached v1", policy, val)
+		}
+
+		if val, _ := db.Get(ctx, "k2").String(); val != "new" {
+			t.Fatalf("%s: got %s, want evicted k2", policy, val)
+		}
+	}
+}
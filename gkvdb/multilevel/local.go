@@ -0,0 +1,230 @@
+package multilevel
+
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key      string
+	value    string
+	tags     []string
+	expireAt time.Time
+
+	elem  *list.Element // lru链表节点
+	freq  int           // lfu访问频率
+	tick  uint64        // lfu最近访问序号
+	index int           // lfu堆索引
+}
+
+// 淘汰策略
+type policy interface {
+	// 添加条目
+	push(e *entry)
+	// 访问条目
+	touch(e *entry)
+	// 移除条目
+	remove(e *entry)
+	// 获取待淘汰的条目
+	victim() *entry
+}
+
+// 本地缓存
+type local struct {
+	mu         sync.Mutex
+	capacity   int
+	expiration time.Duration
+	policy     policy
+	entries    map[string]*entry
+	tags       map[string]map[string]struct{}
+}
+
+func newLocal(name string, capacity int, expiration time.Duration) *local {
+	l := &local{}
+	l.capacity = capacity
+	l.expiration = expiration
+	l.entries = make(map[string]*entry)
+	l.tags = make(map[string]map[string]struct{})
+
+	if name == PolicyLFU {
+		l.policy = &lfu{}
+	} else {
+		l.policy = &lru{list: list.New()}
+	}
+
+	return l
+}
+
+// 获取缓存值
+func (l *local) get(key string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	if time.Now().After(e.expireAt) {
+		l.delete(e)
+		return "", false
+	}
+
+	l.policy.touch(e)
+
+	return e.value, true
+}
+
+// 设置缓存值
+func (l *local) set(key, value string, tags ...string) {
+	if l.capacity <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.entries[key]; ok {
+		l.delete(e)
+	}
+
+	for len(l.entries) >= l.capacity {
+		l.delete(l.policy.victim())
+	}
+
+	e := &entry{key: key, value: value, tags: tags, expireAt: time.Now().Add(l.expiration)}
+	l.entries[key] = e
+	l.policy.push(e)
+
+	for _, tag := range tags {
+		keys, ok := l.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			l.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// 移除缓存
+func (l *local) remove(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if e, ok := l.entries[key]; ok {
+			l.delete(e)
+		}
+	}
+}
+
+// 移除标签下的全部缓存
+func (l *local) removeTags(tags ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range l.tags[tag] {
+			if e, ok := l.entries[key]; ok {
+				l.delete(e)
+			}
+		}
+		delete(l.tags, tag)
+	}
+}
+
+func (l *local) delete(e *entry) {
+	delete(l.entries, e.key)
+	l.policy.remove(e)
+
+	for _, tag := range e.tags {
+		if keys, ok := l.tags[tag]; ok {
+			delete(keys, e.key)
+
+			if len(keys) == 0 {
+				delete(l.tags, tag)
+			}
+		}
+	}
+}
+
+type lru struct {
+	list *list.List
+}
+
+func (p *lru) push(e *entry) {
+	e.elem = p.list.PushFront(e)
+}
+
+func (p *lru) touch(e *entry) {
+	p.list.MoveToFront(e.elem)
+}
+
+func (p *lru) remove(e *entry) {
+	p.list.Remove(e.elem)
+}
+
+func (p *lru) victim() *entry {
+	return p.list.Back().Value.(*entry)
+}
+
+// 以访问频率为序的小顶堆，频率相同时优先淘汰较久未访问的条目
+type lfu struct {
+	entries []*entry
+	tick    uint64
+}
+
+func (p *lfu) push(e *entry) {
+	p.tick++
+	e.freq, e.tick = 1, p.tick
+	heap.Push(p, e)
+}
+
+func (p *lfu) touch(e *entry) {
+	p.tick++
+	e.freq, e.tick = e.freq+1, p.tick
+	heap.Fix(p, e.index)
+}
+
+func (p *lfu) remove(e *entry) {
+	heap.Remove(p, e.index)
+}
+
+func (p *lfu) victim() *entry {
+	return p.entries[0]
+}
+
+func (p *lfu) Len() int {
+	return len(p.entries)
+}
+
+func (p *lfu) Less(i, j int) bool {
+	if p.entries[i].freq != p.entries[j].freq {
+		return p.entries[i].freq < p.entries[j].freq
+	}
+
+	return p.entries[i].tick < p.entries[j].tick
+}
+
+func (p *lfu) Swap(i, j int) {
+	p.entries[i], p.entries[j] = p.entries[j], p.entries[i]
+	p.entries[i].index = i
+	p.entries[j].index = j
+}
+
+func (p *lfu) Push(x any) {
+	e := x.(*entry)
+	e.index = len(p.entries)
+	p.entries = append(p.entries, e)
+}
+
+func (p *lfu) Pop() any {
+	n := len(p.entries)
+	e := p.entries[n-1]
+	p.entries[n-1] = nil
+	p.entries = p.entries[:n-1]
+
+	return e
+}
@@ -0,0 +1,91 @@
+package multilevel
+
+import (
+	"github.com/goodluck0107/gcore/getc"
+	"github.com/goodluck0107/gcore/geventbus"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"time"
+)
+
+const (
+	PolicyLRU = "lru" // 淘汰最近最少使用的本地缓存
+	PolicyLFU = "lfu" // 淘汰使用频率最低的本地缓存
+)
+
+const (
+	defaultPolicy     = PolicyLRU
+	defaultCapacity   = 10000
+	defaultExpiration = "1m"
+	defaultTopic      = "cache:invalidate"
+)
+
+const (
+	defaultPolicyKey     = "etc.cache.multilevel.policy"
+	defaultCapacityKey   = "etc.cache.multilevel.capacity"
+	defaultExpirationKey = "etc.cache.multilevel.expiration"
+	defaultTopicKey      = "etc.cache.multilevel.topic"
+)
+
+type Option func(o *options)
+
+type options struct {
+	// 远程缓存，默认为全局缓存
+	remote gkvdb.KvDB
+
+	// 事件总线
+	// 用于广播缓存失效事件，默认为全局事件总线
+	eventbus geventbus.Eventbus
+
+	// 本地缓存淘汰策略，默认为lru
+	policy string
+
+	// 本地缓存最大条目数，默认为10000
+	capacity int
+
+	// 本地缓存过期时间，默认为1m
+	expiration time.Duration
+
+	// 缓存失效事件主题，默认为cache:invalidate
+	topic string
+}
+
+func defaultOptions() *options {
+	return &options{
+		remote:     gkvdb.GetCache(),
+		eventbus:   geventbus.GetEventbus(),
+		policy:     getc.Get(defaultPolicyKey, defaultPolicy).String(),
+		capacity:   getc.Get(defaultCapacityKey, defaultCapacity).Int(),
+		expiration: getc.Get(defaultExpirationKey, defaultExpiration).Duration(),
+		topic:      getc.Get(defaultTopicKey, defaultTopic).String(),
+	}
+}
+
+// WithRemote 设置远程缓存
+func WithRemote(remote gkvdb.KvDB) Option {
+	return func(o *options) { o.remote = remote }
+}
+
+// WithEventbus 设置事件总线
+func WithEventbus(eventbus geventbus.Eventbus) Option {
+	return func(o *options) { o.eventbus = eventbus }
+}
+
+// WithPolicy 设置本地缓存淘汰策略
+func WithPolicy(policy string) Option {
+	return func(o *options) { o.policy = policy }
+}
+
+// WithCapacity 设置本地缓存最大条目数
+func WithCapacity(capacity int) Option {
+	return func(o *options) { o.capacity = capacity }
+}
+
+// WithExpiration 设置本地缓存过期时间
+func WithExpiration(expiration time.Duration) Option {
+	return func(o *options) { o.expiration = expiration }
+}
+
+// WithTopic 设置缓存失效事件主题
+func WithTopic(topic string) Option {
+	return func(o *options) { o.topic = topic }
+}
@@ -0,0 +1,104 @@
+package multilevel
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gencoding/json"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/glog"
+	"github.com/goodluck0107/gcore/gutils/gconv"
+	"strings"
+)
+
+const (
+	taggedPrefix = "tagged@" // 带标签缓存值的前缀
+	tagKeyPrefix = "tag:"    // 标签版本号的键前缀
+)
+
+// 带标签的缓存值
+// 记录写入时各标签的版本号，读取时版本号与当前不一致即视为失效
+type tagged struct {
+	Value string           `json:"v"`
+	Tags  map[string]int64 `json:"t"`
+}
+
+// 获取标签版本号的键
+func tagKey(tag string) string {
+	return tagKeyPrefix + tag
+}
+
+// 为缓存值附加标签的当前版本号
+func (c *KvDB) tag(ctx context.Context, tags []string, value interface{}) (string, error) {
+	versions, err := c.versions(ctx, tags)
+	if err != nil {
+		return "", err
+	}
+
+	buf, err := json.Marshal(&tagged{Value: gconv.String(value), Tags: versions})
+	if err != nil {
+		return "", err
+	}
+
+	return taggedPrefix + string(buf), nil
+}
+
+// 解析带标签的缓存值；标签已失效时删除远程缓存并返回gerrors.ErrNil
+func (c *KvDB) untag(ctx context.Context, key, val string) (string, []string, error) {
+	if !strings.HasPrefix(val, taggedPrefix) {
+		return val, nil, nil
+	}
+
+	t := &tagged{}
+	if err := json.Unmarshal([]byte(val[len(taggedPrefix):]), t); err != nil {
+		return "", nil, err
+	}
+
+	tags := make([]string, 0, len(t.Tags))
+	for tag := range t.Tags {
+		tags = append(tags, tag)
+	}
+
+	versions, err := c.versions(ctx, tags)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for tag, version := range t.Tags {
+		if versions[tag] != version {
+			if _, err = c.opts.remote.Delete(ctx, key); err != nil {
+				glog.Warnf("stale cache delete failed, key: %s err: %v", key, err)
+			}
+			return "", nil, gerrors.ErrNil
+		}
+	}
+
+	return t.Value, tags, nil
+}
+
+// 批量获取标签的当前版本号，未失效过的标签版本号为0
+func (c *KvDB) versions(ctx context.Context, tags []string) (map[string]int64, error) {
+	versions := make(map[string]int64, len(tags))
+	if len(tags) == 0 {
+		return versions, nil
+	}
+
+	keys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		keys = append(keys, tagKey(tag))
+	}
+
+	rsts, err := c.opts.remote.MGet(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rst := range rsts {
+		version, err := rst.Int64()
+		if err != nil && !gerrors.Is(err, gerrors.ErrNil) {
+			return nil, err
+		}
+
+		versions[tags[i]] = version
+	}
+
+	return versions, nil
+}
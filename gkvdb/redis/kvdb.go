@@ -91,6 +91,30 @@ func (c *KvDB) Set(ctx context.Context, key string, value interface{}, expiratio
 	}
 }
 
+// SetNX 缓存不存在时设置缓存值
+func (c *KvDB) SetNX(ctx context.Context, key string, value interface{}, expiration ...time.Duration) (bool, error) {
+	if len(expiration) > 0 {
+		return c.opts.client.SetNX(ctx, c.AddPrefix(key), gconv.String(value), expiration[0]).Result()
+	} else {
+		return c.opts.client.SetNX(ctx, c.AddPrefix(key), gconv.String(value), 0).Result()
+	}
+}
+
+// CompareAndSwap 缓存值等于old时设置为new
+func (c *KvDB) CompareAndSwap(ctx context.Context, key string, old, new interface{}, expiration ...time.Duration) (bool, error) {
+	ttl := int64(-1)
+	if len(expiration) > 0 {
+		ttl = expiration[0].Milliseconds()
+	}
+
+	return compareAndSwapScript.Run(ctx, c.opts.client, []string{c.AddPrefix(key)}, gconv.String(old), gconv.String(new), ttl).Bool()
+}
+
+// CompareAndDelete 缓存值等于old时删除缓存
+func (c *KvDB) CompareAndDelete(ctx context.Context, key string, old interface{}) (bool, error) {
+	return compareAndDeleteScript.Run(ctx, c.opts.client, []string{c.AddPrefix(key)}, gconv.String(old)).Bool()
+}
+
 // GetSet 获取设置缓存值
 func (c *KvDB) GetSet(ctx context.Context, key string, fn gkvdb.SetValueFunc) gkvdb.Result {
 	key = c.AddPrefix(key)
@@ -135,6 +159,54 @@ func (c *KvDB) GetSet(ctx context.Context, key string, fn gkvdb.SetValueFunc) gk
 	return rst.(gkvdb.Result)
 }
 
+// MGet 批量获取缓存值
+func (c *KvDB) MGet(ctx context.Context, keys ...string) ([]gkvdb.Result, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	prefixedKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixedKeys = append(prefixedKeys, c.AddPrefix(key))
+	}
+
+	vals, err := c.opts.client.MGet(ctx, prefixedKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]gkvdb.Result, 0, len(vals))
+	for _, val := range vals {
+		if val == nil || val == c.opts.nilValue {
+			results = append(results, gkvdb.NewResult(nil, gerrors.ErrNil))
+		} else {
+			results = append(results, gkvdb.NewResult(val))
+		}
+	}
+
+	return results, nil
+}
+
+// MSet 批量设置缓存值
+func (c *KvDB) MSet(ctx context.Context, values map[string]interface{}, expiration ...time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_, err := c.opts.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			if len(expiration) > 0 {
+				pipe.Set(ctx, c.AddPrefix(key), gconv.String(value), expiration[0])
+			} else {
+				pipe.Set(ctx, c.AddPrefix(key), gconv.String(value), redis.KeepTTL)
+			}
+		}
+		return nil
+	})
+
+	return err
+}
+
 // Delete 删除缓存
 func (c *KvDB) Delete(ctx context.Context, keys ...string) (bool, error) {
 	prefixedKeys := make([]string, 0, len(keys))
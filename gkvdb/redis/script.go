@@ -0,0 +1,32 @@
+package redis
+
+import "github.com/go-redis/redis/v8"
+
+// 缓存值等于期望值时设置新值；过期毫秒数大于0时重新设置过期时间，否则保留原有过期时间
+// KEYS[1]：缓存键；ARGV：期望值、新值、过期毫秒数
+const compareAndSwap = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[2], 'KEEPTTL')
+end
+return 1
+`
+
+// 缓存值等于期望值时删除缓存
+// KEYS[1]：缓存键；ARGV：期望值
+const compareAndDelete = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+return 1
+`
+
+var (
+	compareAndSwapScript   = redis.NewScript(compareAndSwap)
+	compareAndDeleteScript = redis.NewScript(compareAndDelete)
+)
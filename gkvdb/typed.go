@@ -0,0 +1,64 @@
+package gkvdb
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gencoding"
+	"github.com/goodluck0107/gcore/gutils/greflect"
+	"reflect"
+	"time"
+)
+
+// GetAs 获取缓存值，并使用编解码器解码为指定类型
+func GetAs[T any](ctx context.Context, db KvDB, codec gencoding.Codec, key string) (T, error) {
+	return decode[T](codec, db.Get(ctx, key))
+}
+
+// SetAs 使用编解码器编码后设置缓存值
+func SetAs[T any](ctx context.Context, db KvDB, codec gencoding.Codec, key string, value T, expiration ...time.Duration) error {
+	buf, err := codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return db.Set(ctx, key, string(buf), expiration...)
+}
+
+// GetSetAs 获取设置缓存值
+// 缓存值使用编解码器编解码，替代直接以字符串形式存取；fn返回nil时缓存空值
+func GetSetAs[T any](ctx context.Context, db KvDB, codec gencoding.Codec, key string, fn func() (T, error)) (T, error) {
+	return decode[T](codec, db.GetSet(ctx, key, func() (interface{}, error) {
+		val, err := fn()
+		if err != nil {
+			return nil, err
+		}
+
+		if greflect.IsNil(val) {
+			return nil, nil
+		}
+
+		buf, err := codec.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+
+		return string(buf), nil
+	}))
+}
+
+// 解码缓存值
+func decode[T any](codec gencoding.Codec, rst Result) (v T, err error) {
+	str, err := rst.String()
+	if err != nil {
+		return
+	}
+
+	// 指针类型需先分配内存，以便proto等编解码器直接解码
+	if rt := reflect.TypeOf(v); rt != nil && rt.Kind() == reflect.Ptr {
+		v = reflect.New(rt.Elem()).Interface().(T)
+		err = codec.Unmarshal([]byte(str), v)
+	} else {
+		err = codec.Unmarshal([]byte(str), &v)
+	}
+
+	return
+}
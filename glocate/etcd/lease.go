@@ -0,0 +1,146 @@
+package etcd
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/glog"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"strconv"
+	"time"
+)
+
+// Lease 租用实例并持续续约
+// 租约ID写入etcd，其他进程为该实例绑定用户时会将绑定关系附加到该租约上
+func (l *Locator) Lease(ctx context.Context, kind, insID string) error {
+	if l.err != nil {
+		return l.err
+	}
+
+	leaseID, err := l.grant(ctx, kind, insID)
+	if err != nil {
+		return err
+	}
+
+	leaseCtx, cancel := context.WithCancel(l.ctx)
+	if v, loaded := l.leases.Swap(l.leaseKey(kind, insID), cancel); loaded {
+		v.(context.CancelFunc)()
+	}
+
+	go l.keepAlive(leaseCtx, kind, insID, leaseID)
+
+	return nil
+}
+
+// Revoke 撤销实例租约，附加在该租约上的绑定关系将被立即删除
+func (l *Locator) Revoke(ctx context.Context, kind, insID string) error {
+	if l.err != nil {
+		return l.err
+	}
+
+	key := l.leaseKey(kind, insID)
+
+	if v, ok := l.leases.LoadAndDelete(key); ok {
+		v.(context.CancelFunc)()
+	}
+
+	l.leaseID.Delete(key)
+
+	leaseID, err := l.fetchLease(ctx, kind, insID)
+	if err != nil || leaseID == clientv3.NoLease {
+		return err
+	}
+
+	_, err = l.opts.client.Revoke(ctx, leaseID)
+
+	return err
+}
+
+// 申请租约并写入租约ID
+func (l *Locator) grant(ctx context.Context, kind, insID string) (clientv3.LeaseID, error) {
+	ttl := int64(l.opts.leaseTTL / time.Second)
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	res, err := l.opts.client.Grant(ctx, ttl)
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+
+	key := l.leaseKey(kind, insID)
+
+	_, err = l.opts.client.Put(ctx, key, strconv.FormatInt(int64(res.ID), 10), clientv3.WithLease(res.ID))
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+
+	l.leaseID.Store(key, res.ID)
+
+	return res.ID, nil
+}
+
+// 持续续约；租约丢失后重新申请，原租约上的绑定关系已随租约过期删除
+func (l *Locator) keepAlive(ctx context.Context, kind, insID string, leaseID clientv3.LeaseID) {
+	for {
+		if leaseID != clientv3.NoLease {
+			ch, err := l.opts.client.KeepAlive(ctx, leaseID)
+			if err == nil {
+				for range ch {
+				}
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		glog.Warnf("location lease lost, kind: %s id: %s", kind, insID)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.opts.retryInterval):
+		}
+
+		var err error
+		if leaseID, err = l.grant(ctx, kind, insID); err != nil {
+			glog.Errorf("location lease grant failed, kind: %s id: %s err: %v", kind, insID, err)
+		}
+	}
+}
+
+// 查找实例租约ID，优先使用缓存
+func (l *Locator) lookupLease(ctx context.Context, kind, insID string) (clientv3.LeaseID, error) {
+	if v, ok := l.leaseID.Load(l.leaseKey(kind, insID)); ok {
+		return v.(clientv3.LeaseID), nil
+	}
+
+	leaseID, err := l.fetchLease(ctx, kind, insID)
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+
+	if leaseID != clientv3.NoLease {
+		l.leaseID.Store(l.leaseKey(kind, insID), leaseID)
+	}
+
+	return leaseID, nil
+}
+
+// 从etcd中读取实例租约ID；实例未租用时返回clientv3.NoLease
+func (l *Locator) fetchLease(ctx context.Context, kind, insID string) (clientv3.LeaseID, error) {
+	res, err := l.opts.client.Get(ctx, l.leaseKey(kind, insID))
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+
+	if len(res.Kvs) == 0 {
+		return clientv3.NoLease, nil
+	}
+
+	id, err := strconv.ParseInt(string(res.Kvs[0].Value), 10, 64)
+	if err != nil {
+		return clientv3.NoLease, nil
+	}
+
+	return clientv3.LeaseID(id), nil
+}
@@ -0,0 +1,256 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/glocate"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const name = "etcd"
+
+var (
+	_ glocate.Locator = &Locator{}
+	_ glocate.Leaser  = &Locator{}
+)
+
+type Locator struct {
+	err     error
+	ctx     context.Context
+	cancel  context.CancelFunc
+	opts    *options
+	builtin bool
+	leases  sync.Map // 本地维持的实例租约
+	leaseID sync.Map // 实例租约ID缓存
+}
+
+func NewLocator(opts ...Option) *Locator {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.namespace == "" {
+		o.namespace = defaultNamespace
+	}
+
+	l := &Locator{}
+	l.opts = o
+	l.ctx, l.cancel = context.WithCancel(o.ctx)
+
+	if o.client == nil {
+		l.builtin = true
+		o.client, l.err = clientv3.New(clientv3.Config{
+			Endpoints:   o.addrs,
+			DialTimeout: o.dialTimeout,
+		})
+	}
+
+	return l
+}
+
+// Name 获取定位器组件名
+func (l *Locator) Name() string {
+	return name
+}
+
+// LocateGate 定位用户所在网关
+func (l *Locator) LocateGate(ctx context.Context, uid int64) (string, error) {
+	return l.get(ctx, l.gateKey(uid))
+}
+
+// LocateNode 定位用户所在节点
+func (l *Locator) LocateNode(ctx context.Context, uid int64, name string) (string, error) {
+	return l.get(ctx, l.nodeKey(uid, name))
+}
+
+// BindGate 绑定网关
+func (l *Locator) BindGate(ctx context.Context, uid int64, gid string) error {
+	_, err := l.bind(ctx, gcluster.Gate.String(), l.gateKey(uid), gid, "", false)
+	return err
+}
+
+// CompareAndBindGate 仅当用户当前绑定的网关为oldGID时绑定至新网关，oldGID为空表示用户当前未绑定网关
+func (l *Locator) CompareAndBindGate(ctx context.Context, uid int64, oldGID, gid string) (bool, error) {
+	return l.bind(ctx, gcluster.Gate.String(), l.gateKey(uid), gid, oldGID, true)
+}
+
+// BindNode 绑定节点
+func (l *Locator) BindNode(ctx context.Context, uid int64, name, nid string) error {
+	_, err := l.bind(ctx, gcluster.Node.String(), l.nodeKey(uid, name), nid, "", false)
+	return err
+}
+
+// CompareAndBindNode 仅当用户当前绑定的节点为oldNID时绑定至新节点，oldNID为空表示用户当前未绑定节点
+func (l *Locator) CompareAndBindNode(ctx context.Context, uid int64, name, oldNID, nid string) (bool, error) {
+	return l.bind(ctx, gcluster.Node.String(), l.nodeKey(uid, name), nid, oldNID, true)
+}
+
+// UnbindGate 解绑网关
+func (l *Locator) UnbindGate(ctx context.Context, uid int64, gid string) error {
+	return l.unbind(ctx, l.gateKey(uid), gid)
+}
+
+// UnbindNode 解绑节点
+func (l *Locator) UnbindNode(ctx context.Context, uid int64, name string, nid string) error {
+	return l.unbind(ctx, l.nodeKey(uid, name), nid)
+}
+
+// Watch 监听用户定位变化
+// 基于etcd的修订版本监听，连接中断恢复后从中断处继续监听，不会遗漏事件
+func (l *Locator) Watch(ctx context.Context, kinds ...string) (glocate.Watcher, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	return newWatcher(ctx, l, kinds...)
+}
+
+// Close 关闭定位器
+func (l *Locator) Close() error {
+	l.cancel()
+
+	if l.builtin && l.opts.client != nil {
+		return l.opts.client.Close()
+	}
+
+	return nil
+}
+
+// 获取键值
+func (l *Locator) get(ctx context.Context, key string) (string, error) {
+	if l.err != nil {
+		return "", l.err
+	}
+
+	res, err := l.opts.client.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(res.Kvs) == 0 {
+		return "", nil
+	}
+
+	return string(res.Kvs[0].Value), nil
+}
+
+// 绑定实例；绑定关系附加在实例租约上，实例租约过期后绑定关系随之删除
+// compare为true时，仅当用户当前绑定的实例为expected时绑定
+func (l *Locator) bind(ctx context.Context, kind, key, insID, expected string, compare bool) (bool, error) {
+	if l.err != nil {
+		return false, l.err
+	}
+
+	for i := 0; ; i++ {
+		leaseID, err := l.lookupLease(ctx, kind, insID)
+		if err != nil {
+			return false, err
+		}
+
+		ok, err := l.doBind(ctx, key, insID, expected, compare, leaseID)
+		if err == nil {
+			return ok, nil
+		}
+
+		// 实例已重新租用，缓存的租约ID失效
+		if i == 0 && leaseID != clientv3.NoLease && err == rpctypes.ErrLeaseNotFound {
+			l.leaseID.Delete(l.leaseKey(kind, insID))
+			continue
+		}
+
+		return false, err
+	}
+}
+
+func (l *Locator) doBind(ctx context.Context, key, insID, expected string, compare bool, leaseID clientv3.LeaseID) (bool, error) {
+	var opts []clientv3.OpOption
+	if leaseID != clientv3.NoLease {
+		opts = append(opts, clientv3.WithLease(leaseID))
+	}
+
+	put := clientv3.OpPut(key, insID, opts...)
+
+	if !compare {
+		_, err := l.opts.client.Do(ctx, put)
+		return err == nil, err
+	}
+
+	var cmp clientv3.Cmp
+	if expected == "" {
+		cmp = clientv3.Compare(clientv3.CreateRevision(key), "=", 0)
+	} else {
+		cmp = clientv3.Compare(clientv3.Value(key), "=", expected)
+	}
+
+	res, err := l.opts.client.Txn(ctx).If(cmp).Then(put).Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return res.Succeeded, nil
+}
+
+// 解绑实例；仅当用户当前绑定的实例为insID时解绑
+func (l *Locator) unbind(ctx context.Context, key, insID string) error {
+	if l.err != nil {
+		return l.err
+	}
+
+	_, err := l.opts.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.Value(key), "=", insID)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+
+	return err
+}
+
+func (l *Locator) gateKey(uid int64) string {
+	return fmt.Sprintf("/%s/user/%s/%d", l.opts.namespace, gcluster.Gate.String(), uid)
+}
+
+func (l *Locator) nodeKey(uid int64, name string) string {
+	return fmt.Sprintf("/%s/user/%s/%d/%s", l.opts.namespace, gcluster.Node.String(), uid, name)
+}
+
+func (l *Locator) userPrefix(kind ...string) string {
+	if len(kind) == 1 {
+		return fmt.Sprintf("/%s/user/%s/", l.opts.namespace, kind[0])
+	}
+
+	return fmt.Sprintf("/%s/user/", l.opts.namespace)
+}
+
+func (l *Locator) leaseKey(kind, insID string) string {
+	return fmt.Sprintf("/%s/lease/%s/%s", l.opts.namespace, kind, insID)
+}
+
+// 解析用户定位键
+func (l *Locator) parseKey(key string) (kind string, uid int64, name string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(key, l.userPrefix()), "/")
+	if len(parts) < 2 {
+		return
+	}
+
+	uid, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return
+	}
+
+	switch kind = parts[0]; kind {
+	case gcluster.Gate.String():
+		return kind, uid, "", len(parts) == 2
+	case gcluster.Node.String():
+		if len(parts) != 3 {
+			return
+		}
+		return kind, uid, parts[2], true
+	default:
+		return
+	}
+}
@@ -0,0 +1,97 @@
+package etcd_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/glocate"
+	"github.com/goodluck0107/gcore/glocate/etcd"
+	"github.com/goodluck0107/gcore/gutils/guuid"
+	"testing"
+	"time"
+)
+
+var locator = etcd.NewLocator(
+	etcd.WithAddrs("127.0.0.1:2379"),
+	etcd.WithLeaseTTL(2*time.Second),
+)
+
+func TestLocator_BindGate(t *testing.T) {
+	ctx := context.Background()
+	uid := time.Now().UnixNano()
+	gid1, gid2 := guuid.UUID(), guuid.UUID()
+
+	if err := locator.BindGate(ctx, uid, gid1); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := locator.CompareAndBindGate(ctx, uid, gid2, gid2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok {
+		t.Fatal("bind with mismatched gate succeeded")
+	}
+
+	// 其他网关的解绑不会影响当前绑定
+	if err = locator.UnbindGate(ctx, uid, gid2); err != nil {
+		t.Fatal(err)
+	}
+
+	if gid, _ := locator.LocateGate(ctx, uid); gid != gid1 {
+		t.Fatalf("got gate %s, want %s", gid, gid1)
+	}
+
+	if err = locator.UnbindGate(ctx, uid, gid1); err != nil {
+		t.Fatal(err)
+	}
+
+	if gid, _ := locator.LocateGate(ctx, uid); gid != "" {
+		t.Fatalf("got gate %s, want none", gid)
+	}
+}
+
+func TestLocator_Watch(t *testing.T) {
+	ctx := context.Background()
+	uid := time.Now().UnixNano()
+	nid := guuid.UUID()
+
+	watcher, err := locator.Watch(ctx, gcluster.Node.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	if err = locator.Lease(ctx, gcluster.Node.String(), nid); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = locator.BindNode(ctx, uid, "lobby", nid); err != nil {
+		t.Fatal(err)
+	}
+
+	// 撤销租约后，绑定在该节点上的用户随之解绑
+	if err = locator.Revoke(ctx, gcluster.Node.String(), nid); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []glocate.EventType{glocate.BindNode, glocate.UnbindNode}
+	for len(expected) > 0 {
+		events, err := watcher.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, event := range events {
+			if event.UID != uid {
+				continue
+			}
+
+			if event.Type != expected[0] || event.InsID != nid || event.InsName != "lobby" {
+				t.Fatalf("unexpected event: %+v", event)
+			}
+
+			expected = expected[1:]
+		}
+	}
+}
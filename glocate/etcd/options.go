@@ -0,0 +1,111 @@
+package etcd
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/getc"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"time"
+)
+
+const (
+	defaultAddr          = "127.0.0.1:2379"
+	defaultDialTimeout   = "5s"
+	defaultNamespace     = "locate"
+	defaultLeaseTTL      = "30s"
+	defaultRetryInterval = "3s"
+)
+
+// 连接配置默认与服务注册发现共用
+const (
+	defaultRegistryAddrsKey       = "etc.registry.etcd.addrs"
+	defaultRegistryDialTimeoutKey = "etc.registry.etcd.dialTimeout"
+)
+
+const (
+	defaultAddrsKey         = "etc.locate.etcd.addrs"
+	defaultDialTimeoutKey   = "etc.locate.etcd.dialTimeout"
+	defaultNamespaceKey     = "etc.locate.etcd.namespace"
+	defaultLeaseTTLKey      = "etc.locate.etcd.leaseTTL"
+	defaultRetryIntervalKey = "etc.locate.etcd.retryInterval"
+)
+
+type Option func(o *options)
+
+type options struct {
+	// 上下文
+	// 默认context.Background
+	ctx context.Context
+
+	// 客户端连接地址
+	// 内建客户端配置，默认使用etc.registry.etcd.addrs配置，均未配置时为[]string{"127.0.0.1:2379"}
+	addrs []string
+
+	// 客户端拨号超时时间
+	// 内建客户端配置，默认使用etc.registry.etcd.dialTimeout配置，均未配置时为5秒
+	dialTimeout time.Duration
+
+	// 外部客户端
+	// 外部客户端配置，存在外部客户端时，优先使用外部客户端，默认为nil
+	client *clientv3.Client
+
+	// 命名空间
+	// 默认为locate
+	namespace string
+
+	// 实例租约时长
+	// 实例停止续约超过该时长后，绑定在该实例上的用户将被自动解绑，默认为30秒
+	leaseTTL time.Duration
+
+	// 重试间隔
+	// 租约续约与事件监听失败后的重试间隔，默认为3秒
+	retryInterval time.Duration
+}
+
+func defaultOptions() *options {
+	addrs := getc.Get(defaultRegistryAddrsKey, []string{defaultAddr}).Strings()
+	dialTimeout := getc.Get(defaultRegistryDialTimeoutKey, defaultDialTimeout).Duration()
+
+	return &options{
+		ctx:           context.Background(),
+		addrs:         getc.Get(defaultAddrsKey, addrs).Strings(),
+		dialTimeout:   getc.Get(defaultDialTimeoutKey, dialTimeout).Duration(),
+		namespace:     getc.Get(defaultNamespaceKey, defaultNamespace).String(),
+		leaseTTL:      getc.Get(defaultLeaseTTLKey, defaultLeaseTTL).Duration(),
+		retryInterval: getc.Get(defaultRetryIntervalKey, defaultRetryInterval).Duration(),
+	}
+}
+
+// WithContext 设置上下文
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithAddrs 设置客户端连接地址
+func WithAddrs(addrs ...string) Option {
+	return func(o *options) { o.addrs = addrs }
+}
+
+// WithDialTimeout 设置客户端拨号超时时间
+func WithDialTimeout(dialTimeout time.Duration) Option {
+	return func(o *options) { o.dialTimeout = dialTimeout }
+}
+
+// WithClient 设置外部客户端
+func WithClient(client *clientv3.Client) Option {
+	return func(o *options) { o.client = client }
+}
+
+// WithNamespace 设置命名空间
+func WithNamespace(namespace string) Option {
+	return func(o *options) { o.namespace = namespace }
+}
+
+// WithLeaseTTL 设置实例租约时长
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(o *options) { o.leaseTTL = ttl }
+}
+
+// WithRetryInterval 设置重试间隔
+func WithRetryInterval(retryInterval time.Duration) Option {
+	return func(o *options) { o.retryInterval = retryInterval }
+}
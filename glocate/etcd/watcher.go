@@ -0,0 +1,165 @@
+package etcd
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/glocate"
+	"github.com/goodluck0107/gcore/glog"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"time"
+)
+
+type watcher struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	locator *Locator
+	prefix  string
+	kinds   map[string]struct{}
+	rev     int64 // 下一个待监听的修订版本
+	chWatch clientv3.WatchChan
+}
+
+func newWatcher(ctx context.Context, l *Locator, kinds ...string) (*watcher, error) {
+	w := &watcher{}
+	w.ctx, w.cancel = context.WithCancel(l.ctx)
+	w.locator = l
+	w.prefix = l.userPrefix(kinds...)
+	w.kinds = make(map[string]struct{}, len(kinds))
+
+	for _, kind := range kinds {
+		w.kinds[kind] = struct{}{}
+	}
+
+	if err := w.seek(ctx); err != nil {
+		w.cancel()
+		return nil, err
+	}
+
+	w.open()
+
+	return w, nil
+}
+
+// Next 返回变动事件列表
+// 监听中断后从最后处理的修订版本继续监听；若该修订版本已被压缩，则返回gerrors.ErrResyncRequired，调用方需全量重建本地缓存
+func (w *watcher) Next() ([]*glocate.Event, error) {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case res, ok := <-w.chWatch:
+			if !ok {
+				w.retry()
+				continue
+			}
+
+			if err := res.Err(); err != nil {
+				if res.CompactRevision != 0 || err == rpctypes.ErrCompacted {
+					if err = w.seek(w.ctx); err != nil {
+						w.retry()
+						continue
+					}
+
+					w.open()
+
+					return nil, gerrors.ErrResyncRequired
+				}
+
+				glog.Warnf("location watch failed: %v", err)
+				w.retry()
+				continue
+			}
+
+			events := make([]*glocate.Event, 0, len(res.Events))
+			for _, ev := range res.Events {
+				w.rev = ev.Kv.ModRevision + 1
+
+				if event, ok := w.parse(ev); ok {
+					events = append(events, event)
+				}
+			}
+
+			if len(events) > 0 {
+				return events, nil
+			}
+		}
+	}
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
+
+// 将监听起点定位到当前修订版本
+func (w *watcher) seek(ctx context.Context) error {
+	res, err := w.locator.opts.client.Get(ctx, w.prefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return err
+	}
+
+	w.rev = res.Header.Revision + 1
+
+	return nil
+}
+
+// 从当前修订版本开始监听
+func (w *watcher) open() {
+	w.chWatch = w.locator.opts.client.Watch(clientv3.WithRequireLeader(w.ctx), w.prefix,
+		clientv3.WithPrefix(),
+		clientv3.WithPrevKV(),
+		clientv3.WithRev(w.rev),
+	)
+}
+
+// 等待后重新监听
+func (w *watcher) retry() {
+	select {
+	case <-w.ctx.Done():
+		return
+	case <-time.After(w.locator.opts.retryInterval):
+	}
+
+	w.open()
+}
+
+// 将etcd事件转换为定位事件
+func (w *watcher) parse(ev *clientv3.Event) (*glocate.Event, bool) {
+	kind, uid, name, ok := w.locator.parseKey(string(ev.Kv.Key))
+	if !ok {
+		return nil, false
+	}
+
+	if _, ok = w.kinds[kind]; !ok {
+		return nil, false
+	}
+
+	event := &glocate.Event{UID: uid, InsKind: kind, InsName: name}
+
+	switch ev.Type {
+	case mvccpb.PUT:
+		event.InsID = string(ev.Kv.Value)
+		if kind == gcluster.Gate.String() {
+			event.Type = glocate.BindGate
+		} else {
+			event.Type = glocate.BindNode
+		}
+	case mvccpb.DELETE:
+		if ev.PrevKv == nil {
+			return nil, false
+		}
+
+		event.InsID = string(ev.PrevKv.Value)
+		if kind == gcluster.Gate.String() {
+			event.Type = glocate.UnbindGate
+		} else {
+			event.Type = glocate.UnbindNode
+		}
+	}
+
+	return event, true
+}
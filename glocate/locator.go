@@ -23,8 +23,17 @@ type Locator interface {
 	LocateNode(ctx context.Context, uid int64, name string) (string, error)
 }
 
+// Leaser 实例租约
+// 定位器实现该接口时，网关与节点启动后会持续续约自身实例；实例停止续约后，绑定在该实例上的用户将被自动解绑
+type Leaser interface {
+	// Lease 租用实例并持续续约
+	Lease(ctx context.Context, kind, insID string) error
+	// Revoke 撤销实例租约，绑定在该实例上的用户将被立即清理
+	Revoke(ctx context.Context, kind, insID string) error
+}
+
 type Watcher interface {
-	// Next 返回用户位置列表
+	// Next 返回用户位置列表；监听期间存在无法补发的事件时返回gerrors.ErrResyncRequired，调用方需重建本地缓存
 	Next() ([]*Event, error)
 	// Stop 停止监听
 	Stop() error
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/glocate"
+	"sync"
+)
+
+const name = "memory"
+
+const (
+	gateKind = "gate"
+	nodeKind = "node"
+)
+
+var _ glocate.Locator = &Locator{}
+
+// Locator 进程内用户定位器
+// 适用于单进程部署及集成测试，多个集群组件共享同一个实例即可共享用户位置
+type Locator struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	opts     *options
+	rw       sync.RWMutex
+	gates    map[int64]string
+	nodes    map[int64]map[string]string
+	idx      int64
+	watchers map[int64]*watcher
+}
+
+func NewLocator(opts ...Option) *Locator {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	l := &Locator{}
+	l.ctx, l.cancel = context.WithCancel(o.ctx)
+	l.opts = o
+	l.gates = make(map[int64]string)
+	l.nodes = make(map[int64]map[string]string)
+	l.watchers = make(map[int64]*watcher)
+
+	return l
+}
+
+// Name 获取定位器组件名
+func (l *Locator) Name() string {
+	return name
+}
+
+// LocateGate 定位用户所在网关
+func (l *Locator) LocateGate(ctx context.Context, uid int64) (string, error) {
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	return l.gates[uid], nil
+}
+
+// LocateNode 定位用户所在节点
+func (l *Locator) LocateNode(ctx context.Context, uid int64, name string) (string, error) {
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	return l.nodes[uid][name], nil
+}
+
+// BindGate 绑定网关
+func (l *Locator) BindGate(ctx context.Context, uid int64, gid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	l.gates[uid] = gid
+
+	l.publish(glocate.BindGate, uid, gid)
+
+	return nil
+}
+
+// BindNode 绑定节点
+func (l *Locator) BindNode(ctx context.Context, uid int64, name, nid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	nodes, ok := l.nodes[uid]
+	if !ok {
+		nodes = make(map[string]string)
+		l.nodes[uid] = nodes
+	}
+	nodes[name] = nid
+
+	l.publish(glocate.BindNode, uid, nid, name)
+
+	return nil
+}
+
+// UnbindGate 解绑网关
+func (l *Locator) UnbindGate(ctx context.Context, uid int64, gid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	if oldGID, ok := l.gates[uid]; !ok || oldGID != gid {
+		return nil
+	}
+
+	delete(l.gates, uid)
+
+	l.publish(glocate.UnbindGate, uid, gid)
+
+	return nil
+}
+
+// UnbindNode 解绑节点
+func (l *Locator) UnbindNode(ctx context.Context, uid int64, name string, nid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	nodes, ok := l.nodes[uid]
+	if !ok {
+		return nil
+	}
+
+	if oldNID, ok := nodes[name]; !ok || oldNID != nid {
+		return nil
+	}
+
+	delete(nodes, name)
+	if len(nodes) == 0 {
+		delete(l.nodes, uid)
+	}
+
+	l.publish(glocate.UnbindNode, uid, nid, name)
+
+	return nil
+}
+
+// Watch 监听用户定位变化
+func (l *Locator) Watch(ctx context.Context, kinds ...string) (glocate.Watcher, error) {
+	if err := l.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	l.idx++
+	w := newWatcher(l, l.idx, kinds...)
+	l.watchers[w.idx] = w
+
+	return w, nil
+}
+
+// Close 关闭定位器
+func (l *Locator) Close() error {
+	l.cancel()
+
+	return nil
+}
+
+// 发布事件；调用方需持有写锁，以保证事件按变更顺序投递
+func (l *Locator) publish(typ glocate.EventType, uid int64, insID string, insName ...string) {
+	var (
+		kind string
+		name string
+	)
+	switch typ {
+	case glocate.BindGate, glocate.UnbindGate:
+		kind = gateKind
+	case glocate.BindNode, glocate.UnbindNode:
+		kind = nodeKind
+	}
+
+	if len(insName) > 0 {
+		name = insName[0]
+	}
+
+	event := &glocate.Event{
+		UID:     uid,
+		Type:    typ,
+		InsID:   insID,
+		InsKind: kind,
+		InsName: name,
+	}
+
+	for _, w := range l.watchers {
+		w.notify(event)
+	}
+}
+
+// 回收监听器
+func (l *Locator) recycle(idx int64) {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	delete(l.watchers, idx)
+}
@@ -0,0 +1,92 @@
+package memory_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/glocate"
+	"github.com/goodluck0107/gcore/glocate/memory"
+	"testing"
+)
+
+func TestLocator_BindGate(t *testing.T) {
+	var (
+		locator = memory.NewLocator()
+		ctx     = context.Background()
+	)
+	defer locator.Close()
+
+	if err := locator.BindGate(ctx, 1, "gate-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := locator.UnbindGate(ctx, 1, "gate-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	gid, err := locator.LocateGate(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gid != "gate-1" {
+		t.Fatalf("unexpected gate: %s", gid)
+	}
+
+	if err = locator.UnbindGate(ctx, 1, "gate-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if gid, _ = locator.LocateGate(ctx, 1); gid != "" {
+		t.Fatalf("unexpected gate: %s", gid)
+	}
+}
+
+func TestLocator_BindNode(t *testing.T) {
+	var (
+		locator = memory.NewLocator()
+		ctx     = context.Background()
+	)
+	defer locator.Close()
+
+	_ = locator.BindNode(ctx, 1, "login", "node-1")
+	_ = locator.BindNode(ctx, 1, "game", "node-2")
+
+	if err := locator.UnbindNode(ctx, 1, "login", "node-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if nid, _ := locator.LocateNode(ctx, 1, "login"); nid != "" {
+		t.Fatalf("unexpected node: %s", nid)
+	}
+
+	if nid, _ := locator.LocateNode(ctx, 1, "game"); nid != "node-2" {
+		t.Fatalf("unexpected node: %s", nid)
+	}
+}
+
+func TestLocator_Watch(t *testing.T) {
+	var (
+		locator = memory.NewLocator()
+		ctx     = context.Background()
+	)
+	defer locator.Close()
+
+	watcher, err := locator.Watch(ctx, gcluster.Gate.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	_ = locator.BindGate(ctx, 1, "gate-1")
+	_ = locator.BindNode(ctx, 1, "game", "node-1")
+	_ = locator.UnbindGate(ctx, 1, "gate-1")
+
+	events, err := watcher.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 2 || events[0].Type != glocate.BindGate || events[1].Type != glocate.UnbindGate {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/glocate"
+	"sync"
+)
+
+type watcher struct {
+	idx      int64
+	ctx      context.Context
+	cancel   context.CancelFunc
+	locator  *Locator
+	kinds    map[string]struct{}
+	mu       sync.Mutex
+	events   []*glocate.Event
+	chNotify chan struct{}
+}
+
+func newWatcher(l *Locator, idx int64, kinds ...string) *watcher {
+	w := &watcher{}
+	w.idx = idx
+	w.locator = l
+	w.ctx, w.cancel = context.WithCancel(l.ctx)
+	w.kinds = make(map[string]struct{}, len(kinds))
+	w.chNotify = make(chan struct{}, 1)
+
+	for _, kind := range kinds {
+		w.kinds[kind] = struct{}{}
+	}
+
+	return w
+}
+
+// 通知事件；事件为增量变更，故以无界队列缓存，不会阻塞发布方也不会丢弃事件
+func (w *watcher) notify(event *glocate.Event) {
+	if _, ok := w.kinds[event.InsKind]; !ok {
+		return
+	}
+
+	w.mu.Lock()
+	w.events = append(w.events, event)
+	w.mu.Unlock()
+
+	select {
+	case w.chNotify <- struct{}{}:
+	default:
+	}
+}
+
+// Next 返回变动事件列表
+func (w *watcher) Next() ([]*glocate.Event, error) {
+	for {
+		w.mu.Lock()
+		if len(w.events) > 0 {
+			events := w.events
+			w.events = nil
+			w.mu.Unlock()
+			return events, nil
+		}
+		w.mu.Unlock()
+
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case <-w.chNotify:
+		}
+	}
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.cancel()
+	w.locator.recycle(w.idx)
+
+	return nil
+}
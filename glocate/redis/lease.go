@@ -0,0 +1,292 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/glog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const sweepBatch = 100
+
+// 脚本仅访问 KEYS 中以 {uid} 为哈希标签的用户键，以兼容 Redis Cluster；
+// 实例用户集合与用户键不在同一槽位，由调用方在脚本前后维护
+
+// 绑定网关
+// KEYS[1]：用户网关键；ARGV：网关ID、期望网关ID、是否比较
+const bindGate = `
+local old = redis.call('GET', KEYS[1]) or ''
+if ARGV[3] == '1' and old ~= ARGV[2] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1])
+return 1
+`
+
+// 绑定节点
+// KEYS[1]：用户节点键；ARGV：节点ID、期望节点ID、是否比较、节点名称
+const bindNode = `
+local old = redis.call('HGET', KEYS[1], ARGV[4]) or ''
+if ARGV[3] == '1' and old ~= ARGV[2] then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[4], ARGV[1])
+return 1
+`
+
+// 解绑网关
+// KEYS[1]：用户网关键；ARGV：网关ID
+const unbindGate = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+return 1
+`
+
+// 解绑节点
+// KEYS[1]：用户节点键；ARGV：节点ID、节点名称
+const unbindNode = `
+if redis.call('HGET', KEYS[1], ARGV[2]) ~= ARGV[1] then
+	return 0
+end
+redis.call('HDEL', KEYS[1], ARGV[2])
+return 1
+`
+
+// 认领过期租约，认领期间其他定位器不会重复清理
+// KEYS[1]：租约键；ARGV：实例ID、当前时间、认领截止时间
+const claimLease = `
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score or tonumber(score) > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+return 1
+`
+
+// 清理完成后移除租约；实例在清理期间重新续约时保留租约
+// KEYS[1]：租约键；ARGV：实例ID、认领截止时间
+const removeLease = `
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score or tonumber(score) ~= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+return 1
+`
+
+var (
+	bindGateScript    = redis.NewScript(bindGate)
+	bindNodeScript    = redis.NewScript(bindNode)
+	unbindGateScript  = redis.NewScript(unbindGate)
+	unbindNodeScript  = redis.NewScript(unbindNode)
+	claimLeaseScript  = redis.NewScript(claimLease)
+	removeLeaseScript = redis.NewScript(removeLease)
+)
+
+// Lease 租用实例并持续续约
+func (l *Locator) Lease(ctx context.Context, kind, insID string) error {
+	if l.opts.leaseTTL <= 0 {
+		return nil
+	}
+
+	if err := l.renew(ctx, kind, insID); err != nil {
+		return err
+	}
+
+	leaseCtx, cancel := context.WithCancel(l.ctx)
+	if v, loaded := l.leases.Swap(kind+":"+insID, cancel); loaded {
+		v.(context.CancelFunc)()
+	}
+
+	go func() {
+		ticker := time.NewTicker(l.opts.leaseTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-leaseCtx.Done():
+				return
+			case <-ticker.C:
+				if err := l.renew(leaseCtx, kind, insID); err != nil && leaseCtx.Err() == nil {
+					glog.Errorf("location lease renew failed, kind: %s id: %s err: %v", kind, insID, err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Revoke 撤销实例租约
+func (l *Locator) Revoke(ctx context.Context, kind, insID string) error {
+	if v, ok := l.leases.LoadAndDelete(kind + ":" + insID); ok {
+		v.(context.CancelFunc)()
+	}
+
+	key := fmt.Sprintf(instanceLeaseKey, l.opts.prefix, kind)
+
+	return l.opts.client.ZAddXX(ctx, key, &redis.Z{Score: 0, Member: insID}).Err()
+}
+
+// 续约实例
+func (l *Locator) renew(ctx context.Context, kind, insID string) error {
+	key := fmt.Sprintf(instanceLeaseKey, l.opts.prefix, kind)
+	deadline := time.Now().Add(l.opts.leaseTTL).UnixMilli()
+
+	return l.opts.client.ZAdd(ctx, key, &redis.Z{Score: float64(deadline), Member: insID}).Err()
+}
+
+// 定时清理过期租约
+func (l *Locator) sweep() {
+	ticker := time.NewTicker(l.opts.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, kind := range []string{gcluster.Gate.String(), gcluster.Node.String()} {
+				if err := l.doSweep(l.ctx, kind); err != nil && l.ctx.Err() == nil {
+					glog.Errorf("location lease sweep failed, kind: %s err: %v", kind, err)
+				}
+			}
+		}
+	}
+}
+
+// 清理指定类型的过期租约
+func (l *Locator) doSweep(ctx context.Context, kind string) error {
+	key := fmt.Sprintf(instanceLeaseKey, l.opts.prefix, kind)
+	now := time.Now().UnixMilli()
+
+	insIDs, err := l.opts.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now, 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, insID := range insIDs {
+		deadline := time.Now().Add(l.opts.leaseTTL).UnixMilli()
+
+		claimed, err := claimLeaseScript.Run(ctx, l.opts.client, []string{key}, insID, now, deadline).Bool()
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		if err = l.expire(ctx, kind, insID); err != nil {
+			return err
+		}
+
+		if err = removeLeaseScript.Run(ctx, l.opts.client, []string{key}, insID, deadline).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 解绑过期实例上的所有用户，并发布解绑事件
+func (l *Locator) expire(ctx context.Context, kind, insID string) error {
+	key := fmt.Sprintf(instanceUsersKey, l.opts.prefix, kind, insID)
+
+	for {
+		members, err := l.opts.client.SRandMemberN(ctx, key, sweepBatch).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(members) == 0 {
+			return nil
+		}
+
+		for _, member := range members {
+			if err = l.expireMember(ctx, kind, insID, member); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (l *Locator) expireMember(ctx context.Context, kind, insID, member string) error {
+	switch kind {
+	case gcluster.Gate.String():
+		uid, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			return l.unindex(ctx, kind, insID, member)
+		}
+
+		_, err = l.unbindGate(ctx, uid, insID)
+		return err
+	default:
+		uid, name, ok := parseNodeMember(member)
+		if !ok {
+			return l.unindex(ctx, kind, insID, member)
+		}
+
+		_, err := l.unbindNode(ctx, uid, name, insID)
+		return err
+	}
+}
+
+// 将用户加入实例用户集合
+func (l *Locator) index(ctx context.Context, kind, insID, member string) error {
+	return l.opts.client.SAdd(ctx, fmt.Sprintf(instanceUsersKey, l.opts.prefix, kind, insID), member).Err()
+}
+
+// 将用户移出实例用户集合
+func (l *Locator) unindex(ctx context.Context, kind, insID, member string) error {
+	return l.opts.client.SRem(ctx, fmt.Sprintf(instanceUsersKey, l.opts.prefix, kind, insID), member).Err()
+}
+
+// 解绑后将用户移出实例用户集合；移出期间用户被重新绑定至该实例时重新加入，避免实例过期时遗漏清理
+func (l *Locator) reindex(ctx context.Context, kind, insID, member string, bound func() (string, error)) error {
+	if err := l.unindex(ctx, kind, insID, member); err != nil {
+		return err
+	}
+
+	current, err := bound()
+	if err != nil {
+		if err == redis.Nil {
+			return nil
+		}
+		return err
+	}
+
+	if current != insID {
+		return nil
+	}
+
+	return l.index(ctx, kind, insID, member)
+}
+
+// 节点实例用户集合成员，格式为uid:name
+func nodeMember(uid int64, name string) string {
+	return strconv.FormatInt(uid, 10) + ":" + name
+}
+
+func parseNodeMember(member string) (int64, string, bool) {
+	uid, name, ok := strings.Cut(member, ":")
+	if !ok {
+		return 0, "", false
+	}
+
+	id, err := strconv.ParseInt(uid, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+
+	return id, name, true
+}
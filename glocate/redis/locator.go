@@ -4,31 +4,38 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-redis/redis/v8"
+	"github.com/goodluck0107/gcore/gcluster"
 	"github.com/goodluck0107/gcore/gencoding/json"
 	"github.com/goodluck0107/gcore/glocate"
 	"github.com/goodluck0107/gcore/glog"
 	"golang.org/x/sync/singleflight"
-	"sort"
-	"strings"
+	"strconv"
 	"sync"
 )
 
 const (
-	userGateKey     = "%s:locate:user:%d:gate"     // string
-	userNodeKey     = "%s:locate:user:%d:node"     // hash
-	clusterEventKey = "%s:locate:cluster:%s:event" // channel
+	userGateKey      = "%s:locate:user:{%d}:gate"     // string
+	userNodeKey      = "%s:locate:user:{%d}:node"     // hash
+	clusterEventKey  = "%s:locate:{cluster}:%s:event" // stream
+	instanceLeaseKey = "%s:locate:lease:%s"           // sorted set
+	instanceUsersKey = "%s:locate:users:%s:%s"        // set
 )
 
 const name = "redis"
 
-var _ glocate.Locator = &Locator{}
+const eventField = "event"
+
+var (
+	_ glocate.Locator = &Locator{}
+	_ glocate.Leaser  = &Locator{}
+)
 
 type Locator struct {
-	ctx      context.Context
-	cancel   context.CancelFunc
-	opts     *options
-	sfg      singleflight.Group // singleFlight
-	watchers sync.Map
+	ctx    context.Context
+	cancel context.CancelFunc
+	opts   *options
+	sfg    singleflight.Group // singleFlight
+	leases sync.Map
 }
 
 func NewLocator(opts ...Option) *Locator {
@@ -55,6 +62,10 @@ func NewLocator(opts ...Option) *Locator {
 	l.ctx, l.cancel = context.WithCancel(o.ctx)
 	l.opts = o
 
+	if o.sweepInterval > 0 {
+		go l.sweep()
+	}
+
 	return l
 }
 
@@ -101,84 +112,143 @@ func (l *Locator) LocateNode(ctx context.Context, uid int64, name string) (strin
 
 // BindGate 绑定网关
 func (l *Locator) BindGate(ctx context.Context, uid int64, gid string) error {
-	key := fmt.Sprintf(userGateKey, l.opts.prefix, uid)
-	err := l.opts.client.Set(ctx, key, gid, redis.KeepTTL).Err()
-	if err != nil {
-		return err
-	}
-
-	err = l.publish(ctx, glocate.BindGate, uid, gid)
-	if err != nil {
-		glog.Errorf("location event publish failed: %v", err)
-	}
+	_, err := l.bindGate(ctx, uid, gid, "", false)
+	return err
+}
 
-	return nil
+// CompareAndBindGate 仅当用户当前绑定的网关为oldGID时绑定至新网关，oldGID为空表示用户当前未绑定网关
+func (l *Locator) CompareAndBindGate(ctx context.Context, uid int64, oldGID, gid string) (bool, error) {
+	return l.bindGate(ctx, uid, gid, oldGID, true)
 }
 
 // BindNode 绑定节点
 func (l *Locator) BindNode(ctx context.Context, uid int64, name, nid string) error {
-	key := fmt.Sprintf(userNodeKey, l.opts.prefix, uid)
-	err := l.opts.client.HSet(ctx, key, name, nid).Err()
-	if err != nil {
-		return err
+	_, err := l.bindNode(ctx, uid, name, nid, "", false)
+	return err
+}
+
+// CompareAndBindNode 仅当用户当前绑定的节点为oldNID时绑定至新节点，oldNID为空表示用户当前未绑定节点
+func (l *Locator) CompareAndBindNode(ctx context.Context, uid int64, name, oldNID, nid string) (bool, error) {
+	return l.bindNode(ctx, uid, name, nid, oldNID, true)
+}
+
+// UnbindGate 解绑网关
+func (l *Locator) UnbindGate(ctx context.Context, uid int64, gid string) error {
+	_, err := l.unbindGate(ctx, uid, gid)
+	return err
+}
+
+// UnbindNode 解绑节点
+func (l *Locator) UnbindNode(ctx context.Context, uid int64, name string, nid string) error {
+	_, err := l.unbindNode(ctx, uid, name, nid)
+	return err
+}
+
+// 绑定网关；compare为true时，仅当用户当前绑定的网关为expected时绑定
+// 绑定前先加入实例用户集合，保证已绑定的用户总能在实例过期时被清理；原实例集合中的成员在解绑时移除
+func (l *Locator) bindGate(ctx context.Context, uid int64, gid, expected string, compare bool) (bool, error) {
+	kind, member := gcluster.Gate.String(), strconv.FormatInt(uid, 10)
+
+	if err := l.index(ctx, kind, gid, member); err != nil {
+		return false, err
 	}
 
-	err = l.publish(ctx, glocate.BindNode, uid, nid, name)
+	key := fmt.Sprintf(userGateKey, l.opts.prefix, uid)
+	ok, err := bindGateScript.Run(ctx, l.opts.client, []string{key}, gid, expected, compare).Bool()
 	if err != nil {
+		return false, err
+	}
+
+	// 比较失败时撤销预先加入的成员
+	if !ok {
+		return false, l.reindex(ctx, kind, gid, member, func() (string, error) {
+			return l.opts.client.Get(ctx, key).Result()
+		})
+	}
+
+	// 绑定期间并发的解绑可能已将成员移出集合，绑定成功后再次加入
+	if err = l.index(ctx, kind, gid, member); err != nil {
+		return false, err
+	}
+
+	if err = l.publish(ctx, glocate.BindGate, uid, gid); err != nil {
 		glog.Errorf("location event publish failed: %v", err)
 	}
 
-	return nil
+	return true, nil
 }
 
-// UnbindGate 解绑网关
-func (l *Locator) UnbindGate(ctx context.Context, uid int64, gid string) error {
-	oldGID, err := l.LocateGate(ctx, uid)
+// 绑定节点；compare为true时，仅当用户当前绑定的节点为expected时绑定
+func (l *Locator) bindNode(ctx context.Context, uid int64, name, nid, expected string, compare bool) (bool, error) {
+	kind, member := gcluster.Node.String(), nodeMember(uid, name)
+
+	if err := l.index(ctx, kind, nid, member); err != nil {
+		return false, err
+	}
+
+	key := fmt.Sprintf(userNodeKey, l.opts.prefix, uid)
+	ok, err := bindNodeScript.Run(ctx, l.opts.client, []string{key}, nid, expected, compare, name).Bool()
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	if oldGID == "" || oldGID != gid {
-		return nil
+	if !ok {
+		return false, l.reindex(ctx, kind, nid, member, func() (string, error) {
+			return l.opts.client.HGet(ctx, key, name).Result()
+		})
 	}
 
-	key := fmt.Sprintf(userGateKey, l.opts.prefix, uid)
-	err = l.opts.client.Del(ctx, key).Err()
-	if err != nil {
-		return err
+	if err = l.index(ctx, kind, nid, member); err != nil {
+		return false, err
 	}
 
-	err = l.publish(ctx, glocate.UnbindGate, uid, gid)
-	if err != nil {
+	if err = l.publish(ctx, glocate.BindNode, uid, nid, name); err != nil {
 		glog.Errorf("location event publish failed: %v", err)
 	}
 
-	return nil
+	return true, nil
 }
 
-// UnbindNode 解绑节点
-func (l *Locator) UnbindNode(ctx context.Context, uid int64, name string, nid string) error {
-	oldNID, err := l.LocateNode(ctx, uid, name)
+// 解绑网关；仅当用户当前绑定的网关为gid时解绑，实例用户集合中的成员总是移除
+func (l *Locator) unbindGate(ctx context.Context, uid int64, gid string) (bool, error) {
+	key := fmt.Sprintf(userGateKey, l.opts.prefix, uid)
+	ok, err := unbindGateScript.Run(ctx, l.opts.client, []string{key}, gid).Bool()
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	if oldNID == "" || oldNID != nid {
-		return nil
+	if err = l.reindex(ctx, gcluster.Gate.String(), gid, strconv.FormatInt(uid, 10), func() (string, error) {
+		return l.opts.client.Get(ctx, key).Result()
+	}); err != nil || !ok {
+		return false, err
 	}
 
+	if err = l.publish(ctx, glocate.UnbindGate, uid, gid); err != nil {
+		glog.Errorf("location event publish failed: %v", err)
+	}
+
+	return true, nil
+}
+
+// 解绑节点；仅当用户当前绑定的节点为nid时解绑，实例用户集合中的成员总是移除
+func (l *Locator) unbindNode(ctx context.Context, uid int64, name, nid string) (bool, error) {
 	key := fmt.Sprintf(userNodeKey, l.opts.prefix, uid)
-	err = l.opts.client.Del(ctx, key).Err()
+	ok, err := unbindNodeScript.Run(ctx, l.opts.client, []string{key}, nid, name).Bool()
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	err = l.publish(ctx, glocate.UnbindNode, uid, nid, name)
-	if err != nil {
+	if err = l.reindex(ctx, gcluster.Node.String(), nid, nodeMember(uid, name), func() (string, error) {
+		return l.opts.client.HGet(ctx, key, name).Result()
+	}); err != nil || !ok {
+		return false, err
+	}
+
+	if err = l.publish(ctx, glocate.UnbindNode, uid, nid, name); err != nil {
 		glog.Errorf("location event publish failed: %v", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 func (l *Locator) publish(ctx context.Context, typ glocate.EventType, uid int64, insID string, insName ...string) error {
@@ -208,39 +278,24 @@ func (l *Locator) publish(ctx context.Context, typ glocate.EventType, uid int64,
 		return err
 	}
 
-	return l.opts.client.Publish(ctx, fmt.Sprintf(clusterEventKey, l.opts.prefix, kind), msg).Err()
+	return l.opts.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: fmt.Sprintf(clusterEventKey, l.opts.prefix, kind),
+		MaxLen: l.opts.streamMaxLen,
+		Approx: true,
+		Values: []interface{}{eventField, msg},
+	}).Err()
 }
 
-func (l *Locator) toUniqueKey(kinds ...string) string {
-	sort.Slice(kinds, func(i, j int) bool {
-		return kinds[i] < kinds[j]
-	})
-
-	keys := make([]string, 0, len(kinds))
-	for _, kind := range kinds {
-		keys = append(keys, kind)
-	}
-
-	return strings.Join(keys, "&")
+// Close 关闭定位器
+func (l *Locator) Close() error {
+	l.cancel()
+	return nil
 }
 
 // Watch 监听用户定位变化
+// 每个监听器独立维护事件流游标，连接中断恢复后从游标处继续读取
 func (l *Locator) Watch(ctx context.Context, kinds ...string) (glocate.Watcher, error) {
-	key := l.toUniqueKey(kinds...)
-
-	v, ok := l.watchers.Load(key)
-	if ok {
-		return v.(*watcherMgr).fork(), nil
-	}
-
-	w, err := newWatcherMgr(ctx, l, key, kinds...)
-	if err != nil {
-		return nil, err
-	}
-
-	l.watchers.Store(key, w)
-
-	return w.fork(), nil
+	return newWatcher(ctx, l, kinds...)
 }
 
 func marshal(event *glocate.Event) (string, error) {
@@ -121,3 +121,73 @@ func TestLocator_Watch(t *testing.T) {
 
 	time.Sleep(60 * time.Second)
 }
+
+func TestLocator_CompareAndBindGate(t *testing.T) {
+	ctx := context.Background()
+	uid := time.Now().UnixNano()
+	gid1, gid2 := guuid.UUID(), guuid.UUID()
+
+	ok, err := locator.CompareAndBindGate(ctx, uid, "", gid1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ok {
+		t.Fatal("bind unbound user failed")
+	}
+
+	ok, err = locator.CompareAndBindGate(ctx, uid, gid2, gid2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok {
+		t.Fatal("bind with mismatched gate succeeded")
+	}
+
+	// 其他网关的解绑不会影响当前绑定
+	if err = locator.UnbindGate(ctx, uid, gid2); err != nil {
+		t.Fatal(err)
+	}
+
+	if gid, _ := locator.LocateGate(ctx, uid); gid != gid1 {
+		t.Fatalf("got gate %s, want %s", gid, gid1)
+	}
+}
+
+func TestLocator_Lease(t *testing.T) {
+	ctx := context.Background()
+	l := redis.NewLocator(
+		redis.WithAddrs("127.0.0.1:6379"),
+		redis.WithLeaseTTL(time.Second),
+		redis.WithSweepInterval(200*time.Millisecond),
+	)
+	defer l.Close()
+
+	gid := guuid.UUID()
+	uid := time.Now().UnixNano()
+
+	if err := l.Lease(ctx, gcluster.Gate.String(), gid); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.BindGate(ctx, uid, gid); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(2 * time.Second)
+
+	if val, _ := l.LocateGate(ctx, uid); val != gid {
+		t.Fatalf("binding of a renewing gate was swept")
+	}
+
+	if err := l.Revoke(ctx, gcluster.Gate.String(), gid); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Second)
+
+	if val, _ := l.LocateGate(ctx, uid); val != "" {
+		t.Fatalf("binding of a revoked gate was not swept")
+	}
+}
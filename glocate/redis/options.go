@@ -4,22 +4,29 @@ import (
 	"context"
 	"github.com/go-redis/redis/v8"
 	"github.com/goodluck0107/gcore/getc"
+	"time"
 )
 
 const (
-	defaultAddr       = "127.0.0.1:6379"
-	defaultDB         = 0
-	defaultMaxRetries = 3
-	defaultPrefix     = "gcore"
+	defaultAddr          = "127.0.0.1:6379"
+	defaultDB            = 0
+	defaultMaxRetries    = 3
+	defaultPrefix        = "gcore"
+	defaultLeaseTTL      = "30s"
+	defaultSweepInterval = "10s"
+	defaultStreamMaxLen  = 100000
 )
 
 const (
-	defaultAddrsKey      = "etc.locate.redis.addrs"
-	defaultDBKey         = "etc.locate.redis.db"
-	defaultMaxRetriesKey = "etc.locate.redis.maxRetries"
-	defaultPrefixKey     = "etc.locate.redis.prefix"
-	defaultUsernameKey   = "etc.locate.redis.username"
-	defaultPasswordKey   = "etc.locate.redis.password"
+	defaultAddrsKey         = "etc.locate.redis.addrs"
+	defaultDBKey            = "etc.locate.redis.db"
+	defaultMaxRetriesKey    = "etc.locate.redis.maxRetries"
+	defaultPrefixKey        = "etc.locate.redis.prefix"
+	defaultUsernameKey      = "etc.locate.redis.username"
+	defaultPasswordKey      = "etc.locate.redis.password"
+	defaultLeaseTTLKey      = "etc.locate.redis.leaseTTL"
+	defaultSweepIntervalKey = "etc.locate.redis.sweepInterval"
+	defaultStreamMaxLenKey  = "etc.locate.redis.streamMaxLen"
 )
 
 type Option func(o *options)
@@ -54,6 +61,18 @@ type options struct {
 	// 前缀
 	// key前缀，默认为gcore
 	prefix string
+
+	// 租约时长
+	// 实例停止续约超过该时长后，其绑定关系将被清除，默认为30s，小于等于0时不启用租约
+	leaseTTL time.Duration
+
+	// 清理间隔
+	// 扫描过期租约的时间间隔，默认为10s，小于等于0时不清理
+	sweepInterval time.Duration
+
+	// 事件流最大长度
+	// 定位事件流保留的近似最大事件数，监听器中断期间遗漏的事件超出该长度时需全量重建缓存，默认为100000
+	streamMaxLen int64
 }
 
 func defaultOptions() *options {
@@ -65,6 +84,10 @@ func defaultOptions() *options {
 		prefix:     getc.Get(defaultPrefixKey, defaultPrefix).String(),
 		username:   getc.Get(defaultUsernameKey).String(),
 		password:   getc.Get(defaultPasswordKey).String(),
+
+		leaseTTL:      getc.Get(defaultLeaseTTLKey, defaultLeaseTTL).Duration(),
+		sweepInterval: getc.Get(defaultSweepIntervalKey, defaultSweepInterval).Duration(),
+		streamMaxLen:  getc.Get(defaultStreamMaxLenKey, defaultStreamMaxLen).Int64(),
 	}
 }
 
@@ -107,3 +130,18 @@ func WithClient(client redis.UniversalClient) Option {
 func WithPrefix(prefix string) Option {
 	return func(o *options) { o.prefix = prefix }
 }
+
+// WithLeaseTTL 设置实例租约时长
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(o *options) { o.leaseTTL = ttl }
+}
+
+// WithSweepInterval 设置过期租约清理间隔
+func WithSweepInterval(interval time.Duration) Option {
+	return func(o *options) { o.sweepInterval = interval }
+}
+
+// WithStreamMaxLen 设置事件流最大长度
+func WithStreamMaxLen(maxLen int64) Option {
+	return func(o *options) { o.streamMaxLen = maxLen }
+}
@@ -4,149 +4,206 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-redis/redis/v8"
+	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/glocate"
 	"github.com/goodluck0107/gcore/glog"
-	"sync"
-	"sync/atomic"
+	"strings"
+	"time"
+)
+
+const (
+	initialCursor = "0-0"                  // 事件流不存在时的起始游标
+	readCount     = 100                    // 单次读取的最大事件数
+	readBlock     = 5 * time.Second        // 单次读取的最大阻塞时间
+	retryInterval = 500 * time.Millisecond // 读取失败后的重试间隔
 )
 
 type watcher struct {
-	idx        int64
-	state      int32
-	ctx        context.Context
-	cancel     context.CancelFunc
-	chEvent    chan []*glocate.Event
-	watcherMgr *watcherMgr
+	ctx     context.Context
+	cancel  context.CancelFunc
+	locator *Locator
+	streams []string          // 事件流键，各事件流共用同一个哈希标签以便在集群模式下一并读取
+	cursors map[string]string // 事件流游标，记录该监听器最后一次读取到的事件ID
+	anchors map[string]bool   // 游标是否指向事件流中真实存在的事件；开始监听时事件流为空则游标为流的末尾ID
+	broken  bool              // 读取中断过，需要校验是否存在事件缺失
 }
 
-func newWatcher(wm *watcherMgr, idx int64) *watcher {
+func newWatcher(ctx context.Context, l *Locator, kinds ...string) (*watcher, error) {
 	w := &watcher{}
-	w.idx = idx
-	w.watcherMgr = wm
-	w.ctx, w.cancel = context.WithCancel(wm.ctx)
-	w.chEvent = make(chan []*glocate.Event, 16)
+	w.ctx, w.cancel = context.WithCancel(l.ctx)
+	w.locator = l
+	w.streams = make([]string, 0, len(kinds))
+	w.cursors = make(map[string]string, len(kinds))
+	w.anchors = make(map[string]bool, len(kinds))
 
-	return w
-}
+	for _, kind := range kinds {
+		w.streams = append(w.streams, fmt.Sprintf(clusterEventKey, l.opts.prefix, kind))
+	}
 
-func (w *watcher) notify(events []*glocate.Event) {
-	if atomic.LoadInt32(&w.state) == 0 {
-		return
+	if err := w.seek(ctx); err != nil {
+		w.cancel()
+		return nil, err
 	}
 
-	w.chEvent <- events
+	return w, nil
 }
 
 // Next 返回变动事件列表
+// 与redis的连接中断恢复后，会从中断处补发遗漏的事件；若遗漏的事件已被裁剪，则返回gerrors.ErrResyncRequired，调用方需全量重建本地缓存
 func (w *watcher) Next() ([]*glocate.Event, error) {
-	if atomic.LoadInt32(&w.state) == 0 {
-		atomic.StoreInt32(&w.state, 1)
-	}
+	for {
+		if err := w.ctx.Err(); err != nil {
+			return nil, err
+		}
 
-	select {
-	case <-w.ctx.Done():
-		return nil, w.ctx.Err()
-	case events, ok := <-w.chEvent:
-		if !ok {
-			if err := w.ctx.Err(); err != nil {
-				return nil, err
+		if w.broken {
+			lost, err := w.verify(w.ctx)
+			if err != nil {
+				w.retry(err)
+				continue
+			}
+
+			w.broken = false
+
+			if lost {
+				if err = w.seek(w.ctx); err != nil {
+					w.broken = true
+					w.retry(err)
+					continue
+				}
+
+				return nil, gerrors.ErrResyncRequired
+			}
+		}
+
+		args := make([]string, 0, 2*len(w.streams))
+		args = append(args, w.streams...)
+		for _, stream := range w.streams {
+			args = append(args, w.cursors[stream])
+		}
+
+		streams, err := w.locator.opts.client.XRead(w.ctx, &redis.XReadArgs{
+			Streams: args,
+			Count:   readCount,
+			Block:   readBlock,
+		}).Result()
+		if err != nil {
+			if err == redis.Nil {
+				continue
+			}
+
+			w.broken = true
+			w.retry(err)
+			continue
+		}
+
+		events := make([]*glocate.Event, 0, readCount)
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				w.cursors[stream.Stream] = message.ID
+				w.anchors[stream.Stream] = true
+
+				payload, ok := message.Values[eventField].(string)
+				if !ok {
+					continue
+				}
+
+				event, err := unmarshal([]byte(payload))
+				if err != nil {
+					glog.Errorf("invalid payload, %s", payload)
+					continue
+				}
+
+				events = append(events, event)
 			}
 		}
 
-		return events, nil
+		if len(events) > 0 {
+			return events, nil
+		}
 	}
 }
 
 // Stop 停止监听
 func (w *watcher) Stop() error {
 	w.cancel()
-	close(w.chEvent)
-	return w.watcherMgr.recycle(w.idx)
+	return nil
 }
 
-type watcherMgr struct {
-	ctx     context.Context
-	cancel  context.CancelFunc
-	locator *Locator
-	key     string
-	sub     *redis.PubSub
+// 将游标定位到各事件流的末尾
+// 事件流为空时记录其最后生成的事件ID，事件流不存在时为初始游标
+func (w *watcher) seek(ctx context.Context) error {
+	for _, stream := range w.streams {
+		messages, err := w.locator.opts.client.XRevRangeN(ctx, stream, "+", "-", 1).Result()
+		if err != nil {
+			return err
+		}
 
-	rw       sync.RWMutex
-	idx      int64
-	watchers map[int64]*watcher
-}
+		if len(messages) > 0 {
+			w.cursors[stream] = messages[0].ID
+			w.anchors[stream] = true
+			continue
+		}
 
-func newWatcherMgr(ctx context.Context, l *Locator, key string, kinds ...string) (*watcherMgr, error) {
-	sub := l.opts.client.Subscribe(ctx)
-	channels := make([]string, 0, len(kinds))
-	for _, kind := range kinds {
-		channels = append(channels, fmt.Sprintf(clusterEventKey, l.opts.prefix, kind))
-	}
+		info, err := w.locator.opts.client.XInfoStream(ctx, stream).Result()
+		switch {
+		case err == nil:
+			w.cursors[stream] = info.LastGeneratedID
+		case strings.Contains(err.Error(), "no such key"):
+			w.cursors[stream] = initialCursor
+		default:
+			return err
+		}
 
-	err := sub.Subscribe(ctx, channels...)
-	if err != nil {
-		return nil, err
+		w.anchors[stream] = false
 	}
 
-	wm := &watcherMgr{}
-	wm.ctx, wm.cancel = context.WithCancel(l.ctx)
-	wm.locator = l
-	wm.watchers = make(map[int64]*watcher)
-	wm.key = key
-	wm.sub = sub
+	return nil
+}
+
+// 校验游标之后的事件是否已被裁剪
+// 事件流总是从头部裁剪，游标所指向的事件仍然存在时，其后的事件必然完整
+// 游标未指向真实事件时无法据此判断；事件流按近似长度裁剪后的长度不低于上限，因此长度未达上限时必然未发生裁剪，否则视为存在缺失
+func (w *watcher) verify(ctx context.Context) (bool, error) {
+	for _, stream := range w.streams {
+		cursor := w.cursors[stream]
 
-	go func() {
-		for {
-			iface, err := wm.sub.Receive(wm.ctx)
+		if !w.anchors[stream] {
+			n, err := w.locator.opts.client.XLen(ctx, stream).Result()
 			if err != nil {
-				return
+				return false, err
 			}
 
-			switch v := iface.(type) {
-			case *redis.Message:
-				event, err := unmarshal([]byte(v.Payload))
-				if err != nil {
-					glog.Errorf("invalid payload, %s", v.Payload)
-					continue
-				}
-				wm.broadcast(event)
+			if maxLen := w.locator.opts.streamMaxLen; maxLen > 0 && n >= maxLen {
+				return true, nil
 			}
-		}
-	}()
 
-	return wm, nil
-}
+			continue
+		}
 
-func (wm *watcherMgr) fork() glocate.Watcher {
-	wm.rw.Lock()
-	defer wm.rw.Unlock()
+		messages, err := w.locator.opts.client.XRangeN(ctx, stream, cursor, cursor, 1).Result()
+		if err != nil {
+			return false, err
+		}
 
-	w := newWatcher(wm, atomic.AddInt64(&wm.idx, 1))
-	wm.watchers[w.idx] = w
+		if len(messages) == 0 {
+			return true, nil
+		}
+	}
 
-	return w
+	return false, nil
 }
 
-func (wm *watcherMgr) recycle(idx int64) error {
-	wm.rw.Lock()
-	defer wm.rw.Unlock()
-
-	delete(wm.watchers, idx)
-
-	if len(wm.watchers) == 0 {
-		wm.cancel()
-		wm.locator.watchers.Delete(wm.key)
-		return wm.sub.Close()
+// 读取失败后等待重试
+func (w *watcher) retry(err error) {
+	if w.ctx.Err() != nil {
+		return
 	}
 
-	return nil
-}
-
-func (wm *watcherMgr) broadcast(events ...*glocate.Event) {
-	wm.rw.RLock()
-	defer wm.rw.RUnlock()
+	glog.Warnf("location stream read failed, retry after %v: %v", retryInterval, err)
 
-	for _, w := range wm.watchers {
-		w.notify(events)
+	select {
+	case <-w.ctx.Done():
+	case <-time.After(retryInterval):
 	}
 }
@@ -0,0 +1,24 @@
+package glock
+
+import (
+	"context"
+	"time"
+)
+
+// Locker 分布式锁的存储后端
+type Locker interface {
+	// Name 获取锁组件名
+	Name() string
+	// Acquire 获取互斥锁；同一持有者可重入，返回本次持有的防护令牌，锁被其他持有者占用时返回false
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (token int64, ok bool, err error)
+	// Renew 续期互斥锁；锁已不属于该持有者时返回false
+	Renew(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// Release 释放一次互斥锁，返回剩余的重入次数；锁不属于该持有者时返回gerrors.ErrLockNotHeld
+	Release(ctx context.Context, name, owner string) (int, error)
+	// AcquireSemaphore 获取信号量许可；许可已被占满时返回false
+	AcquireSemaphore(ctx context.Context, name, owner string, size int, ttl time.Duration) (bool, error)
+	// RenewSemaphore 续期信号量许可；许可已不属于该持有者时返回false
+	RenewSemaphore(ctx context.Context, name, owner string, size int, ttl time.Duration) (bool, error)
+	// ReleaseSemaphore 释放信号量许可
+	ReleaseSemaphore(ctx context.Context, name, owner string, size int) error
+}
@@ -0,0 +1,334 @@
+package memcache
+
+import (
+	"context"
+	"fmt"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gutils/grand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const name = "memcache"
+
+const (
+	lockKey      = "%s:lock:%s"         // 互斥锁键
+	fenceKey     = "%s:lock:%s:fence"   // 防护令牌键
+	semaphoreKey = "%s:semaphore:%s:%d" // 信号量许可槽位键
+)
+
+const (
+	casRetries = 10 // CAS冲突时的最大重试次数
+	expired    = -1 // 立即过期
+)
+
+// Locker 基于memcache的分布式锁
+// 通过add保证互斥，通过cas维护重入次数与续期；信号量由size个许可槽位组成，每个槽位同样通过add占用
+// 防护令牌计数器存放于memcache中，可能因内存淘汰或服务重启而丢失并从1重新计数，因此令牌不保证单调递增，
+// 不能作为写入外部存储时的防护依据；需要可靠的防护令牌时应使用redis等持久化后端
+type Locker struct {
+	opts    *options
+	builtin bool
+}
+
+func NewLocker(opts ...Option) *Locker {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	l := &Locker{}
+	l.opts = o
+
+	if o.client == nil {
+		l.builtin = true
+		o.client = memcache.New(o.addrs...)
+	}
+
+	return l
+}
+
+// Name 获取锁组件名
+func (l *Locker) Name() string {
+	return name
+}
+
+// Acquire 获取互斥锁
+func (l *Locker) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (int64, bool, error) {
+	key := fmt.Sprintf(lockKey, l.opts.prefix, name)
+
+	for i := 0; i < casRetries; i++ {
+		item, err := l.opts.client.Get(key)
+		if err != nil {
+			if !gerrors.Is(err, memcache.ErrCacheMiss) {
+				return 0, false, err
+			}
+
+			token, err := l.fence(name)
+			if err != nil {
+				return 0, false, err
+			}
+
+			err = l.opts.client.Add(&memcache.Item{Key: key, Value: encode(owner, 1, token), Expiration: seconds(ttl)})
+			if err == nil {
+				return token, true, nil
+			}
+
+			if !gerrors.Is(err, memcache.ErrNotStored) {
+				return 0, false, err
+			}
+
+			continue
+		}
+
+		holder, count, token, ok := decode(item.Value)
+		if !ok || holder != owner {
+			return 0, false, nil
+		}
+
+		item.Value = encode(owner, count+1, token)
+		item.Expiration = seconds(ttl)
+
+		if err = l.opts.client.CompareAndSwap(item); err == nil {
+			return token, true, nil
+		}
+
+		if !isConflict(err) {
+			return 0, false, err
+		}
+	}
+
+	return 0, false, nil
+}
+
+// Renew 续期互斥锁
+func (l *Locker) Renew(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf(lockKey, l.opts.prefix, name)
+
+	for i := 0; i < casRetries; i++ {
+		item, err := l.opts.client.Get(key)
+		if err != nil {
+			if gerrors.Is(err, memcache.ErrCacheMiss) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		if holder, _, _, ok := decode(item.Value); !ok || holder != owner {
+			return false, nil
+		}
+
+		item.Expiration = seconds(ttl)
+
+		if err = l.opts.client.CompareAndSwap(item); err == nil {
+			return true, nil
+		}
+
+		if !isConflict(err) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// Release 释放一次互斥锁
+func (l *Locker) Release(ctx context.Context, name, owner string) (int, error) {
+	key := fmt.Sprintf(lockKey, l.opts.prefix, name)
+
+	for i := 0; i < casRetries; i++ {
+		item, err := l.opts.client.Get(key)
+		if err != nil {
+			if gerrors.Is(err, memcache.ErrCacheMiss) {
+				return 0, gerrors.ErrLockNotHeld
+			}
+			return 0, err
+		}
+
+		holder, count, token, ok := decode(item.Value)
+		if !ok || holder != owner {
+			return 0, gerrors.ErrLockNotHeld
+		}
+
+		// 重入次数归零时通过cas将锁置为立即过期，避免误删其他持有者的锁
+		if count--; count <= 0 {
+			count = 0
+			item.Expiration = expired
+		}
+		item.Value = encode(owner, count, token)
+
+		if err = l.opts.client.CompareAndSwap(item); err == nil {
+			return count, nil
+		}
+
+		if !isConflict(err) {
+			return 0, err
+		}
+	}
+
+	return 0, gerrors.ErrLockNotHeld
+}
+
+// AcquireSemaphore 获取信号量许可
+func (l *Locker) AcquireSemaphore(ctx context.Context, name, owner string, size int, ttl time.Duration) (bool, error) {
+	if size <= 0 {
+		return false, nil
+	}
+
+	if ok, err := l.RenewSemaphore(ctx, name, owner, size, ttl); err != nil || ok {
+		return ok, err
+	}
+
+	// 从随机槽位开始尝试，分散多个持有者的竞争
+	offset := grand.Int(0, size-1)
+
+	for i := 0; i < size; i++ {
+		key := fmt.Sprintf(semaphoreKey, l.opts.prefix, name, (offset+i)%size)
+
+		err := l.opts.client.Add(&memcache.Item{Key: key, Value: []byte(owner), Expiration: seconds(ttl)})
+		if err == nil {
+			return true, nil
+		}
+
+		if !gerrors.Is(err, memcache.ErrNotStored) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// RenewSemaphore 续期信号量许可
+func (l *Locker) RenewSemaphore(ctx context.Context, name, owner string, size int, ttl time.Duration) (bool, error) {
+	item, err := l.slot(name, owner, size)
+	if err != nil || item == nil {
+		return false, err
+	}
+
+	item.Expiration = seconds(ttl)
+
+	if err = l.opts.client.CompareAndSwap(item); err != nil {
+		if isConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseSemaphore 释放信号量许可
+func (l *Locker) ReleaseSemaphore(ctx context.Context, name, owner string, size int) error {
+	item, err := l.slot(name, owner, size)
+	if err != nil || item == nil {
+		return err
+	}
+
+	item.Expiration = expired
+
+	if err = l.opts.client.CompareAndSwap(item); err != nil && !isConflict(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Close 关闭内建客户端
+func (l *Locker) Close() error {
+	if !l.builtin {
+		return nil
+	}
+
+	return l.opts.client.Close()
+}
+
+// 查找持有者占用的信号量槽位
+func (l *Locker) slot(name, owner string, size int) (*memcache.Item, error) {
+	keys := make([]string, 0, size)
+	for i := 0; i < size; i++ {
+		keys = append(keys, fmt.Sprintf(semaphoreKey, l.opts.prefix, name, i))
+	}
+
+	items, err := l.opts.client.GetMulti(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if string(item.Value) == owner {
+			return item, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// 生成防护令牌
+// 计数器丢失后从1重新计数，生成的令牌可能小于此前已发放的令牌
+func (l *Locker) fence(name string) (int64, error) {
+	key := fmt.Sprintf(fenceKey, l.opts.prefix, name)
+
+	for {
+		token, err := l.opts.client.Increment(key, 1)
+		if err == nil {
+			return int64(token), nil
+		}
+
+		if !gerrors.Is(err, memcache.ErrCacheMiss) {
+			return 0, err
+		}
+
+		err = l.opts.client.Add(&memcache.Item{Key: key, Value: []byte("0")})
+		if err != nil && !gerrors.Is(err, memcache.ErrNotStored) {
+			return 0, err
+		}
+	}
+}
+
+// 编码锁的值：持有者|重入次数|防护令牌
+func encode(owner string, count int, token int64) []byte {
+	return []byte(owner + "|" + strconv.Itoa(count) + "|" + strconv.FormatInt(token, 10))
+}
+
+// 解码锁的值；从右侧解析，持有者中可包含分隔符
+func decode(value []byte) (owner string, count int, token int64, ok bool) {
+	str := string(value)
+
+	i := strings.LastIndexByte(str, '|')
+	if i < 0 {
+		return
+	}
+
+	j := strings.LastIndexByte(str[:i], '|')
+	if j < 0 {
+		return
+	}
+
+	count, err := strconv.Atoi(str[j+1 : i])
+	if err != nil {
+		return
+	}
+
+	token, err = strconv.ParseInt(str[i+1:], 10, 64)
+	if err != nil {
+		return
+	}
+
+	return str[:j], count, token, true
+}
+
+// 是否为cas冲突或条目已被删除
+func isConflict(err error) bool {
+	return gerrors.Is(err, memcache.ErrCASConflict) || gerrors.Is(err, memcache.ErrNotStored) || gerrors.Is(err, memcache.ErrCacheMiss)
+}
+
+// 转换为memcache的过期秒数，不足1秒时按1秒计算
+func seconds(ttl time.Duration) int32 {
+	if ttl < time.Second {
+		return 1
+	}
+
+	return int32((ttl + time.Second - 1) / time.Second)
+}
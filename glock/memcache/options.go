@@ -0,0 +1,54 @@
+package memcache
+
+import (
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/goodluck0107/gcore/getc"
+)
+
+const (
+	defaultAddr   = "127.0.0.1:11211"
+	defaultPrefix = "gcore"
+)
+
+const (
+	defaultAddrsKey  = "etc.lock.memcache.addrs"
+	defaultPrefixKey = "etc.lock.memcache.prefix"
+)
+
+type Option func(o *options)
+
+type options struct {
+	// 客户端连接地址
+	// 内建客户端配置，默认为[]string{"127.0.0.1:11211"}
+	addrs []string
+
+	// 客户端
+	// 外部客户端配置，存在外部客户端时，优先使用外部客户端，默认为nil
+	client *memcache.Client
+
+	// 前缀
+	// key前缀，默认为gcore
+	prefix string
+}
+
+func defaultOptions() *options {
+	return &options{
+		addrs:  getc.Get(defaultAddrsKey, []string{defaultAddr}).Strings(),
+		prefix: getc.Get(defaultPrefixKey, defaultPrefix).String(),
+	}
+}
+
+// WithAddrs 设置连接地址
+func WithAddrs(addrs ...string) Option {
+	return func(o *options) { o.addrs = addrs }
+}
+
+// WithClient 设置外部客户端
+func WithClient(client *memcache.Client) Option {
+	return func(o *options) { o.client = client }
+}
+
+// WithPrefix 设置前缀
+func WithPrefix(prefix string) Option {
+	return func(o *options) { o.prefix = prefix }
+}
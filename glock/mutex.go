@@ -0,0 +1,163 @@
+package glock
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/glog"
+	"sync"
+	"time"
+)
+
+// Mutex 分布式互斥锁
+// 同一持有者可重入；开启看门狗时持有期间自动续期，每次获取返回单调递增的防护令牌，可用于拒绝过期持有者的写入
+type Mutex struct {
+	name   string
+	locker Locker
+	opts   *options
+
+	mu     sync.Mutex
+	holds  int
+	token  int64
+	cancel context.CancelFunc
+}
+
+// NewMutex 创建分布式互斥锁
+func NewMutex(locker Locker, name string, opts ...Option) *Mutex {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Mutex{name: name, locker: locker, opts: o}
+}
+
+// Name 获取锁名称
+func (m *Mutex) Name() string {
+	return m.name
+}
+
+// Owner 获取持有者
+func (m *Mutex) Owner() string {
+	return m.opts.owner
+}
+
+// Token 获取当前持有的防护令牌，未持有时返回0
+func (m *Mutex) Token() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.token
+}
+
+// Lock 阻塞获取锁，直至获取成功或上下文结束
+func (m *Mutex) Lock(ctx context.Context) (int64, error) {
+	for {
+		token, ok, err := m.TryLock(ctx)
+		if err != nil || ok {
+			return token, err
+		}
+
+		if err = wait(ctx, m.opts.retryInterval); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// TryLock 尝试获取锁，锁被其他持有者占用时返回false
+func (m *Mutex) TryLock(ctx context.Context) (int64, bool, error) {
+	token, ok, err := m.locker.Acquire(ctx, m.name, m.opts.owner, m.opts.ttl)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.holds++
+	m.token = token
+
+	if m.holds == 1 && m.opts.watchdog {
+		var watchCtx context.Context
+		watchCtx, m.cancel = context.WithCancel(context.Background())
+		go m.watch(watchCtx)
+	}
+
+	return token, true, nil
+}
+
+// Unlock 释放一次锁；重入次数归零时锁被真正释放
+func (m *Mutex) Unlock(ctx context.Context) error {
+	remaining, err := m.locker.Release(ctx, m.name, m.opts.owner)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case err != nil:
+		// 仅在锁已不属于当前持有者时清理本地状态；网络等临时错误时锁可能仍被持有，保留看门狗以便重试释放
+		if gerrors.Is(err, gerrors.ErrLockNotHeld) {
+			m.reset()
+		}
+	case remaining == 0:
+		m.reset()
+	case m.holds > 0:
+		m.holds--
+	}
+
+	return err
+}
+
+func (m *Mutex) reset() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+
+	m.holds = 0
+	m.token = 0
+}
+
+// 看门狗，持有期间定时续期
+func (m *Mutex) watch(ctx context.Context) {
+	ticker := time.NewTicker(m.opts.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := m.locker.Renew(ctx, m.name, m.opts.owner, m.opts.ttl)
+			if err != nil {
+				if ctx.Err() == nil {
+					glog.Warnf("lock renew failed, name: %s err: %v", m.name, err)
+				}
+				continue
+			}
+
+			if !ok {
+				glog.Warnf("lock lost, name: %s owner: %s", m.name, m.opts.owner)
+
+				m.mu.Lock()
+				if ctx.Err() == nil {
+					m.reset()
+				}
+				m.mu.Unlock()
+				return
+			}
+		}
+	}
+}
+
+// 等待重试间隔
+func wait(ctx context.Context, interval time.Duration) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
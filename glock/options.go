@@ -0,0 +1,66 @@
+package glock
+
+import (
+	"github.com/goodluck0107/gcore/getc"
+	"github.com/goodluck0107/gcore/gutils/guuid"
+	"time"
+)
+
+const (
+	defaultTTL           = "30s"
+	defaultRetryInterval = "100ms"
+	defaultWatchdog      = true
+)
+
+const (
+	defaultTTLKey           = "etc.lock.ttl"
+	defaultRetryIntervalKey = "etc.lock.retryInterval"
+	defaultWatchdogKey      = "etc.lock.watchdog"
+)
+
+type Option func(o *options)
+
+type options struct {
+	// 持有者
+	// 相同持有者可重入同一把锁，默认为随机生成的唯一标识
+	owner string
+
+	// 锁的过期时间，默认为30s
+	ttl time.Duration
+
+	// 阻塞获取时的重试间隔，默认为100ms
+	retryInterval time.Duration
+
+	// 是否开启看门狗
+	// 开启后持有期间每隔ttl/3自动续期，默认为true
+	watchdog bool
+}
+
+func defaultOptions() *options {
+	return &options{
+		owner:         guuid.UUID(),
+		ttl:           getc.Get(defaultTTLKey, defaultTTL).Duration(),
+		retryInterval: getc.Get(defaultRetryIntervalKey, defaultRetryInterval).Duration(),
+		watchdog:      getc.Get(defaultWatchdogKey, defaultWatchdog).Bool(),
+	}
+}
+
+// WithOwner 设置持有者
+func WithOwner(owner string) Option {
+	return func(o *options) { o.owner = owner }
+}
+
+// WithTTL 设置锁的过期时间
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) { o.ttl = ttl }
+}
+
+// WithRetryInterval 设置阻塞获取时的重试间隔
+func WithRetryInterval(retryInterval time.Duration) Option {
+	return func(o *options) { o.retryInterval = retryInterval }
+}
+
+// WithWatchdog 设置是否开启看门狗
+func WithWatchdog(watchdog bool) Option {
+	return func(o *options) { o.watchdog = watchdog }
+}
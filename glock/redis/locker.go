@@ -0,0 +1,293 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/goodluck0107/gcore/gerrors"
+	"sync"
+	"time"
+)
+
+const name = "redis"
+
+const (
+	lockKey      = "%s:lock:{%s}"       // 互斥锁键
+	fenceKey     = "%s:lock:{%s}:fence" // 防护令牌键
+	semaphoreKey = "%s:semaphore:{%s}"  // 信号量键
+)
+
+const (
+	clockDriftFactor = 0.01                 // Redlock时钟漂移系数
+	clockDriftMin    = 2 * time.Millisecond // Redlock最小时钟漂移
+)
+
+// Locker 基于redis的分布式锁
+// 配置多个相互独立的节点时按Redlock算法工作：在多数节点上获取成功且耗时未超出有效期才视为获取成功，
+// 防护令牌取多数节点中的最大值，仅在多数节点的数据未丢失时保证单调递增
+type Locker struct {
+	opts    *options
+	builtin bool
+	clients []redis.UniversalClient
+}
+
+func NewLocker(opts ...Option) *Locker {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	l := &Locker{}
+	l.opts = o
+
+	if len(o.clients) > 0 {
+		l.clients = o.clients
+	} else {
+		if o.client == nil {
+			l.builtin = true
+			o.client = redis.NewUniversalClient(&redis.UniversalOptions{
+				Addrs:      o.addrs,
+				DB:         o.db,
+				Username:   o.username,
+				Password:   o.password,
+				MaxRetries: o.maxRetries,
+			})
+		}
+		l.clients = []redis.UniversalClient{o.client}
+	}
+
+	return l
+}
+
+// Name 获取锁组件名
+func (l *Locker) Name() string {
+	return name
+}
+
+// Acquire 获取互斥锁
+func (l *Locker) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (int64, bool, error) {
+	var (
+		mu       sync.Mutex
+		token    int64
+		acquired = make([]redis.UniversalClient, 0, len(l.clients))
+		keys     = []string{fmt.Sprintf(lockKey, l.opts.prefix, name), fmt.Sprintf(fenceKey, l.opts.prefix, name)}
+		start    = time.Now()
+	)
+
+	n, err := l.each(ctx, ttl, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+		t, err := acquireScript.Run(ctx, client, keys, owner, ttl.Milliseconds()).Int64()
+		if err != nil || t == 0 {
+			return false, err
+		}
+
+		mu.Lock()
+		if t > token {
+			token = t
+		}
+		acquired = append(acquired, client)
+		mu.Unlock()
+
+		return true, nil
+	})
+
+	if l.quorum(n) && l.valid(start, ttl) {
+		return token, true, nil
+	}
+
+	// 未在多数节点上获取成功时，仅撤销本次获取成功的节点，避免误减其他节点上已有的重入次数
+	_, _ = l.run(ctx, acquired, ttl, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+		return true, releaseScript.Run(ctx, client, keys[:1], owner).Err()
+	})
+
+	return 0, false, err
+}
+
+// Renew 续期互斥锁
+func (l *Locker) Renew(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	keys := []string{fmt.Sprintf(lockKey, l.opts.prefix, name)}
+	start := time.Now()
+
+	n, err := l.each(ctx, ttl, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+		return renewScript.Run(ctx, client, keys, owner, ttl.Milliseconds()).Bool()
+	})
+
+	if l.quorum(n) && l.valid(start, ttl) {
+		return true, nil
+	}
+
+	return false, err
+}
+
+// Release 释放一次互斥锁
+func (l *Locker) Release(ctx context.Context, name, owner string) (int, error) {
+	var (
+		mu        sync.Mutex
+		remaining int64
+		keys      = []string{fmt.Sprintf(lockKey, l.opts.prefix, name)}
+	)
+
+	n, err := l.each(ctx, 0, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+		count, err := releaseScript.Run(ctx, client, keys, owner).Int64()
+		if err != nil || count < 0 {
+			return false, err
+		}
+
+		mu.Lock()
+		if count > remaining {
+			remaining = count
+		}
+		mu.Unlock()
+
+		return true, nil
+	})
+
+	if n == 0 {
+		if err != nil {
+			return 0, err
+		}
+		return 0, gerrors.ErrLockNotHeld
+	}
+
+	return int(remaining), nil
+}
+
+// AcquireSemaphore 获取信号量许可
+func (l *Locker) AcquireSemaphore(ctx context.Context, name, owner string, size int, ttl time.Duration) (bool, error) {
+	return l.semaphore(ctx, name, owner, size, ttl, false)
+}
+
+// RenewSemaphore 续期信号量许可
+func (l *Locker) RenewSemaphore(ctx context.Context, name, owner string, size int, ttl time.Duration) (bool, error) {
+	return l.semaphore(ctx, name, owner, size, ttl, true)
+}
+
+// ReleaseSemaphore 释放信号量许可
+func (l *Locker) ReleaseSemaphore(ctx context.Context, name, owner string, _ int) error {
+	key := fmt.Sprintf(semaphoreKey, l.opts.prefix, name)
+
+	n, err := l.each(ctx, 0, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+		return true, client.ZRem(ctx, key, owner).Err()
+	})
+	if n == 0 {
+		return err
+	}
+
+	return nil
+}
+
+// Close 关闭内建客户端
+func (l *Locker) Close() error {
+	if !l.builtin {
+		return nil
+	}
+
+	return l.opts.client.Close()
+}
+
+// 获取或续期信号量许可
+func (l *Locker) semaphore(ctx context.Context, name, owner string, size int, ttl time.Duration, renewOnly bool) (bool, error) {
+	keys := []string{fmt.Sprintf(semaphoreKey, l.opts.prefix, name)}
+	start := time.Now()
+
+	only := "0"
+	if renewOnly {
+		only = "1"
+	}
+
+	var (
+		mu       sync.Mutex
+		acquired = make([]redis.UniversalClient, 0, len(l.clients))
+	)
+
+	n, err := l.each(ctx, ttl, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+		ok, err := acquireSemaphoreScript.Run(ctx, client, keys, owner, size, ttl.Milliseconds(), only).Bool()
+		if ok {
+			mu.Lock()
+			acquired = append(acquired, client)
+			mu.Unlock()
+		}
+		return ok, err
+	})
+
+	if l.quorum(n) && l.valid(start, ttl) {
+		return true, nil
+	}
+
+	// 仅撤销本次获取成功的节点
+	_, _ = l.run(ctx, acquired, ttl, func(ctx context.Context, client redis.UniversalClient) (bool, error) {
+		return true, client.ZRem(ctx, keys[0], owner).Err()
+	})
+
+	return false, err
+}
+
+// 在所有节点上并发执行操作，返回成功的节点数与首个错误
+// Redlock模式下每个节点的操作耗时不超过ttl的十分之一，避免单个节点故障拖垮整体耗时
+func (l *Locker) each(ctx context.Context, ttl time.Duration, fn func(ctx context.Context, client redis.UniversalClient) (bool, error)) (int, error) {
+	return l.run(ctx, l.clients, ttl, fn)
+}
+
+// 在指定节点上并发执行操作，返回成功的节点数与首个错误
+func (l *Locker) run(ctx context.Context, clients []redis.UniversalClient, ttl time.Duration, fn func(ctx context.Context, client redis.UniversalClient) (bool, error)) (int, error) {
+	switch len(clients) {
+	case 0:
+		return 0, nil
+	case 1:
+		ok, err := fn(ctx, clients[0])
+		if ok {
+			return 1, nil
+		}
+		return 0, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		n        int
+		firstErr error
+	)
+
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client redis.UniversalClient) {
+			defer wg.Done()
+
+			nodeCtx, cancel := ctx, context.CancelFunc(func() {})
+			if ttl > 0 {
+				nodeCtx, cancel = context.WithTimeout(ctx, ttl/10)
+			}
+			defer cancel()
+
+			ok, err := fn(nodeCtx, client)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if ok {
+				n++
+			} else if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(client)
+	}
+
+	wg.Wait()
+
+	return n, firstErr
+}
+
+// 是否在多数节点上成功
+func (l *Locker) quorum(n int) bool {
+	return n >= len(l.clients)/2+1
+}
+
+// 单节点时总是有效；Redlock模式下扣除耗时与时钟漂移后仍有剩余有效期才视为有效
+func (l *Locker) valid(start time.Time, ttl time.Duration) bool {
+	if len(l.clients) == 1 {
+		return true
+	}
+
+	drift := time.Duration(float64(ttl)*clockDriftFactor) + clockDriftMin
+
+	return ttl-time.Since(start)-drift > 0
+}
@@ -0,0 +1,98 @@
+package redis_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/glock"
+	"github.com/goodluck0107/gcore/glock/redis"
+	"github.com/goodluck0107/gcore/gutils/guuid"
+	"testing"
+	"time"
+)
+
+var locker = redis.NewLocker(redis.WithAddrs("127.0.0.1:6379"))
+
+func TestMutex_Lock(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		name = "reward:" + guuid.UUID()
+		m1   = glock.NewMutex(locker, name, glock.WithTTL(time.Second))
+		m2   = glock.NewMutex(locker, name, glock.WithTTL(time.Second))
+	)
+
+	token1, err := m1.Lock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一持有者可重入，防护令牌保持不变
+	token, err := m1.Lock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != token1 {
+		t.Fatalf("got token %d, want %d", token, token1)
+	}
+
+	// 看门狗续期期间其他持有者无法获取
+	timeoutCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if _, err = m2.Lock(timeoutCtx); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want deadline exceeded", err)
+	}
+
+	if err = m2.Unlock(ctx); !gerrors.Is(err, gerrors.ErrLockNotHeld) {
+		t.Fatalf("got %v, want lock not held", err)
+	}
+
+	if err = m1.Unlock(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = m1.Unlock(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	token2, err := m2.Lock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m2.Unlock(ctx)
+
+	if token2 <= token1 {
+		t.Fatalf("got token %d, want greater than %d", token2, token1)
+	}
+}
+
+func TestSemaphore_Acquire(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		name = "settle:" + guuid.UUID()
+		s1   = glock.NewSemaphore(locker, name, 2)
+		s2   = glock.NewSemaphore(locker, name, 2)
+		s3   = glock.NewSemaphore(locker, name, 2)
+	)
+
+	for _, s := range []*glock.Semaphore{s1, s2} {
+		if ok, err := s.TryAcquire(ctx); err != nil || !ok {
+			t.Fatalf("acquire failed: %v", err)
+		}
+	}
+
+	if ok, err := s3.TryAcquire(ctx); err != nil || ok {
+		t.Fatalf("acquire over size: %v %v", ok, err)
+	}
+
+	if err := s1.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := s3.TryAcquire(ctx); err != nil || !ok {
+		t.Fatalf("acquire after release failed: %v", err)
+	}
+
+	_ = s2.Release(ctx)
+	_ = s3.Release(ctx)
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"github.com/go-redis/redis/v8"
+	"github.com/goodluck0107/gcore/getc"
+)
+
+const (
+	defaultAddr       = "127.0.0.1:6379"
+	defaultDB         = 0
+	defaultMaxRetries = 3
+	defaultPrefix     = "gcore"
+)
+
+const (
+	defaultAddrsKey      = "etc.lock.redis.addrs"
+	defaultDBKey         = "etc.lock.redis.db"
+	defaultMaxRetriesKey = "etc.lock.redis.maxRetries"
+	defaultPrefixKey     = "etc.lock.redis.prefix"
+	defaultUsernameKey   = "etc.lock.redis.username"
+	defaultPasswordKey   = "etc.lock.redis.password"
+)
+
+type Option func(o *options)
+
+type options struct {
+	// 客户端连接地址
+	// 内建客户端配置，默认为[]string{"127.0.0.1:6379"}
+	addrs []string
+
+	// 数据库号
+	// 内建客户端配置，默认为0
+	db int
+
+	// 用户名
+	// 内建客户端配置，默认为空
+	username string
+
+	// 密码
+	// 内建客户端配置，默认为空
+	password string
+
+	// 最大重试次数
+	// 内建客户端配置，默认为3次
+	maxRetries int
+
+	// 客户端
+	// 外部客户端配置，存在外部客户端时，优先使用外部客户端，默认为nil
+	client redis.UniversalClient
+
+	// 多节点客户端
+	// 配置多个相互独立的redis节点时，按Redlock算法在多数节点上加锁，优先级高于client，默认为nil
+	clients []redis.UniversalClient
+
+	// 前缀
+	// key前缀，默认为gcore
+	prefix string
+}
+
+func defaultOptions() *options {
+	return &options{
+		addrs:      getc.Get(defaultAddrsKey, []string{defaultAddr}).Strings(),
+		db:         getc.Get(defaultDBKey, defaultDB).Int(),
+		maxRetries: getc.Get(defaultMaxRetriesKey, defaultMaxRetries).Int(),
+		prefix:     getc.Get(defaultPrefixKey, defaultPrefix).String(),
+		username:   getc.Get(defaultUsernameKey).String(),
+		password:   getc.Get(defaultPasswordKey).String(),
+	}
+}
+
+// WithAddrs 设置连接地址
+func WithAddrs(addrs ...string) Option {
+	return func(o *options) { o.addrs = addrs }
+}
+
+// WithDB 设置数据库号
+func WithDB(db int) Option {
+	return func(o *options) { o.db = db }
+}
+
+// WithUsername 设置用户名
+func WithUsername(username string) Option {
+	return func(o *options) { o.username = username }
+}
+
+// WithPassword 设置密码
+func WithPassword(password string) Option {
+	return func(o *options) { o.password = password }
+}
+
+// WithMaxRetries 设置最大重试次数
+func WithMaxRetries(maxRetries int) Option {
+	return func(o *options) { o.maxRetries = maxRetries }
+}
+
+// WithClient 设置外部客户端
+func WithClient(client redis.UniversalClient) Option {
+	return func(o *options) { o.client = client }
+}
+
+// WithClients 设置多个相互独立的redis节点，启用Redlock模式
+func WithClients(clients ...redis.UniversalClient) Option {
+	return func(o *options) { o.clients = clients }
+}
+
+// WithPrefix 设置前缀
+func WithPrefix(prefix string) Option {
+	return func(o *options) { o.prefix = prefix }
+}
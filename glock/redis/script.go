@@ -0,0 +1,71 @@
+package redis
+
+import "github.com/go-redis/redis/v8"
+
+// 获取互斥锁；同一持有者重入时增加重入次数并续期，返回防护令牌，锁被占用时返回0
+// KEYS[1]：锁键；KEYS[2]：防护令牌键；ARGV：持有者、过期毫秒数
+const acquire = `
+local owner = redis.call('HGET', KEYS[1], 'owner')
+if not owner then
+	local token = redis.call('INCR', KEYS[2])
+	redis.call('HSET', KEYS[1], 'owner', ARGV[1], 'count', 1, 'token', token)
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return token
+end
+if owner ~= ARGV[1] then
+	return 0
+end
+redis.call('HINCRBY', KEYS[1], 'count', 1)
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return tonumber(redis.call('HGET', KEYS[1], 'token'))
+`
+
+// 续期互斥锁
+// KEYS[1]：锁键；ARGV：持有者、过期毫秒数
+const renew = `
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then
+	return 0
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`
+
+// 释放一次互斥锁，返回剩余重入次数，锁不属于该持有者时返回-1
+// KEYS[1]：锁键；ARGV：持有者
+const release = `
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then
+	return -1
+end
+local count = redis.call('HINCRBY', KEYS[1], 'count', -1)
+if count <= 0 then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+return count
+`
+
+// 获取或续期信号量许可；持有者以过期时间为分值存放于有序集合中
+// 过期时间以redis服务端时间计算，避免各客户端之间的时钟偏差导致许可被提前清理或长期占用
+// KEYS[1]：信号量键；ARGV：持有者、许可数、过期毫秒数、是否仅续期
+const acquireSemaphore = `
+if redis.replicate_commands then
+	redis.replicate_commands()
+end
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+local held = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not held and (ARGV[4] == '1' or redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[2])) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], now + tonumber(ARGV[3]), ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`
+
+var (
+	acquireScript          = redis.NewScript(acquire)
+	renewScript            = redis.NewScript(renew)
+	releaseScript          = redis.NewScript(release)
+	acquireSemaphoreScript = redis.NewScript(acquireSemaphore)
+)
@@ -0,0 +1,112 @@
+package glock
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/glog"
+	"sync"
+	"time"
+)
+
+// Semaphore 分布式计数信号量
+// 同时最多有size个持有者获得许可；开启看门狗时持有期间自动续期
+type Semaphore struct {
+	name   string
+	size   int
+	locker Locker
+	opts   *options
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewSemaphore 创建分布式计数信号量
+func NewSemaphore(locker Locker, name string, size int, opts ...Option) *Semaphore {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Semaphore{name: name, size: size, locker: locker, opts: o}
+}
+
+// Name 获取信号量名称
+func (s *Semaphore) Name() string {
+	return s.name
+}
+
+// Acquire 阻塞获取许可，直至获取成功或上下文结束
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	for {
+		ok, err := s.TryAcquire(ctx)
+		if err != nil || ok {
+			return err
+		}
+
+		if err = wait(ctx, s.opts.retryInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// TryAcquire 尝试获取许可，许可已被占满时返回false
+func (s *Semaphore) TryAcquire(ctx context.Context) (bool, error) {
+	ok, err := s.locker.AcquireSemaphore(ctx, s.name, s.opts.owner, s.size, s.opts.ttl)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil && s.opts.watchdog {
+		var watchCtx context.Context
+		watchCtx, s.cancel = context.WithCancel(context.Background())
+		go s.watch(watchCtx)
+	}
+
+	return true, nil
+}
+
+// Release 释放许可
+func (s *Semaphore) Release(ctx context.Context) error {
+	s.stop()
+
+	return s.locker.ReleaseSemaphore(ctx, s.name, s.opts.owner, s.size)
+}
+
+func (s *Semaphore) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// 看门狗，持有期间定时续期
+func (s *Semaphore) watch(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := s.locker.RenewSemaphore(ctx, s.name, s.opts.owner, s.size, s.opts.ttl)
+			if err != nil {
+				if ctx.Err() == nil {
+					glog.Warnf("semaphore renew failed, name: %s err: %v", s.name, err)
+				}
+				continue
+			}
+
+			if !ok {
+				glog.Warnf("semaphore lost, name: %s owner: %s", s.name, s.opts.owner)
+				s.stop()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package gcron
+
+import (
+	"context"
+	"fmt"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/glog"
+	"github.com/goodluck0107/gcore/gmodules"
+	"github.com/goodluck0107/gcore/gwrap/info"
+	"sync"
+	"time"
+)
+
+// 单次调度最多处理的计划数；长时间停机后超出部分将直接跳过
+const maxPending = 1000
+
+var (
+	ErrInvalidSpec    = gerrors.New("invalid cron spec")
+	ErrInvalidJobName = gerrors.New("invalid job name")
+	ErrJobExists      = gerrors.New("job exists")
+	ErrNotFoundJob    = gerrors.New("not found job")
+)
+
+var _ gmodules.Module = &Scheduler{}
+
+type Scheduler struct {
+	gmodules.Base
+	opts    *options
+	ctx     context.Context
+	cancel  context.CancelFunc
+	proxy   *Proxy
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+	chWake  chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewScheduler(opts ...Option) *Scheduler {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.store == nil {
+		o.store = NewMemoryStore()
+	}
+
+	s := &Scheduler{}
+	s.opts = o
+	s.ctx, s.cancel = context.WithCancel(o.ctx)
+	s.proxy = newProxy(s)
+	s.jobs = make(map[string]*job)
+	s.chWake = make(chan struct{}, 1)
+
+	return s
+}
+
+// Name 组件名称
+func (s *Scheduler) Name() string {
+	return s.opts.name
+}
+
+// Start 启动组件
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+
+	now := time.Now()
+
+	for _, j := range jobs {
+		s.prepare(j, now)
+	}
+
+	s.mu.Lock()
+	s.started = true
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.run()
+
+	info.PrintBoxInfo("Cron",
+		fmt.Sprintf("Name: %s", s.opts.name),
+		fmt.Sprintf("Location: %s", s.opts.location),
+		fmt.Sprintf("Jobs: %d", len(jobs)),
+	)
+}
+
+// Close 关闭组件；等待执行中的任务结束
+func (s *Scheduler) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Proxy 获取调度器代理
+func (s *Scheduler) Proxy() *Proxy {
+	return s.proxy
+}
+
+// 添加任务
+func (s *Scheduler) addJob(name, spec string, handler JobHandler, opts ...JobOption) error {
+	if name == "" || handler == nil {
+		return ErrInvalidJobName
+	}
+
+	schedule, err := Parse(spec, s.opts.location)
+	if err != nil {
+		return err
+	}
+
+	o := defaultJobOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	j := &job{name: name, spec: spec, schedule: schedule, handler: handler, opts: o}
+
+	s.mu.Lock()
+	_, ok := s.jobs[name]
+	started := s.started
+	s.mu.Unlock()
+
+	if ok {
+		return ErrJobExists
+	}
+
+	if started {
+		s.prepare(j, time.Now())
+	}
+
+	s.mu.Lock()
+	if _, ok = s.jobs[name]; ok {
+		s.mu.Unlock()
+		return ErrJobExists
+	}
+	s.jobs[name] = j
+	s.mu.Unlock()
+
+	s.wake()
+
+	return nil
+}
+
+// 移除任务
+func (s *Scheduler) removeJob(name string) bool {
+	s.mu.Lock()
+	_, ok := s.jobs[name]
+	delete(s.jobs, name)
+	s.mu.Unlock()
+
+	if ok {
+		s.wake()
+	}
+
+	return ok
+}
+
+// 加载任务
+func (s *Scheduler) loadJob(name string) (*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+
+	return j, ok
+}
+
+// 准备任务；存在执行记录时从上一次计划执行时间开始调度，以便补偿停机期间错过的计划
+func (s *Scheduler) prepare(j *job, now time.Time) {
+	last, err := s.opts.store.Last(s.ctx, j.name)
+	if err != nil {
+		glog.Warnf("load the last run of cron job %s failed: %v", j.name, err)
+	}
+
+	if last.IsZero() {
+		j.next = j.schedule.Next(now)
+	} else {
+		j.prev, j.next = last, j.schedule.Next(last)
+	}
+}
+
+// 唤醒调度协程
+func (s *Scheduler) wake() {
+	select {
+	case s.chWake <- struct{}{}:
+	default:
+	}
+}
+
+// 调度
+func (s *Scheduler) run() {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		d := time.Hour
+		if next := s.earliest(); !next.IsZero() {
+			d = time.Until(next)
+		}
+
+		timer.Reset(d)
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.chWake:
+		case now := <-timer.C:
+			s.dispatch(now)
+		}
+	}
+}
+
+// 获取最早的计划执行时间
+func (s *Scheduler) earliest() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var earliest time.Time
+
+	for _, j := range s.jobs {
+		if !j.next.IsZero() && (earliest.IsZero() || j.next.Before(earliest)) {
+			earliest = j.next
+		}
+	}
+
+	return earliest
+}
+
+// 分发到期的任务
+func (s *Scheduler) dispatch(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		var due []time.Time
+
+		for !j.next.IsZero() && !j.next.After(now) {
+			due = append(due, j.next)
+
+			j.prev, j.next = j.next, j.schedule.Next(j.next)
+
+			if len(due) >= maxPending {
+				j.next = j.schedule.Next(now)
+				break
+			}
+		}
+
+		if len(due) > 0 {
+			s.trigger(j, due, now)
+		}
+	}
+}
+
+// 按错过执行策略触发任务
+func (s *Scheduler) trigger(j *job, due []time.Time, now time.Time) {
+	type run struct {
+		scheduled time.Time
+		catchUp   bool
+	}
+
+	var (
+		runs   []run
+		normal time.Time
+		missed = due
+	)
+
+	if latest := due[len(due)-1]; now.Sub(latest) <= s.opts.threshold {
+		normal, missed = latest, due[:len(due)-1]
+	}
+
+	switch j.opts.misfire {
+	case MisfireRunAll:
+		n := min(len(missed), j.opts.maxCatchUp)
+		for _, scheduled := range missed[len(missed)-n:] {
+			runs = append(runs, run{scheduled: scheduled, catchUp: true})
+		}
+		missed = missed[:len(missed)-n]
+	case MisfireRunOnce:
+		if normal.IsZero() && len(missed) > 0 {
+			runs = append(runs, run{scheduled: missed[len(missed)-1], catchUp: true})
+			missed = missed[:len(missed)-1]
+		}
+	}
+
+	if !normal.IsZero() {
+		runs = append(runs, run{scheduled: normal})
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		for _, scheduled := range missed {
+			j.record(&Record{Name: j.name, Scheduled: scheduled, Status: StatusMissed, Reason: "misfire"}, s.opts.historySize)
+		}
+
+		for _, r := range runs {
+			s.exec(j, r.scheduled, r.catchUp)
+		}
+
+		if err := s.opts.store.Save(s.ctx, j.name, due[len(due)-1]); err != nil {
+			glog.Warnf("save the last run of cron job %s failed: %v", j.name, err)
+		}
+	}()
+}
+
+// 执行任务
+func (s *Scheduler) exec(j *job, scheduled time.Time, catchUp bool) {
+	r := &Record{Name: j.name, Scheduled: scheduled, CatchUp: catchUp}
+
+	defer j.record(r, s.opts.historySize)
+
+	// 先检测本地是否仍在执行，避免占用租约后跳过，导致所有实例均未执行本次调度
+	if !j.running.TryLock() {
+		r.Status, r.Reason = StatusSkipped, "previous run is still running"
+		return
+	}
+	defer j.running.Unlock()
+
+	if j.opts.singleton {
+		ok, err := s.opts.store.Acquire(s.ctx, fmt.Sprintf("%s:%d", j.name, scheduled.Unix()), j.opts.lease)
+		if err != nil {
+			r.Status, r.Reason = StatusFailed, err.Error()
+			return
+		}
+
+		if !ok {
+			r.Status, r.Reason = StatusSkipped, "executed by another instance"
+			return
+		}
+	}
+
+	ctx := s.ctx
+	if j.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.opts.timeout)
+		defer cancel()
+	}
+
+	r.Start = time.Now()
+
+	err := call(ctx, j.handler)
+
+	r.Duration = time.Since(r.Start)
+
+	if err != nil {
+		r.Status, r.Reason = StatusFailed, err.Error()
+		glog.Errorf("cron job %s scheduled at %s failed: %v", j.name, scheduled.Format(time.RFC3339), err)
+	} else {
+		r.Status = StatusSucceeded
+	}
+}
+
+// 调用任务处理器
+func call(ctx context.Context, handler JobHandler) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("panic: %v", e)
+		}
+	}()
+
+	return handler(ctx)
+}
@@ -0,0 +1,224 @@
+package gcron_test
+
+import (
+	"context"
+	"errors"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"github.com/goodluck0107/gcore/gmodules/gcron"
+	"github.com/goodluck0107/gcore/gutils/gconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParse(t *testing.T) {
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+
+	cases := []struct {
+		spec string
+		from time.Time
+		next time.Time
+	}{
+		{"CRON_TZ=Asia/Shanghai 0 5 * * *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 5, 0, 0, 0, shanghai).AddDate(0, 0, 1)},
+		{"*/15 * * * * *", time.Date(2024, 3, 1, 0, 0, 7, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 15, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 9, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 9, 8, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2024, 3, 1, 0, 0, 0, 500, time.UTC), time.Date(2024, 3, 1, 0, 1, 30, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		schedule, err := gcron.Parse(c.spec, time.UTC)
+		if err != nil {
+			t.Fatalf("parse %s failed: %v", c.spec, err)
+		}
+
+		if next := schedule.Next(c.from); !next.Equal(c.next) {
+			t.Errorf("%s: expected %s, got %s", c.spec, c.next, next)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 32 * *", "@every 1ms", "CRON_TZ=Nowhere/City * * * * *"} {
+		if _, err := gcron.Parse(spec); err == nil {
+			t.Errorf("%q: expected error", spec)
+		}
+	}
+
+	if _, err := gcron.Parse("* * * * * * *"); !errors.Is(err, gcron.ErrInvalidSpec) {
+		t.Errorf("expected ErrInvalidSpec, got %v", err)
+	}
+}
+
+func TestSingleton(t *testing.T) {
+	var (
+		store    = gcron.NewMemoryStore()
+		executed atomic.Int32
+		handler  = func(ctx context.Context) error {
+			executed.Add(1)
+			return nil
+		}
+	)
+
+	schedulers := make([]*gcron.Scheduler, 2)
+	for i := range schedulers {
+		schedulers[i] = gcron.NewScheduler(gcron.WithStore(store))
+
+		if err := schedulers[i].Proxy().AddJob("report", "@every 1s", handler, gcron.WithSingleton()); err != nil {
+			t.Fatal(err)
+		}
+
+		schedulers[i].Start()
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+
+	counts := make(map[gcron.Status]int)
+	for _, s := range schedulers {
+		s.Close()
+
+		records, err := s.Proxy().History("report")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range records {
+			counts[r.Status]++
+		}
+	}
+
+	if counts[gcron.StatusSucceeded] == 0 || counts[gcron.StatusSucceeded] != int(executed.Load()) {
+		t.Fatalf("unexpected records: %v, executed: %d", counts, executed.Load())
+	}
+
+	if counts[gcron.StatusSucceeded] != counts[gcron.StatusSkipped] {
+		t.Fatalf("each schedule should be executed exactly once: %v", counts)
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	store := gcron.NewMemoryStore()
+
+	// 模拟停机两年后重启
+	if err := store.Save(context.Background(), "yearly", time.Now().AddDate(-2, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{}, 1)
+
+	s := gcron.NewScheduler(gcron.WithStore(store))
+
+	if err := s.Proxy().AddJob("yearly", "0 0 1 1 *", func(ctx context.Context) error {
+		done <- struct{}{}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Start()
+	defer s.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("missed schedule was not caught up")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	records, err := s.Proxy().History("yearly")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := records[len(records)-1]
+	if last.Status != gcron.StatusSucceeded || !last.CatchUp {
+		t.Fatalf("unexpected record: %+v", last)
+	}
+
+	for _, r := range records[:len(records)-1] {
+		if r.Status != gcron.StatusMissed {
+			t.Fatalf("unexpected record: %+v", r)
+		}
+	}
+
+	if _, err = s.Proxy().History("none"); !errors.Is(err, gcron.ErrNotFoundJob) {
+		t.Fatalf("expected ErrNotFoundJob, got %v", err)
+	}
+}
+
+type memoryKvDB struct {
+	gkvdb.KvDB
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (db *memoryKvDB) Get(_ context.Context, key string, _ ...interface{}) gkvdb.Result {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if val, ok := db.values[key]; ok {
+		return gkvdb.NewResult(val)
+	}
+
+	return gkvdb.NewResult(nil, gerrors.ErrNil)
+}
+
+func (db *memoryKvDB) SetNX(_ context.Context, key string, value interface{}, _ ...time.Duration) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.values[key]; ok {
+		return false, nil
+	}
+
+	db.values[key] = gconv.String(value)
+
+	return true, nil
+}
+
+func (db *memoryKvDB) CompareAndSwap(_ context.Context, key string, old, new interface{}, _ ...time.Duration) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if val, ok := db.values[key]; !ok || val != gconv.String(old) {
+		return false, nil
+	}
+
+	db.values[key] = gconv.String(new)
+
+	return true, nil
+}
+
+func TestKvDBStore_Save(t *testing.T) {
+	ctx := context.Background()
+	store := gcron.NewKvDBStore(&memoryKvDB{values: make(map[string]string)})
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 10; i > 0; i-- {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := store.Save(ctx, "job", base.Add(time.Duration(i)*time.Minute)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := store.Save(ctx, "job", base); err != nil {
+		t.Fatal(err)
+	}
+
+	last, err := store.Last(ctx, "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := base.Add(10 * time.Minute); !last.Equal(want) {
+		t.Fatalf("last = %v, want %v", last, want)
+	}
+}
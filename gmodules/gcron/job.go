@@ -0,0 +1,129 @@
+package gcron
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLease      = time.Minute // 默认执行租约时长
+	defaultMaxCatchUp = 10          // 默认单次最多补偿执行次数
+)
+
+// Misfire 错过执行的处理策略
+type Misfire int
+
+const (
+	MisfireRunOnce Misfire = iota // 仅补偿执行最近一次错过的计划（默认）
+	MisfireRunAll                 // 逐次补偿执行所有错过的计划
+	MisfireSkip                   // 跳过所有错过的计划
+)
+
+// Status 执行状态
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded" // 执行成功
+	StatusFailed    Status = "failed"    // 执行失败
+	StatusSkipped   Status = "skipped"   // 跳过执行；已由其他实例执行或上一次执行尚未结束
+	StatusMissed    Status = "missed"    // 错过执行；按错过执行策略未补偿执行
+)
+
+type JobHandler func(ctx context.Context) error
+
+// Record 执行记录
+type Record struct {
+	Name      string        `json:"name"`      // 任务名称
+	Scheduled time.Time     `json:"scheduled"` // 计划执行时间
+	Start     time.Time     `json:"start"`     // 实际开始时间
+	Duration  time.Duration `json:"duration"`  // 执行耗时
+	Status    Status        `json:"status"`    // 执行状态
+	CatchUp   bool          `json:"catchUp"`   // 是否为补偿执行
+	Reason    string        `json:"reason"`    // 失败或跳过的原因
+}
+
+// JobInfo 任务信息
+type JobInfo struct {
+	Name      string    `json:"name"`      // 任务名称
+	Spec      string    `json:"spec"`      // cron表达式
+	Singleton bool      `json:"singleton"` // 是否在集群中单实例执行
+	Prev      time.Time `json:"prev"`      // 上一次计划执行时间
+	Next      time.Time `json:"next"`      // 下一次计划执行时间
+}
+
+type JobOption func(o *jobOptions)
+
+type jobOptions struct {
+	singleton  bool          // 是否在集群中单实例执行
+	lease      time.Duration // 执行租约时长；应大于各实例间的时钟偏差
+	misfire    Misfire       // 错过执行的处理策略
+	maxCatchUp int           // 单次最多补偿执行次数；仅对 MisfireRunAll 策略生效
+	timeout    time.Duration // 执行超时时间；为0时不限制
+}
+
+func defaultJobOptions() *jobOptions {
+	return &jobOptions{
+		lease:      defaultLease,
+		misfire:    MisfireRunOnce,
+		maxCatchUp: defaultMaxCatchUp,
+	}
+}
+
+// WithSingleton 设置任务在集群中单实例执行；同一计划时间仅由最先获取执行租约的实例执行
+func WithSingleton() JobOption {
+	return func(o *jobOptions) { o.singleton = true }
+}
+
+// WithLease 设置执行租约时长
+func WithLease(lease time.Duration) JobOption {
+	return func(o *jobOptions) { o.lease = lease }
+}
+
+// WithMisfire 设置错过执行的处理策略
+func WithMisfire(misfire Misfire) JobOption {
+	return func(o *jobOptions) { o.misfire = misfire }
+}
+
+// WithMaxCatchUp 设置单次最多补偿执行次数
+func WithMaxCatchUp(max int) JobOption {
+	return func(o *jobOptions) { o.maxCatchUp = max }
+}
+
+// WithJobTimeout 设置执行超时时间
+func WithJobTimeout(timeout time.Duration) JobOption {
+	return func(o *jobOptions) { o.timeout = timeout }
+}
+
+type job struct {
+	name     string
+	spec     string
+	schedule Schedule
+	handler  JobHandler
+	opts     *jobOptions
+	prev     time.Time  // 上一次计划执行时间
+	next     time.Time  // 下一次计划执行时间
+	running  sync.Mutex // 执行锁；同一任务不会并发执行
+	rw       sync.RWMutex
+	history  []*Record // 执行记录
+}
+
+// 添加执行记录
+func (j *job) record(r *Record, size int) {
+	j.rw.Lock()
+	defer j.rw.Unlock()
+
+	j.history = append(j.history, r)
+
+	if size > 0 && len(j.history) > size {
+		j.history = append(j.history[:0], j.history[len(j.history)-size:]...)
+	}
+}
+
+// 获取执行记录
+func (j *job) records() []*Record {
+	j.rw.RLock()
+	defer j.rw.RUnlock()
+
+	return append([]*Record(nil), j.history...)
+}
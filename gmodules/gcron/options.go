@@ -0,0 +1,83 @@
+package gcron
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/getc"
+	"github.com/goodluck0107/gcore/glog"
+	"time"
+)
+
+const (
+	defaultName             = "cron"      // 默认组件名称
+	defaultTimezone         = "Local"     // 默认时区
+	defaultHistorySize      = 100         // 默认每个任务保留的执行记录数
+	defaultMisfireThreshold = time.Second // 默认错过执行的判定阈值
+)
+
+const (
+	defaultNameKey             = "etc.cron.name"
+	defaultTimezoneKey         = "etc.cron.timezone"
+	defaultHistorySizeKey      = "etc.cron.historySize"
+	defaultMisfireThresholdKey = "etc.cron.misfireThreshold"
+)
+
+type Option func(o *options)
+
+type options struct {
+	ctx         context.Context // 上下文
+	name        string          // 组件名称
+	location    *time.Location  // 默认时区；表达式未指定时区时使用
+	store       Store           // 调度状态存储
+	historySize int             // 每个任务保留的执行记录数
+	threshold   time.Duration   // 错过执行的判定阈值；计划时间早于当前时间超过该阈值时视为错过执行
+}
+
+func defaultOptions() *options {
+	opts := &options{
+		ctx:         context.Background(),
+		name:        getc.Get(defaultNameKey, defaultName).String(),
+		location:    time.Local,
+		historySize: getc.Get(defaultHistorySizeKey, defaultHistorySize).Int(),
+		threshold:   getc.Get(defaultMisfireThresholdKey, defaultMisfireThreshold).Duration(),
+	}
+
+	if timezone := getc.Get(defaultTimezoneKey, defaultTimezone).String(); timezone != "" {
+		if loc, err := time.LoadLocation(timezone); err != nil {
+			glog.Warnf("load cron timezone %s failed: %v", timezone, err)
+		} else {
+			opts.location = loc
+		}
+	}
+
+	return opts
+}
+
+// WithContext 设置上下文
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithName 设置组件名称
+func WithName(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// WithLocation 设置默认时区
+func WithLocation(location *time.Location) Option {
+	return func(o *options) { o.location = location }
+}
+
+// WithStore 设置调度状态存储；默认为仅在当前进程内生效的内存存储
+func WithStore(store Store) Option {
+	return func(o *options) { o.store = store }
+}
+
+// WithHistorySize 设置每个任务保留的执行记录数
+func WithHistorySize(size int) Option {
+	return func(o *options) { o.historySize = size }
+}
+
+// WithMisfireThreshold 设置错过执行的判定阈值
+func WithMisfireThreshold(threshold time.Duration) Option {
+	return func(o *options) { o.threshold = threshold }
+}
@@ -0,0 +1,71 @@
+package gcron
+
+import "sort"
+
+type Proxy struct {
+	scheduler *Scheduler
+}
+
+func newProxy(s *Scheduler) *Proxy {
+	return &Proxy{scheduler: s}
+}
+
+// AddJob 添加任务；spec 为cron表达式，参见 Parse
+func (p *Proxy) AddJob(name, spec string, handler JobHandler, opts ...JobOption) error {
+	return p.scheduler.addJob(name, spec, handler, opts...)
+}
+
+// RemoveJob 移除任务；执行中的任务不受影响
+func (p *Proxy) RemoveJob(name string) bool {
+	return p.scheduler.removeJob(name)
+}
+
+// Job 获取任务信息
+func (p *Proxy) Job(name string) (*JobInfo, bool) {
+	j, ok := p.scheduler.loadJob(name)
+	if !ok {
+		return nil, false
+	}
+
+	p.scheduler.mu.Lock()
+	defer p.scheduler.mu.Unlock()
+
+	return &JobInfo{
+		Name:      j.name,
+		Spec:      j.spec,
+		Singleton: j.opts.singleton,
+		Prev:      j.prev,
+		Next:      j.next,
+	}, true
+}
+
+// Jobs 获取所有任务信息；按任务名称排序
+func (p *Proxy) Jobs() []*JobInfo {
+	p.scheduler.mu.Lock()
+	names := make([]string, 0, len(p.scheduler.jobs))
+	for name := range p.scheduler.jobs {
+		names = append(names, name)
+	}
+	p.scheduler.mu.Unlock()
+
+	sort.Strings(names)
+
+	jobs := make([]*JobInfo, 0, len(names))
+	for _, name := range names {
+		if job, ok := p.Job(name); ok {
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs
+}
+
+// History 获取任务在当前实例上的执行记录；按计划执行顺序排列
+func (p *Proxy) History(name string) ([]*Record, error) {
+	j, ok := p.scheduler.loadJob(name)
+	if !ok {
+		return nil, ErrNotFoundJob
+	}
+
+	return j.records(), nil
+}
@@ -0,0 +1,350 @@
+package gcron
+
+import (
+	"fmt"
+	"github.com/goodluck0107/gcore/gerrors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 字段值为 * 或 ? 时的标记位；用于日期与星期的组合匹配
+const starBit = 1 << 63
+
+type bounds struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	seconds = bounds{0, 59, nil}
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	doms    = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dows = bounds{0, 7, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// Schedule 执行计划
+type Schedule interface {
+	// Next 获取晚于指定时间的下一次执行时间；不存在时返回零值
+	Next(t time.Time) time.Time
+}
+
+// Parse 解析cron表达式
+// 支持5段（分 时 日 月 周）与6段（秒 分 时 日 月 周）表达式，以及 @daily、@weekly、@every 1h 等描述符
+// 表达式可通过 CRON_TZ=Asia/Shanghai 前缀指定时区，未指定时使用 loc 参数，均未指定时使用本地时区
+func Parse(spec string, loc ...*time.Location) (Schedule, error) {
+	location := time.Local
+	if len(loc) > 0 && loc[0] != nil {
+		location = loc[0]
+	}
+
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		i := strings.Index(spec, " ")
+		if i < 0 {
+			return nil, invalidSpec(spec, "missing fields")
+		}
+
+		tz := spec[strings.Index(spec, "=")+1 : i]
+
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, gerrors.NewError(fmt.Sprintf("invalid time zone %s", tz), err)
+		}
+
+		location, spec = l, strings.TrimSpace(spec[i:])
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || d < time.Second {
+			return nil, invalidSpec(spec, "invalid duration")
+		}
+
+		return &everySchedule{interval: d.Truncate(time.Second)}, nil
+	}
+
+	if descriptor, ok := descriptors[spec]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, invalidSpec(spec, fmt.Sprintf("expected 5 or 6 fields, found %d", len(fields)))
+	}
+
+	s := &specSchedule{loc: location}
+
+	var err error
+
+	for i, f := range []struct {
+		field  *uint64
+		bounds bounds
+	}{
+		{&s.second, seconds},
+		{&s.minute, minutes},
+		{&s.hour, hours},
+		{&s.dom, doms},
+		{&s.month, months},
+		{&s.dow, dows},
+	} {
+		if *f.field, err = parseField(fields[i], f.bounds); err != nil {
+			return nil, invalidSpec(spec, err.Error())
+		}
+	}
+
+	// 星期日可使用0或7表示
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	return s, nil
+}
+
+// 解析字段
+func parseField(field string, r bounds) (uint64, error) {
+	var bits uint64
+
+	for _, expr := range strings.Split(field, ",") {
+		bit, err := parseRange(expr, r)
+		if err != nil {
+			return 0, err
+		}
+
+		bits |= bit
+	}
+
+	return bits, nil
+}
+
+// 解析范围；支持 *、?、a、a-b、*/n、a/n、a-b/n
+func parseRange(expr string, r bounds) (uint64, error) {
+	var (
+		start, end, step uint = 0, 0, 1
+		extra            uint64
+		err              error
+	)
+
+	rangeAndStep := strings.Split(expr, "/")
+	lowAndHigh := strings.Split(rangeAndStep[0], "-")
+
+	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+		if len(lowAndHigh) > 1 {
+			return 0, fmt.Errorf("invalid range %s", expr)
+		}
+
+		start, end, extra = r.min, r.max, starBit
+	} else {
+		if start, err = parseValue(lowAndHigh[0], r); err != nil {
+			return 0, err
+		}
+
+		switch len(lowAndHigh) {
+		case 1:
+			end = start
+		case 2:
+			if end, err = parseValue(lowAndHigh[1], r); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("invalid range %s", expr)
+		}
+	}
+
+	switch len(rangeAndStep) {
+	case 1:
+	case 2:
+		if step, err = parseValue(rangeAndStep[1], bounds{1, math.MaxUint8, nil}); err != nil {
+			return 0, err
+		}
+
+		// 单个值带步长时表示从该值开始直到最大值
+		if len(lowAndHigh) == 1 && extra == 0 {
+			end = r.max
+		}
+
+		if step > 1 {
+			extra = 0
+		}
+	default:
+		return 0, fmt.Errorf("invalid step %s", expr)
+	}
+
+	if start < r.min || end > r.max || start > end {
+		return 0, fmt.Errorf("value of %s out of range [%d, %d]", expr, r.min, r.max)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+
+	return bits | extra, nil
+}
+
+// 解析值；支持数字与名称
+func parseValue(expr string, r bounds) (uint, error) {
+	if r.names != nil {
+		if v, ok := r.names[strings.ToLower(expr)]; ok {
+			return v, nil
+		}
+	}
+
+	v, err := strconv.ParseUint(expr, 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s", expr)
+	}
+
+	if uint(v) < r.min || uint(v) > r.max {
+		return 0, fmt.Errorf("value %s out of range [%d, %d]", expr, r.min, r.max)
+	}
+
+	return uint(v), nil
+}
+
+// 无效表达式错误
+func invalidSpec(spec, reason string) error {
+	return gerrors.NewError(fmt.Sprintf("spec %q: %s", spec, reason), ErrInvalidSpec)
+}
+
+type specSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	loc                                   *time.Location
+}
+
+// Next 获取晚于指定时间的下一次执行时间
+func (s *specSchedule) Next(t time.Time) time.Time {
+	origin := t.Location()
+
+	t = t.In(s.loc)
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+
+	added := false
+	limit := t.Year() + 5
+
+WRAP:
+	if t.Year() > limit {
+		return time.Time{}
+	}
+
+	for 1<<uint(t.Month())&s.month == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, s.loc)
+		}
+
+		t = t.AddDate(0, 1, 0)
+
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.matchDay(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.loc)
+		}
+
+		t = t.AddDate(0, 0, 1)
+
+		// 夏令时切换可能导致零点不存在，修正到当天零点附近
+		if t.Hour() != 0 {
+			if t.Hour() > 12 {
+				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+			} else {
+				t = t.Add(time.Duration(-t.Hour()) * time.Hour)
+			}
+		}
+
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Hour())&s.hour == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, s.loc)
+		}
+
+		t = t.Add(time.Hour)
+
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Minute())&s.minute == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+
+		t = t.Add(time.Minute)
+
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Second())&s.second == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+
+		t = t.Add(time.Second)
+
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t.In(origin)
+}
+
+// 匹配日期；日期与星期均有限制时满足其一即可
+func (s *specSchedule) matchDay(t time.Time) bool {
+	domMatch := 1<<uint(t.Day())&s.dom > 0
+	dowMatch := 1<<uint(t.Weekday())&s.dow > 0
+
+	if s.dom&starBit > 0 || s.dow&starBit > 0 {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next 获取晚于指定时间的下一次执行时间
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval - time.Duration(t.Nanosecond()))
+}
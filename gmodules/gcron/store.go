@@ -0,0 +1,132 @@
+package gcron
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gkvdb"
+	"sync"
+	"time"
+)
+
+// Store 调度状态存储；多个实例共享同一存储时，可实现任务的集群单实例执行与跨进程的错过补偿
+type Store interface {
+	// Acquire 获取执行租约；租约有效期内相同键的租约无法被再次获取
+	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Last 获取任务最后一次执行的计划时间；从未执行时返回零值
+	Last(ctx context.Context, name string) (time.Time, error)
+	// Save 保存任务最后一次执行的计划时间
+	Save(ctx context.Context, name string, scheduled time.Time) error
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	leases map[string]time.Time
+	lasts  map[string]time.Time
+}
+
+// NewMemoryStore 新建内存存储；仅在当前进程内生效
+func NewMemoryStore() Store {
+	return &memoryStore{
+		leases: make(map[string]time.Time),
+		lasts:  make(map[string]time.Time),
+	}
+}
+
+// Acquire 获取执行租约
+func (s *memoryStore) Acquire(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for k, expire := range s.leases {
+		if !expire.After(now) {
+			delete(s.leases, k)
+		}
+	}
+
+	if _, ok := s.leases[key]; ok {
+		return false, nil
+	}
+
+	s.leases[key] = now.Add(ttl)
+
+	return true, nil
+}
+
+// Last 获取任务最后一次执行的计划时间
+func (s *memoryStore) Last(_ context.Context, name string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lasts[name], nil
+}
+
+// Save 保存任务最后一次执行的计划时间
+func (s *memoryStore) Save(_ context.Context, name string, scheduled time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if scheduled.After(s.lasts[name]) {
+		s.lasts[name] = scheduled
+	}
+
+	return nil
+}
+
+type kvdbStore struct {
+	db gkvdb.KvDB
+}
+
+// NewKvDBStore 新建基于gkvdb的存储；租约通过 SetNX 实现
+func NewKvDBStore(db gkvdb.KvDB) Store {
+	return &kvdbStore{db: db}
+}
+
+// Acquire 获取执行租约
+func (s *kvdbStore) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.db.SetNX(ctx, "cron:lease:"+key, time.Now().UnixNano(), ttl)
+}
+
+// Last 获取任务最后一次执行的计划时间
+func (s *kvdbStore) Last(ctx context.Context, name string) (time.Time, error) {
+	nsec, err := s.db.Get(ctx, "cron:last:"+name).Int64()
+	if err != nil {
+		if gerrors.Is(err, gerrors.ErrNil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, nsec), nil
+}
+
+// Save 保存任务最后一次执行的计划时间；仅在计划时间晚于已保存的时间时更新
+func (s *kvdbStore) Save(ctx context.Context, name string, scheduled time.Time) error {
+	key, nsec := "cron:last:"+name, scheduled.UnixNano()
+
+	for {
+		cur, err := s.db.Get(ctx, key).Int64()
+		if err != nil {
+			if !gerrors.Is(err, gerrors.ErrNil) {
+				return err
+			}
+
+			ok, err := s.db.SetNX(ctx, key, nsec)
+			if err != nil || ok {
+				return err
+			}
+
+			continue
+		}
+
+		if cur >= nsec {
+			return nil
+		}
+
+		ok, err := s.db.CompareAndSwap(ctx, key, cur, nsec)
+		if err != nil || ok {
+			return err
+		}
+	}
+}
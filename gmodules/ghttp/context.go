@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"github.com/gofiber/fiber/v3"
 	"github.com/goodluck0107/gcore/gcodes"
+	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/gprotocol/handler"
+	"github.com/goodluck0107/gcore/gutils/gvalidate"
 	"io"
 	"net/http"
 	"net/url"
@@ -54,7 +56,7 @@ func (c *context) Failure(rst any) error {
 	case error:
 		code, _ := gcodes.Convert(v)
 
-		return c.JSON(&Resp{Code: code.Code(), Message: code.Message()})
+		return c.JSON(&Resp{Code: code.Code(), Message: code.Message(), Data: details(v)})
 	case *gcodes.Code:
 		return c.JSON(&Resp{Code: v.Code(), Message: v.Message()})
 	default:
@@ -68,7 +70,7 @@ func (c *context) StatusFailure(status int, rst any) error {
 	case error:
 		code, _ := gcodes.Convert(v)
 
-		return c.Status(status).JSON(&Resp{Code: code.Code(), Message: code.Message()})
+		return c.Status(status).JSON(&Resp{Code: code.Code(), Message: code.Message(), Data: details(v)})
 	case *gcodes.Code:
 		return c.Status(status).JSON(&Resp{Code: v.Code(), Message: v.Message()})
 	default:
@@ -76,6 +78,16 @@ func (c *context) StatusFailure(status int, rst any) error {
 	}
 }
 
+// 获取错误详情；参数校验失败时为各字段的校验错误
+func details(err error) any {
+	var errs gvalidate.FieldErrors
+	if gerrors.As(err, &errs) {
+		return errs
+	}
+
+	return nil
+}
+
 // Success 成功响应
 func (c *context) Success(data ...any) error {
 	if len(data) > 0 {
@@ -6,6 +6,7 @@ import (
 	"github.com/goodluck0107/gcore/gcodes"
 	"github.com/goodluck0107/gcore/glog"
 	ghandler "github.com/goodluck0107/gcore/gprotocol/handler"
+	"github.com/goodluck0107/gcore/gutils/gvalidate"
 )
 
 const (
@@ -31,6 +32,7 @@ func handlersMiddleware(md ghandler.Metadata) fiber.Handler {
 func convertHandle(handle ghandler.Handler) fiber.Handler {
 	return func(ctx fiber.Ctx) error {
 		ctxWrapper := &context{Ctx: ctx}
+		var invalid error
 		dec := func(req interface{}) error {
 			var err error
 			switch ctx.Method() {
@@ -41,7 +43,11 @@ func convertHandle(handle ghandler.Handler) fiber.Handler {
 			default:
 				err = fmt.Errorf("not support method: %s", ctx.Method())
 			}
-			return err
+			if err != nil {
+				return err
+			}
+			invalid = gvalidate.Request(req)
+			return invalid
 		}
 		ret, code := handle(ctxWrapper.Context(), dec)
 		if code != gcodes.OK {
@@ -49,6 +55,9 @@ func convertHandle(handle ghandler.Handler) fiber.Handler {
 				glog.Warnf("[redirect] [%s] %s -> %s", ctx.IP(), ctx.OriginalURL(), redirect)
 				return ctxWrapper.Redirect().Status(fiber.StatusTemporaryRedirect).To(redirect)
 			}
+			if invalid != nil {
+				return ctxWrapper.Failure(invalid)
+			}
 			return ctxWrapper.Failure(code)
 		} else {
 			return ctxWrapper.Success(ret)
@@ -1,5 +1,7 @@
 package gmodules
 
+import "context"
+
 type Module interface {
 	// Name 组件名称
 	Name() string
@@ -13,6 +15,36 @@ type Module interface {
 	Destroy()
 }
 
+// Dependent 声明依赖的组件；引擎按依赖关系排序启动，并按相反顺序关闭
+type Dependent interface {
+	// Depends 依赖的组件名称
+	Depends() []string
+}
+
+// Initializer 可返回错误的初始化组件；实现后引擎将调用 TryInit 代替 Init
+type Initializer interface {
+	// TryInit 初始化组件
+	TryInit(ctx context.Context) error
+}
+
+// Starter 可返回错误的启动组件；实现后引擎将调用 TryStart 代替 Start
+type Starter interface {
+	// TryStart 启动组件
+	TryStart(ctx context.Context) error
+}
+
+// Closer 可返回错误的关闭组件；实现后引擎将调用 TryClose 代替 Close
+type Closer interface {
+	// TryClose 关闭组件
+	TryClose(ctx context.Context) error
+}
+
+// Destroyer 可返回错误的销毁组件；实现后引擎将调用 TryDestroy 代替 Destroy
+type Destroyer interface {
+	// TryDestroy 销毁组件
+	TryDestroy(ctx context.Context) error
+}
+
 type Base struct {
 }
 
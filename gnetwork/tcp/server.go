@@ -100,6 +100,11 @@ func (s *server) OnReceive(handler gnetwork.ReceiveHandler) {
 
 // 初始化TCP服务器
 func (s *server) init() error {
+	if s.opts.listener != nil {
+		s.listener = s.opts.listener
+		return nil
+	}
+
 	addr, err := net.ResolveTCPAddr("tcp", s.opts.addr)
 	if err != nil {
 		return err
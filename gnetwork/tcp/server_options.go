@@ -2,6 +2,7 @@ package tcp
 
 import (
 	"github.com/goodluck0107/gcore/getc"
+	"net"
 	"time"
 )
 
@@ -33,6 +34,7 @@ type serverOptions struct {
 	maxConnNum         int                // 最大连接数，默认5000
 	heartbeatInterval  time.Duration      // 心跳检测间隔时间，默认10s
 	heartbeatMechanism HeartbeatMechanism // 心跳机制，默认resp
+	listener           net.Listener       // 已就绪的监听器，设置后不再按监听地址创建监听器
 }
 
 func defaultServerOptions() *serverOptions {
@@ -49,6 +51,11 @@ func WithServerListenAddr(addr string) ServerOption {
 	return func(o *serverOptions) { o.addr = addr }
 }
 
+// WithServerListener 设置已就绪的监听器；监听地址随之设置为监听器的地址
+func WithServerListener(listener net.Listener) ServerOption {
+	return func(o *serverOptions) { o.listener, o.addr = listener, listener.Addr().String() }
+}
+
 // WithServerMaxConnNum 设置连接的最大连接数
 func WithServerMaxConnNum(maxConnNum int) ServerOption {
 	return func(o *serverOptions) { o.maxConnNum = maxConnNum }
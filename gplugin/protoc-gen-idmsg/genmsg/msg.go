@@ -34,6 +34,18 @@ type Protocol struct {
 	IdName     string
 }
 
+// Validation 消息字段校验规则
+type Validation struct {
+	Type  string       // 消息类型名
+	Rules []*FieldRule // 字段校验规则
+}
+
+// FieldRule 字段校验规则
+type FieldRule struct {
+	Field string // Go字段名
+	Rule  string // 校验规则，格式与 gvalidate 的 validate 标签一致
+}
+
 type GenData struct {
 	CmdTypeName string
 	MapProto    map[string]*Protocol
@@ -41,6 +53,7 @@ type GenData struct {
 	cmdType     *Enum
 	cmdIdValue  map[int32]*descriptor.EnumValueDescriptorProto
 	Services    []*descriptor.ServiceDescriptorProto
+	Validations []*Validation
 	Imports     map[string]*ImportInfo // key: 完整导入路径
 	importAlias map[string]int         // key: 别名，value: 计数
 
@@ -300,6 +313,8 @@ func (ts *GenData) FromReg(reg *Registry) {
 
 	ts.initGenFilePackage(fileDesc, enumType)
 
+	ts.AddValidations(reg)
+
 	for _, file := range files {
 		currentPkg := ts.getCurrentPackage(file.FileDescriptorProto)
 
@@ -432,6 +447,35 @@ func (ts *GenData) GetEnumValue(value int32) *descriptor.EnumValueDescriptorProt
 	return ret
 }
 
+// AddValidations 收集生成文件所在包中声明了 (extend.validate) 字段选项的消息
+func (ts *GenData) AddValidations(reg *Registry) {
+	for _, msg := range reg.msgs {
+		if msg.File.GoPkg.Path != ts.genFileGoPkg || msg.GetOptions().GetMapEntry() {
+			continue
+		}
+
+		var rules []*FieldRule
+		for _, field := range msg.Fields {
+			// oneof 字段生成在包装类型中，无法按字段名校验
+			if field.OneofIndex != nil && !field.GetProto3Optional() {
+				continue
+			}
+
+			if rule := extractValidateOption(field.FieldDescriptorProto); rule != "" {
+				rules = append(rules, &FieldRule{Field: goCamelCase(field.GetName()), Rule: rule})
+			}
+		}
+
+		if len(rules) > 0 {
+			ts.Validations = append(ts.Validations, &Validation{Type: msg.GoType(ts.genFileGoPkg), Rules: rules})
+		}
+	}
+
+	sort.Slice(ts.Validations, func(i, j int) bool {
+		return ts.Validations[i].Type < ts.Validations[j].Type
+	})
+}
+
 func (ts *GenData) AddProtocol(protocol *Protocol) {
 	ts.MapProto[protocol.Name] = protocol
 	ts.ProtoList = append(ts.ProtoList, protocol.Name)
@@ -446,6 +490,9 @@ package pb
 import (
 	wrappers "github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/goodluck0107/gmsgdef"
+{{- if .Validations}}
+	"github.com/goodluck0107/gcore/gutils/gvalidate"
+{{- end}}
 	"google.golang.org/protobuf/proto"
 {{- range .Imports}}
 	{{.Alias}} "{{.Path}}"
@@ -497,7 +544,19 @@ func (m *MsgDefStruct) GetIdMsg() map[int32]*gmsgdef.ReqItem {
 	return IdMsg
 }
 
-var MsgDef = &MsgDefStruct{}`
+var MsgDef = &MsgDefStruct{}
+{{- if .Validations}}
+
+func init() {
+{{- range .Validations}}
+	gvalidate.RegisterRules(&{{.Type}}{}, map[string]string{
+	{{- range .Rules}}
+		"{{.Field}}": {{printf "%q" .Rule}},
+	{{- end}}
+	})
+{{- end}}
+}
+{{- end}}`
 
 	str := ts.renderCode(tplStr)
 
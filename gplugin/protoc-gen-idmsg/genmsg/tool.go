@@ -47,6 +47,52 @@ func extractIdOptions(meth *descriptor.MethodDescriptorProto) (*pb.ProtoIdRule,
 	return opts, nil
 }
 
+// extractValidateOption 获取字段的 (extend.validate) 校验规则
+func extractValidateOption(field *descriptor.FieldDescriptorProto) string {
+	if field.Options == nil || !proto.HasExtension(field.Options, pb.E_Validate) {
+		return ""
+	}
+
+	rule, _ := proto.GetExtension(field.Options, pb.E_Validate).(string)
+
+	return rule
+}
+
+// goCamelCase 将 proto 字段名转换为 protoc-gen-go 生成的Go字段名
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
 type RouterInfo struct {
 	Method string
 	Route  string
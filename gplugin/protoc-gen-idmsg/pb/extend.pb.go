@@ -252,6 +252,14 @@ var file_extend_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "varint,1004,opt,name=service_name,enum=extend.ServiceName",
 		Filename:      "extend.proto",
 	},
+	{
+		ExtendedType:  (*descriptor.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         1005,
+		Name:          "extend.validate",
+		Tag:           "bytes,1005,opt,name=validate",
+		Filename:      "extend.proto",
+	},
 }
 
 // Extension fields to descriptor.MethodOptions.
@@ -274,6 +282,14 @@ var (
 	E_ServiceName = &file_extend_proto_extTypes[3]
 )
 
+// Extension fields to descriptor.FieldOptions.
+var (
+	// 字段校验规则，格式与 gvalidate 的 validate 标签一致，如 "required,max=16"
+	//
+	// optional string validate = 1005;
+	E_Validate = &file_extend_proto_extTypes[4]
+)
+
 var File_extend_proto protoreflect.FileDescriptor
 
 var file_extend_proto_rawDesc = []byte{
@@ -316,8 +332,12 @@ var file_extend_proto_rawDesc = []byte{
 	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xec,
 	0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x2e, 0x53,
 	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x0b, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x42, 0x0d, 0x5a, 0x0b, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x63, 0x6f, 0x6c, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x3a, 0x3a, 0x0a, 0x08, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0xed, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x42, 0x0d, 0x5a, 0x0b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2f,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -342,6 +362,7 @@ var file_extend_proto_goTypes = []interface{}{
 	(CMD)(0),                          // 4: CMD
 	(*descriptor.MethodOptions)(nil),  // 5: google.protobuf.MethodOptions
 	(*descriptor.ServiceOptions)(nil), // 6: google.protobuf.ServiceOptions
+	(*descriptor.FieldOptions)(nil),   // 7: google.protobuf.FieldOptions
 }
 var file_extend_proto_depIdxs = []int32{
 	3,  // 0: extend.ProtoId.rules:type_name -> extend.ProtoIdRule
@@ -350,14 +371,15 @@ var file_extend_proto_depIdxs = []int32{
 	5,  // 3: extend.auth_method:extendee -> google.protobuf.MethodOptions
 	6,  // 4: extend.auth_service:extendee -> google.protobuf.ServiceOptions
 	6,  // 5: extend.service_name:extendee -> google.protobuf.ServiceOptions
-	3,  // 6: extend.id:type_name -> extend.ProtoIdRule
-	0,  // 7: extend.auth_method:type_name -> extend.AuthType
-	0,  // 8: extend.auth_service:type_name -> extend.AuthType
-	1,  // 9: extend.service_name:type_name -> extend.ServiceName
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	6,  // [6:10] is the sub-list for extension type_name
-	2,  // [2:6] is the sub-list for extension extendee
+	7,  // 6: extend.validate:extendee -> google.protobuf.FieldOptions
+	3,  // 7: extend.id:type_name -> extend.ProtoIdRule
+	0,  // 8: extend.auth_method:type_name -> extend.AuthType
+	0,  // 9: extend.auth_service:type_name -> extend.AuthType
+	1,  // 10: extend.service_name:type_name -> extend.ServiceName
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	7,  // [7:11] is the sub-list for extension type_name
+	2,  // [2:7] is the sub-list for extension extendee
 	0,  // [0:2] is the sub-list for field type_name
 }
 
@@ -400,7 +422,7 @@ func file_extend_proto_init() {
 			RawDescriptor: file_extend_proto_rawDesc,
 			NumEnums:      2,
 			NumMessages:   2,
-			NumExtensions: 4,
+			NumExtensions: 5,
 			NumServices:   0,
 		},
 		GoTypes:           file_extend_proto_goTypes,
@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/granking"
+	"sync"
+	"time"
+)
+
+var _ granking.Ranking = &Ranking{}
+
+type Ranking struct {
+	rw     sync.RWMutex
+	boards map[string]*Board
+}
+
+func NewRanking() *Ranking {
+	return &Ranking{boards: make(map[string]*Board)}
+}
+
+// Board 获取排行榜
+func (r *Ranking) Board(name string) granking.Board {
+	return r.board(name)
+}
+
+func (r *Ranking) board(name string) *Board {
+	r.rw.RLock()
+	b, ok := r.boards[name]
+	r.rw.RUnlock()
+	if ok {
+		return b
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if b, ok = r.boards[name]; !ok {
+		b = &Board{ranking: r, name: name, list: newSkiplist(), members: make(map[string]*granking.Entry)}
+		r.boards[name] = b
+	}
+
+	return b
+}
+
+var _ granking.Board = &Board{}
+
+type Board struct {
+	ranking *Ranking
+	name    string
+	rw      sync.RWMutex
+	list    *skiplist
+	members map[string]*granking.Entry
+}
+
+// Name 获取排行榜名称
+func (b *Board) Name() string {
+	return b.name
+}
+
+// Update 更新成员分数
+func (b *Board) Update(_ context.Context, member string, score int64) error {
+	b.rw.Lock()
+	b.set(member, score, time.Now())
+	b.rw.Unlock()
+
+	return nil
+}
+
+// Incr 增减成员分数，返回最新分数
+func (b *Board) Incr(_ context.Context, member string, delta int64) (int64, error) {
+	b.rw.Lock()
+	defer b.rw.Unlock()
+
+	score := delta
+	if entry, ok := b.members[member]; ok {
+		score += entry.Score
+	}
+
+	b.set(member, score, time.Now())
+
+	return score, nil
+}
+
+// Remove 移除成员
+func (b *Board) Remove(_ context.Context, members ...string) error {
+	b.rw.Lock()
+	defer b.rw.Unlock()
+
+	for _, member := range members {
+		if entry, ok := b.members[member]; ok {
+			b.list.delete(entry)
+			delete(b.members, member)
+		}
+	}
+
+	return nil
+}
+
+// Member 获取成员排名及分数
+func (b *Board) Member(_ context.Context, member string) (*granking.Entry, error) {
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	entry, ok := b.members[member]
+	if !ok {
+		return nil, gerrors.ErrNotFoundMember
+	}
+
+	return clone(entry, b.list.countAhead(entry)+1), nil
+}
+
+// Range 获取排名区间[start,stop]内的成员
+func (b *Board) Range(_ context.Context, start, stop int64) ([]*granking.Entry, error) {
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	return b.doRange(start, stop), nil
+}
+
+// Around 获取成员前后各n名的成员
+func (b *Board) Around(_ context.Context, member string, n int64) ([]*granking.Entry, error) {
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	entry, ok := b.members[member]
+	if !ok {
+		return nil, gerrors.ErrNotFoundMember
+	}
+
+	rank := b.list.countAhead(entry) + 1
+
+	return b.doRange(rank-n, rank+n), nil
+}
+
+// Count 获取成员数量
+func (b *Board) Count(_ context.Context) (int64, error) {
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	return b.list.length, nil
+}
+
+// CountAhead 统计排在指定条目之前的成员数量
+func (b *Board) CountAhead(_ context.Context, entry *granking.Entry) (int64, error) {
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	return b.list.countAhead(entry), nil
+}
+
+// Window 按分数获取排在指定条目前后各至多n名的成员
+func (b *Board) Window(_ context.Context, entry *granking.Entry, n int64) ([]*granking.Entry, error) {
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	rank := b.list.countAhead(entry) + 1
+
+	return b.doRange(rank-n, rank+n), nil
+}
+
+// Snapshot 生成排行榜快照
+func (b *Board) Snapshot(_ context.Context, tag string) (granking.Board, error) {
+	snapshot := b.ranking.board(granking.SnapshotName(b.name, tag))
+	if snapshot == b {
+		return b, nil
+	}
+
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	snapshot.rw.Lock()
+	defer snapshot.rw.Unlock()
+
+	snapshot.list = newSkiplist()
+	snapshot.members = make(map[string]*granking.Entry, len(b.members))
+	for x := b.list.head.levels[0].forward; x != nil; x = x.levels[0].forward {
+		snapshot.list.insert(x.entry)
+		snapshot.members[x.entry.Member] = x.entry
+	}
+
+	return snapshot, nil
+}
+
+// Clear 清空排行榜
+func (b *Board) Clear(_ context.Context) error {
+	b.rw.Lock()
+	b.list = newSkiplist()
+	b.members = make(map[string]*granking.Entry)
+	b.rw.Unlock()
+
+	return nil
+}
+
+func (b *Board) set(member string, score int64, at time.Time) {
+	if entry, ok := b.members[member]; ok {
+		b.list.delete(entry)
+	}
+
+	entry := &granking.Entry{Member: member, Score: score, UpdatedAt: at}
+	b.list.insert(entry)
+	b.members[member] = entry
+}
+
+func (b *Board) doRange(start, stop int64) []*granking.Entry {
+	if start < 1 {
+		start = 1
+	}
+
+	if stop > b.list.length {
+		stop = b.list.length
+	}
+
+	if stop < start {
+		return nil
+	}
+
+	entries := make([]*granking.Entry, 0, stop-start+1)
+	for x, rank := b.list.byRank(start), start; x != nil && rank <= stop; x, rank = x.levels[0].forward, rank+1 {
+		entries = append(entries, clone(x.entry, rank))
+	}
+
+	return entries
+}
+
+func clone(entry *granking.Entry, rank int64) *granking.Entry {
+	return &granking.Entry{
+		Member:    entry.Member,
+		Score:     entry.Score,
+		Rank:      rank,
+		UpdatedAt: entry.UpdatedAt,
+	}
+}
@@ -0,0 +1,192 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/granking"
+	"github.com/goodluck0107/gcore/granking/memory"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBoard(t *testing.T) {
+	ctx := context.Background()
+	board := memory.NewRanking().Board("arena")
+
+	for i, score := range []int64{100, 300, 200, 300} {
+		if err := board.Update(ctx, fmt.Sprintf("p%d", i), score); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// p1与p3同分，p1先达到该分数
+	entries, err := board.Range(ctx, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"p1", "p3", "p2", "p0"}
+	if len(entries) != len(expected) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(expected))
+	}
+
+	for i, entry := range entries {
+		if entry.Member != expected[i] || entry.Rank != int64(i+1) {
+			t.Fatalf("entry %d: got %s@%d, want %s@%d", i, entry.Member, entry.Rank, expected[i], i+1)
+		}
+	}
+
+	score, err := board.Incr(ctx, "p0", 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := board.Member(ctx, "p0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if score != 350 || entry.Score != 350 || entry.Rank != 1 {
+		t.Fatalf("got score %d rank %d, want 350 rank 1", entry.Score, entry.Rank)
+	}
+
+	around, err := board.Around(ctx, "p3", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(around) != 3 || around[0].Member != "p1" || around[2].Member != "p2" {
+		t.Fatalf("unexpected around entries: %v", around)
+	}
+
+	snapshot, err := board.Snapshot(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = board.Remove(ctx, "p0", "p1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = board.Member(ctx, "p0"); err != gerrors.ErrNotFoundMember {
+		t.Fatalf("got %v, want ErrNotFoundMember", err)
+	}
+
+	if n, _ := board.Count(ctx); n != 2 {
+		t.Fatalf("got count %d, want 2", n)
+	}
+
+	if n, _ := snapshot.Count(ctx); n != 4 {
+		t.Fatalf("got snapshot count %d, want 4", n)
+	}
+
+	if snapshot.Name() != granking.SnapshotName("arena", "s1") {
+		t.Fatalf("unexpected snapshot name %s", snapshot.Name())
+	}
+}
+
+func TestSharded(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		ranking = memory.NewRanking()
+		single  = ranking.Board("single")
+		sharded = granking.NewSharded(ranking, "sharded", 8)
+	)
+
+	for i := 0; i < 2000; i++ {
+		member := fmt.Sprintf("m%d", i)
+		score := rand.Int63n(500)
+
+		if err := single.Update(ctx, member, score); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := sharded.Update(ctx, member, score); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := single.Range(ctx, 1, 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 两个排行榜的更新时间不同，以分片排行榜的顺序为准校验分数与排名
+	actual, err := sharded.Range(ctx, 1, 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actual) != len(expected) {
+		t.Fatalf("got %d entries, want %d", len(actual), len(expected))
+	}
+
+	if !sort.SliceIsSorted(actual, func(i, j int) bool { return granking.Less(actual[i], actual[j]) }) {
+		t.Fatal("sharded entries are not sorted")
+	}
+
+	for i := range actual {
+		if actual[i].Score != expected[i].Score {
+			t.Fatalf("rank %d: got score %d, want %d", i+1, actual[i].Score, expected[i].Score)
+		}
+	}
+
+	for _, i := range []int{0, 1, 99, 1000, 1999} {
+		entry, err := sharded.Member(ctx, actual[i].Member)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.Rank != actual[i].Rank {
+			t.Fatalf("member %s: got rank %d, want %d", entry.Member, entry.Rank, actual[i].Rank)
+		}
+	}
+
+	for _, i := range []int{0, 2, 1000, 1999} {
+		around, err := sharded.Around(ctx, actual[i].Member, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lo, hi := i-3, i+4
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(actual) {
+			hi = len(actual)
+		}
+
+		if len(around) != hi-lo {
+			t.Fatalf("member %s: got %d around entries, want %d", actual[i].Member, len(around), hi-lo)
+		}
+
+		for j, entry := range around {
+			if entry.Member != actual[lo+j].Member || entry.Rank != actual[lo+j].Rank {
+				t.Fatalf("member %s: got %s@%d, want %s@%d", actual[i].Member, entry.Member, entry.Rank, actual[lo+j].Member, actual[lo+j].Rank)
+			}
+		}
+	}
+
+	page, err := sharded.Range(ctx, 11, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page) != 10 || page[0].Member != actual[10].Member || page[0].Rank != 11 {
+		t.Fatalf("unexpected page: %v", page)
+	}
+
+	if _, err = sharded.Snapshot(ctx, "s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sharded.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, _ := sharded.OpenSnapshot("s1").Count(ctx); n != 2000 {
+		t.Fatalf("got snapshot count %d, want 2000", n)
+	}
+}
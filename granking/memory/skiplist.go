@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"github.com/goodluck0107/gcore/granking"
+	"math/rand"
+)
+
+const (
+	maxLevel    = 32
+	probability = 0.25
+)
+
+type level struct {
+	forward *node
+	span    int64
+}
+
+type node struct {
+	entry    *granking.Entry
+	backward *node
+	levels   []level
+}
+
+// skiplist 带跨度的跳表，支持按排名定位
+type skiplist struct {
+	head   *node
+	tail   *node
+	length int64
+	level  int
+}
+
+func newSkiplist() *skiplist {
+	return &skiplist{head: &node{levels: make([]level, maxLevel)}, level: 1}
+}
+
+func randomLevel() int {
+	lvl := 1
+	for lvl < maxLevel && rand.Float64() < probability {
+		lvl++
+	}
+
+	return lvl
+}
+
+// insert 插入条目
+func (sl *skiplist) insert(entry *granking.Entry) {
+	var (
+		update [maxLevel]*node
+		rank   [maxLevel]int64
+	)
+
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		if i < sl.level-1 {
+			rank[i] = rank[i+1]
+		}
+
+		for x.levels[i].forward != nil && granking.Less(x.levels[i].forward.entry, entry) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].forward
+		}
+
+		update[i] = x
+	}
+
+	lvl := randomLevel()
+	if lvl > sl.level {
+		for i := sl.level; i < lvl; i++ {
+			rank[i] = 0
+			update[i] = sl.head
+			update[i].levels[i].span = sl.length
+		}
+
+		sl.level = lvl
+	}
+
+	x = &node{entry: entry, levels: make([]level, lvl)}
+	for i := 0; i < lvl; i++ {
+		x.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = x
+		x.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+
+	for i := lvl; i < sl.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	if update[0] != sl.head {
+		x.backward = update[0]
+	}
+
+	if x.levels[0].forward != nil {
+		x.levels[0].forward.backward = x
+	} else {
+		sl.tail = x
+	}
+
+	sl.length++
+}
+
+// delete 删除条目
+func (sl *skiplist) delete(entry *granking.Entry) bool {
+	var update [maxLevel]*node
+
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && granking.Less(x.levels[i].forward.entry, entry) {
+			x = x.levels[i].forward
+		}
+
+		update[i] = x
+	}
+
+	x = x.levels[0].forward
+	if x == nil || x.entry != entry {
+		return false
+	}
+
+	for i := 0; i < sl.level; i++ {
+		if update[i].levels[i].forward == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].forward = x.levels[i].forward
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+
+	if x.levels[0].forward != nil {
+		x.levels[0].forward.backward = x.backward
+	} else {
+		sl.tail = x.backward
+	}
+
+	for sl.level > 1 && sl.head.levels[sl.level-1].forward == nil {
+		sl.level--
+	}
+
+	sl.length--
+
+	return true
+}
+
+// countAhead 统计排在条目之前的节点数量
+func (sl *skiplist) countAhead(entry *granking.Entry) int64 {
+	var n int64
+
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && granking.Less(x.levels[i].forward.entry, entry) {
+			n += x.levels[i].span
+			x = x.levels[i].forward
+		}
+	}
+
+	return n
+}
+
+// byRank 按排名获取节点，排名从1开始
+func (sl *skiplist) byRank(rank int64) *node {
+	var traversed int64
+
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && traversed+x.levels[i].span <= rank {
+			traversed += x.levels[i].span
+			x = x.levels[i].forward
+		}
+
+		if traversed == rank {
+			return x
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package granking
+
+import (
+	"context"
+	"time"
+)
+
+type Ranking interface {
+	// Board 获取排行榜
+	Board(name string) Board
+}
+
+type Board interface {
+	// Name 获取排行榜名称
+	Name() string
+	// Update 更新成员分数；更新时间用于同分排序，先达到该分数的成员排名靠前
+	Update(ctx context.Context, member string, score int64) error
+	// Incr 增减成员分数，返回最新分数
+	Incr(ctx context.Context, member string, delta int64) (int64, error)
+	// Remove 移除成员
+	Remove(ctx context.Context, members ...string) error
+	// Member 获取成员排名及分数；成员不存在时返回gerrors.ErrNotFoundMember
+	Member(ctx context.Context, member string) (*Entry, error)
+	// Range 获取排名区间[start,stop]内的成员，排名从1开始
+	Range(ctx context.Context, start, stop int64) ([]*Entry, error)
+	// Around 获取成员前后各n名的成员
+	Around(ctx context.Context, member string, n int64) ([]*Entry, error)
+	// Count 获取成员数量
+	Count(ctx context.Context) (int64, error)
+	// CountAhead 统计排在指定条目之前的成员数量
+	CountAhead(ctx context.Context, entry *Entry) (int64, error)
+	// Window 按分数获取排在指定条目前后各至多n名的成员，按排名顺序返回；指定条目可不在排行榜中，返回条目的排名不保证有效
+	Window(ctx context.Context, entry *Entry, n int64) ([]*Entry, error)
+	// Snapshot 生成排行榜快照，快照名称为SnapshotName(name, tag)
+	Snapshot(ctx context.Context, tag string) (Board, error)
+	// Clear 清空排行榜
+	Clear(ctx context.Context) error
+}
+
+// Entry 排行榜条目
+type Entry struct {
+	Member    string    `json:"member"`    // 成员
+	Score     int64     `json:"score"`     // 分数
+	Rank      int64     `json:"rank"`      // 排名，从1开始
+	UpdatedAt time.Time `json:"updatedAt"` // 分数更新时间
+}
+
+// Less 判断条目a是否排在条目b之前
+// 分数高者在前；同分时先更新者在前；仍相同时按成员名逆序，与redis的ZREVRANGE保持一致
+func Less(a, b *Entry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+
+	if !a.UpdatedAt.Equal(b.UpdatedAt) {
+		return a.UpdatedAt.Before(b.UpdatedAt)
+	}
+
+	return a.Member > b.Member
+}
+
+// SnapshotName 获取快照排行榜名称
+func SnapshotName(name, tag string) string {
+	return name + "@" + tag
+}
+
+// SnapshotHandler 生成定时快照处理器，快照标签为当前时间按layout格式化后的字符串
+// 可直接作为gcron的任务处理器用于赛季结算
+func SnapshotHandler(board Board, layout string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := board.Snapshot(ctx, time.Now().Format(layout))
+		return err
+	}
+}
@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/goodluck0107/gcore/getc"
+	"time"
+)
+
+const (
+	defaultAddr       = "127.0.0.1:6379"
+	defaultDB         = 0
+	defaultMaxRetries = 3
+	defaultPrefix     = "gcore"
+	defaultEpoch      = "2024-01-01T00:00:00Z"
+	defaultTimeBits   = 22
+	defaultTimeUnit   = "1m"
+)
+
+const (
+	defaultAddrsKey       = "etc.ranking.redis.addrs"
+	defaultDBKey          = "etc.ranking.redis.db"
+	defaultMaxRetriesKey  = "etc.ranking.redis.maxRetries"
+	defaultPrefixKey      = "etc.ranking.redis.prefix"
+	defaultUsernameKey    = "etc.ranking.redis.username"
+	defaultPasswordKey    = "etc.ranking.redis.password"
+	defaultEpochKey       = "etc.ranking.redis.epoch"
+	defaultTimeBitsKey    = "etc.ranking.redis.timeBits"
+	defaultTimeUnitKey    = "etc.ranking.redis.timeUnit"
+	defaultSnapshotTTLKey = "etc.ranking.redis.snapshotTTL"
+)
+
+type Option func(o *options)
+
+type options struct {
+	ctx context.Context
+
+	// 客户端连接地址
+	// 内建客户端配置，默认为[]string{"127.0.0.1:6379"}
+	addrs []string
+
+	// 数据库号
+	// 内建客户端配置，默认为0
+	db int
+
+	// 用户名
+	// 内建客户端配置，默认为空
+	username string
+
+	// 密码
+	// 内建客户端配置，默认为空
+	password string
+
+	// 最大重试次数
+	// 内建客户端配置，默认为3次
+	maxRetries int
+
+	// 客户端
+	// 外部客户端配置，存在外部客户端时，优先使用外部客户端，默认为nil
+	client redis.UniversalClient
+
+	// 前缀
+	// key前缀，默认为gcore
+	prefix string
+
+	// 起始时间
+	// 同分排序的时间起点，默认为2024-01-01T00:00:00Z
+	epoch time.Time
+
+	// 时间位数
+	// 有序集合分数中用于同分排序的时间位数，默认为22位，配合分钟级时间精度约可覆盖8年
+	// 分数的取值范围为[-2^(53-timeBits), 2^(53-timeBits))，默认即为int32的取值范围；设置为0时不按时间排序
+	// 超出时间范围的更新按最晚时间处理，同分成员退化为按名称排序
+	timeBits int
+
+	// 时间精度
+	// 同分排序的时间精度，精度越低可覆盖的时间范围越长，默认为1分钟
+	timeUnit time.Duration
+
+	// 快照过期时间
+	// 默认为0，永不过期
+	snapshotTTL time.Duration
+}
+
+func defaultOptions() *options {
+	epoch, err := time.Parse(time.RFC3339, getc.Get(defaultEpochKey, defaultEpoch).String())
+	if err != nil {
+		epoch, _ = time.Parse(time.RFC3339, defaultEpoch)
+	}
+
+	return &options{
+		ctx:         context.Background(),
+		addrs:       getc.Get(defaultAddrsKey, []string{defaultAddr}).Strings(),
+		db:          getc.Get(defaultDBKey, defaultDB).Int(),
+		maxRetries:  getc.Get(defaultMaxRetriesKey, defaultMaxRetries).Int(),
+		prefix:      getc.Get(defaultPrefixKey, defaultPrefix).String(),
+		username:    getc.Get(defaultUsernameKey).String(),
+		password:    getc.Get(defaultPasswordKey).String(),
+		epoch:       epoch,
+		timeBits:    getc.Get(defaultTimeBitsKey, defaultTimeBits).Int(),
+		timeUnit:    getc.Get(defaultTimeUnitKey, defaultTimeUnit).Duration(),
+		snapshotTTL: getc.Get(defaultSnapshotTTLKey).Duration(),
+	}
+}
+
+// WithContext 设置上下文
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithAddrs 设置连接地址
+func WithAddrs(addrs ...string) Option {
+	return func(o *options) { o.addrs = addrs }
+}
+
+// WithDB 设置数据库号
+func WithDB(db int) Option {
+	return func(o *options) { o.db = db }
+}
+
+// WithUsername 设置用户名
+func WithUsername(username string) Option {
+	return func(o *options) { o.username = username }
+}
+
+// WithPassword 设置密码
+func WithPassword(password string) Option {
+	return func(o *options) { o.password = password }
+}
+
+// WithMaxRetries 设置最大重试次数
+func WithMaxRetries(maxRetries int) Option {
+	return func(o *options) { o.maxRetries = maxRetries }
+}
+
+// WithClient 设置外部客户端
+func WithClient(client redis.UniversalClient) Option {
+	return func(o *options) { o.client = client }
+}
+
+// WithPrefix 设置前缀
+func WithPrefix(prefix string) Option {
+	return func(o *options) { o.prefix = prefix }
+}
+
+// WithEpoch 设置同分排序的时间起点
+func WithEpoch(epoch time.Time) Option {
+	return func(o *options) { o.epoch = epoch }
+}
+
+// WithTimeBits 设置同分排序的时间位数
+func WithTimeBits(timeBits int) Option {
+	return func(o *options) { o.timeBits = timeBits }
+}
+
+// WithTimeUnit 设置同分排序的时间精度
+func WithTimeUnit(timeUnit time.Duration) Option {
+	return func(o *options) { o.timeUnit = timeUnit }
+}
+
+// WithSnapshotTTL 设置快照过期时间
+func WithSnapshotTTL(ttl time.Duration) Option {
+	return func(o *options) { o.snapshotTTL = ttl }
+}
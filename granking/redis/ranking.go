@@ -0,0 +1,368 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/granking"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 以去除快照标签后的排行榜名称作为哈希标签，使排行榜与其快照位于同一个槽位
+const boardKey = "%s:ranking:{%s}%s" // sorted set
+
+// 有序集合分数采用double存储，整数部分最多53位有效位
+const precisionBits = 53
+
+// 增减分数脚本；分数越界时返回nil
+const incrScript = `
+local unit = tonumber(ARGV[3])
+local limit = tonumber(ARGV[5])
+local score = 0
+local current = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if current then
+	score = math.floor(tonumber(current) / unit)
+end
+score = score + tonumber(ARGV[2])
+if score >= limit or score < -limit then
+	return false
+end
+redis.call('ZADD', KEYS[1], score * unit + tonumber(ARGV[4]), ARGV[1])
+return score
+`
+
+// 统计排在指定条目之前的成员数量；同分成员按名称逆序排列
+const countAheadScript = `
+local n = redis.call('ZCOUNT', KEYS[1], '(' .. ARGV[1], '+inf')
+local ties = redis.call('ZRANGEBYSCORE', KEYS[1], ARGV[1], ARGV[1])
+for _, member in ipairs(ties) do
+	if member > ARGV[2] then
+		n = n + 1
+	end
+end
+return n
+`
+
+// 获取排在指定条目前后各至多n名的成员；返回起始位置及成员与分数列表
+const windowScript = `
+local n = tonumber(ARGV[3])
+local ahead = redis.call('ZCOUNT', KEYS[1], '(' .. ARGV[1], '+inf')
+local ties = redis.call('ZRANGEBYSCORE', KEYS[1], ARGV[1], ARGV[1])
+for _, member in ipairs(ties) do
+	if member > ARGV[2] then
+		ahead = ahead + 1
+	end
+end
+local start = math.max(ahead - n, 0)
+local list = redis.call('ZREVRANGE', KEYS[1], start, ahead + n, 'WITHSCORES')
+table.insert(list, 1, start)
+return list
+`
+
+var (
+	incr       = redis.NewScript(incrScript)
+	countAhead = redis.NewScript(countAheadScript)
+	window     = redis.NewScript(windowScript)
+)
+
+var _ granking.Ranking = &Ranking{}
+
+type Ranking struct {
+	opts  *options
+	unit  int64 // 2^timeBits
+	mask  int64 // 2^timeBits-1
+	limit int64 // 2^(53-timeBits)
+}
+
+func NewRanking(opts ...Option) *Ranking {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.prefix == "" {
+		o.prefix = defaultPrefix
+	}
+
+	if o.timeBits < 0 || o.timeBits >= precisionBits {
+		o.timeBits = defaultTimeBits
+	}
+
+	if o.timeUnit <= 0 {
+		o.timeUnit, _ = time.ParseDuration(defaultTimeUnit)
+	}
+
+	if o.client == nil {
+		o.client = redis.NewUniversalClient(&redis.UniversalOptions{
+			Addrs:      o.addrs,
+			DB:         o.db,
+			Username:   o.username,
+			Password:   o.password,
+			MaxRetries: o.maxRetries,
+		})
+	}
+
+	r := &Ranking{}
+	r.opts = o
+	r.unit = int64(1) << o.timeBits
+	r.mask = r.unit - 1
+	r.limit = int64(1) << (precisionBits - o.timeBits)
+
+	return r
+}
+
+// Board 获取排行榜
+func (r *Ranking) Board(name string) granking.Board {
+	tag, suffix := name, ""
+	if i := strings.Index(name, "@"); i >= 0 {
+		tag, suffix = name[:i], name[i:]
+	}
+
+	return &Board{ranking: r, name: name, key: fmt.Sprintf(boardKey, r.opts.prefix, tag, suffix)}
+}
+
+// 将分数与更新时间编码为有序集合分数；更新时间越早，低位越大
+func (r *Ranking) encode(score int64, at time.Time) (int64, error) {
+	if score >= r.limit || score < -r.limit {
+		return 0, gerrors.ErrScoreOverflow
+	}
+
+	return score*r.unit + r.remainder(at), nil
+}
+
+func (r *Ranking) remainder(at time.Time) int64 {
+	if r.mask == 0 {
+		return 0
+	}
+
+	ticks := int64(at.Sub(r.opts.epoch) / r.opts.timeUnit)
+	if ticks < 0 {
+		ticks = 0
+	} else if ticks > r.mask {
+		ticks = r.mask
+	}
+
+	return r.mask - ticks
+}
+
+// 将有序集合分数解码为分数与更新时间
+func (r *Ranking) decode(value float64) (int64, time.Time) {
+	c := int64(value)
+	score := c >> r.opts.timeBits
+
+	if r.mask == 0 {
+		return score, time.Time{}
+	}
+
+	return score, r.opts.epoch.Add(time.Duration(r.mask-(c&r.mask)) * r.opts.timeUnit)
+}
+
+var _ granking.Board = &Board{}
+
+type Board struct {
+	ranking *Ranking
+	name    string
+	key     string
+}
+
+// Name 获取排行榜名称
+func (b *Board) Name() string {
+	return b.name
+}
+
+// Update 更新成员分数
+func (b *Board) Update(ctx context.Context, member string, score int64) error {
+	c, err := b.ranking.encode(score, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return b.client().ZAdd(ctx, b.key, &redis.Z{Score: float64(c), Member: member}).Err()
+}
+
+// Incr 增减成员分数，返回最新分数
+func (b *Board) Incr(ctx context.Context, member string, delta int64) (int64, error) {
+	score, err := incr.Run(ctx, b.client(), []string{b.key},
+		member,
+		delta,
+		b.ranking.unit,
+		b.ranking.remainder(time.Now()),
+		b.ranking.limit,
+	).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, gerrors.ErrScoreOverflow
+		}
+		return 0, err
+	}
+
+	return score, nil
+}
+
+// Remove 移除成员
+func (b *Board) Remove(ctx context.Context, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, len(members))
+	for i, member := range members {
+		values[i] = member
+	}
+
+	return b.client().ZRem(ctx, b.key, values...).Err()
+}
+
+// Member 获取成员排名及分数
+func (b *Board) Member(ctx context.Context, member string) (*granking.Entry, error) {
+	var (
+		scoreCmd *redis.FloatCmd
+		rankCmd  *redis.IntCmd
+	)
+
+	_, err := b.client().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		scoreCmd = pipe.ZScore(ctx, b.key, member)
+		rankCmd = pipe.ZRevRank(ctx, b.key, member)
+		return nil
+	})
+	if err != nil {
+		if err == redis.Nil {
+			return nil, gerrors.ErrNotFoundMember
+		}
+		return nil, err
+	}
+
+	score, at := b.ranking.decode(scoreCmd.Val())
+
+	return &granking.Entry{Member: member, Score: score, Rank: rankCmd.Val() + 1, UpdatedAt: at}, nil
+}
+
+// Range 获取排名区间[start,stop]内的成员
+func (b *Board) Range(ctx context.Context, start, stop int64) ([]*granking.Entry, error) {
+	if start < 1 {
+		start = 1
+	}
+
+	if stop < start {
+		return nil, nil
+	}
+
+	list, err := b.client().ZRevRangeWithScores(ctx, b.key, start-1, stop-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*granking.Entry, 0, len(list))
+	for i, z := range list {
+		score, at := b.ranking.decode(z.Score)
+		entries = append(entries, &granking.Entry{
+			Member:    z.Member.(string),
+			Score:     score,
+			Rank:      start + int64(i),
+			UpdatedAt: at,
+		})
+	}
+
+	return entries, nil
+}
+
+// Around 获取成员前后各n名的成员
+func (b *Board) Around(ctx context.Context, member string, n int64) ([]*granking.Entry, error) {
+	rank, err := b.client().ZRevRank(ctx, b.key, member).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, gerrors.ErrNotFoundMember
+		}
+		return nil, err
+	}
+
+	return b.Range(ctx, rank+1-n, rank+1+n)
+}
+
+// Count 获取成员数量
+func (b *Board) Count(ctx context.Context) (int64, error) {
+	return b.client().ZCard(ctx, b.key).Result()
+}
+
+// CountAhead 统计排在指定条目之前的成员数量
+func (b *Board) CountAhead(ctx context.Context, entry *granking.Entry) (int64, error) {
+	c, err := b.ranking.encode(entry.Score, entry.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return countAhead.Run(ctx, b.client(), []string{b.key}, strconv.FormatInt(c, 10), entry.Member).Int64()
+}
+
+// Window 按分数获取排在指定条目前后各至多n名的成员
+func (b *Board) Window(ctx context.Context, entry *granking.Entry, n int64) ([]*granking.Entry, error) {
+	c, err := b.ranking.encode(entry.Score, entry.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 0 {
+		n = 0
+	}
+
+	res, err := window.Run(ctx, b.client(), []string{b.key}, strconv.FormatInt(c, 10), entry.Member, n).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	start, _ := res[0].(int64)
+	entries := make([]*granking.Entry, 0, (len(res)-1)/2)
+	for i := 1; i+1 < len(res); i += 2 {
+		member, _ := res[i].(string)
+		value, _ := res[i+1].(string)
+
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		score, at := b.ranking.decode(f)
+		entries = append(entries, &granking.Entry{
+			Member:    member,
+			Score:     score,
+			Rank:      start + int64(len(entries)) + 1,
+			UpdatedAt: at,
+		})
+	}
+
+	return entries, nil
+}
+
+// Snapshot 生成排行榜快照
+func (b *Board) Snapshot(ctx context.Context, tag string) (granking.Board, error) {
+	snapshot := b.ranking.Board(granking.SnapshotName(b.name, tag)).(*Board)
+
+	_, err := b.client().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZUnionStore(ctx, snapshot.key, &redis.ZStore{Keys: []string{b.key}})
+		if b.ranking.opts.snapshotTTL > 0 {
+			pipe.Expire(ctx, snapshot.key, b.ranking.opts.snapshotTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Clear 清空排行榜
+func (b *Board) Clear(ctx context.Context) error {
+	return b.client().Del(ctx, b.key).Err()
+}
+
+func (b *Board) client() redis.UniversalClient {
+	return b.ranking.opts.client
+}
@@ -0,0 +1,269 @@
+package granking
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+var _ Board = &Sharded{}
+
+// Sharded 分片排行榜
+// 成员按名称哈希分散到多个子排行榜中，适用于百万级以上成员的排行榜
+// 排名通过汇总各分片中排在该成员之前的数量计算；区间查询需要合并各分片的前stop名，适用于头部区间查询
+type Sharded struct {
+	ranking Ranking
+	name    string
+	shards  []Board
+}
+
+// NewSharded 创建分片排行榜，第i个分片的名称为name#i
+func NewSharded(ranking Ranking, name string, shards int) *Sharded {
+	if shards <= 0 {
+		shards = 1
+	}
+
+	s := &Sharded{ranking: ranking, name: name, shards: make([]Board, shards)}
+	for i := range s.shards {
+		s.shards[i] = ranking.Board(shardName(name, i))
+	}
+
+	return s
+}
+
+// Name 获取排行榜名称
+func (s *Sharded) Name() string {
+	return s.name
+}
+
+// Shards 获取所有分片
+func (s *Sharded) Shards() []Board {
+	return s.shards
+}
+
+// Update 更新成员分数
+func (s *Sharded) Update(ctx context.Context, member string, score int64) error {
+	return s.shard(member).Update(ctx, member, score)
+}
+
+// Incr 增减成员分数，返回最新分数
+func (s *Sharded) Incr(ctx context.Context, member string, delta int64) (int64, error) {
+	return s.shard(member).Incr(ctx, member, delta)
+}
+
+// Remove 移除成员
+func (s *Sharded) Remove(ctx context.Context, members ...string) error {
+	groups := make(map[int][]string)
+	for _, member := range members {
+		i := s.index(member)
+		groups[i] = append(groups[i], member)
+	}
+
+	for i, group := range groups {
+		if err := s.shards[i].Remove(ctx, group...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Member 获取成员排名及分数
+func (s *Sharded) Member(ctx context.Context, member string) (*Entry, error) {
+	entry, err := s.shard(member).Member(ctx, member)
+	if err != nil {
+		return nil, err
+	}
+
+	ahead, err := s.CountAhead(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Rank = ahead + 1
+
+	return entry, nil
+}
+
+// Range 获取排名区间[start,stop]内的成员
+func (s *Sharded) Range(ctx context.Context, start, stop int64) ([]*Entry, error) {
+	if start < 1 {
+		start = 1
+	}
+
+	if stop < start {
+		return nil, nil
+	}
+
+	entries := make([]*Entry, 0, stop)
+	for _, shard := range s.shards {
+		list, err := shard.Range(ctx, 1, stop)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, list...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return Less(entries[i], entries[j])
+	})
+
+	if int64(len(entries)) < start {
+		return nil, nil
+	}
+
+	if int64(len(entries)) > stop {
+		entries = entries[:stop]
+	}
+
+	entries = entries[start-1:]
+	for i, entry := range entries {
+		entry.Rank = start + int64(i)
+	}
+
+	return entries, nil
+}
+
+// Around 获取成员前后各n名的成员
+// 各分片按分数窗口获取该成员前后各n名后合并，无需从头部逐名合并
+func (s *Sharded) Around(ctx context.Context, member string, n int64) ([]*Entry, error) {
+	entry, err := s.Member(ctx, member)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.Window(ctx, entry, n)
+	if err != nil {
+		return nil, err
+	}
+
+	at := position(entries, entry)
+	for i, e := range entries {
+		e.Rank = entry.Rank + int64(i-at)
+	}
+
+	return entries, nil
+}
+
+// Window 按分数获取排在指定条目前后各至多n名的成员
+func (s *Sharded) Window(ctx context.Context, entry *Entry, n int64) ([]*Entry, error) {
+	if n < 0 {
+		n = 0
+	}
+
+	entries := make([]*Entry, 0, (2*n+1)*int64(len(s.shards)))
+	for _, shard := range s.shards {
+		list, err := shard.Window(ctx, entry, n)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, list...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return Less(entries[i], entries[j])
+	})
+
+	at := int64(position(entries, entry))
+	start, stop := at-n, at+n+1
+	if start < 0 {
+		start = 0
+	}
+
+	if stop > int64(len(entries)) {
+		stop = int64(len(entries))
+	}
+
+	return entries[start:stop], nil
+}
+
+// Count 获取成员数量
+func (s *Sharded) Count(ctx context.Context) (int64, error) {
+	var total int64
+	for _, shard := range s.shards {
+		n, err := shard.Count(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		total += n
+	}
+
+	return total, nil
+}
+
+// CountAhead 统计排在指定条目之前的成员数量
+func (s *Sharded) CountAhead(ctx context.Context, entry *Entry) (int64, error) {
+	var total int64
+	for _, shard := range s.shards {
+		n, err := shard.CountAhead(ctx, entry)
+		if err != nil {
+			return 0, err
+		}
+
+		total += n
+	}
+
+	return total, nil
+}
+
+// Snapshot 生成排行榜快照，各分片分别生成快照
+func (s *Sharded) Snapshot(ctx context.Context, tag string) (Board, error) {
+	shards := make([]Board, len(s.shards))
+	for i, shard := range s.shards {
+		snapshot, err := shard.Snapshot(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		shards[i] = snapshot
+	}
+
+	return &Sharded{ranking: s.ranking, name: SnapshotName(s.name, tag), shards: shards}, nil
+}
+
+// OpenSnapshot 打开已生成的分片排行榜快照
+func (s *Sharded) OpenSnapshot(tag string) *Sharded {
+	shards := make([]Board, len(s.shards))
+	for i := range s.shards {
+		shards[i] = s.ranking.Board(SnapshotName(shardName(s.name, i), tag))
+	}
+
+	return &Sharded{ranking: s.ranking, name: SnapshotName(s.name, tag), shards: shards}
+}
+
+// Clear 清空排行榜
+func (s *Sharded) Clear(ctx context.Context) error {
+	for _, shard := range s.shards {
+		if err := shard.Clear(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Sharded) shard(member string) Board {
+	return s.shards[s.index(member)]
+}
+
+func (s *Sharded) index(member string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(member))
+
+	return int(h.Sum32() % uint32(len(s.shards)))
+}
+
+// 获取有序条目中首个不排在指定条目之前的位置
+func position(entries []*Entry, entry *Entry) int {
+	return sort.Search(len(entries), func(i int) bool {
+		return !Less(entries[i], entry)
+	})
+}
+
+func shardName(name string, i int) string {
+	return name + "#" + strconv.Itoa(i)
+}
@@ -24,6 +24,7 @@ const (
 	metaFieldWeight   = "weight"
 	metaFieldServices = "services"
 	metaFieldEndpoint = "endpoint"
+	metaFieldMetadata = "metadata"
 )
 
 const (
@@ -83,6 +84,10 @@ func (r *registrar) register(ctx context.Context, ins *gregistry.ServiceInstance
 	registration.Meta[metaFieldWeight] = gconv.String(ins.Weight)
 	registration.Meta[metaFieldServices] = gconv.Json(ins.Services)
 
+	if len(ins.Metadata) > 0 {
+		registration.Meta[metaFieldMetadata] = gconv.Json(ins.Metadata)
+	}
+
 	for field, value := range marshalMetaRoutes(ins.Routes) {
 		registration.Meta[field] = value
 	}
@@ -0,0 +1,163 @@
+package gregistry
+
+import (
+	"context"
+)
+
+// Filter 服务实例过滤器，返回true时保留该实例
+type Filter func(ins *ServiceInstance) bool
+
+// MatchMetadata 匹配元数据，实例元数据中key的值为values之一时保留；未指定values时仅要求key存在
+func MatchMetadata(key string, values ...string) Filter {
+	return func(ins *ServiceInstance) bool {
+		val, ok := ins.Metadata[key]
+		if !ok {
+			return false
+		}
+
+		if len(values) == 0 {
+			return true
+		}
+
+		for _, v := range values {
+			if v == val {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchState 匹配实例状态
+func MatchState(states ...string) Filter {
+	return func(ins *ServiceInstance) bool {
+		for _, state := range states {
+			if state == ins.State {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchAlias 匹配实例别名
+func MatchAlias(aliases ...string) Filter {
+	return func(ins *ServiceInstance) bool {
+		for _, alias := range aliases {
+			if alias == ins.Alias {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchName 仅对指定服务名的实例应用过滤器，其他服务的实例全部保留
+// 例如仅对node服务做灰度过滤，而不影响gate、mesh服务的发现
+func MatchName(name string, filters ...Filter) Filter {
+	return func(ins *ServiceInstance) bool {
+		if ins.Name != name {
+			return true
+		}
+
+		return match(ins, filters)
+	}
+}
+
+// Not 对过滤器取反
+func Not(filter Filter) Filter {
+	return func(ins *ServiceInstance) bool {
+		return !filter(ins)
+	}
+}
+
+// FilterServices 过滤服务实例列表，保留满足全部过滤器的实例
+func FilterServices(services []*ServiceInstance, filters ...Filter) []*ServiceInstance {
+	if len(filters) == 0 {
+		return services
+	}
+
+	list := make([]*ServiceInstance, 0, len(services))
+	for _, ins := range services {
+		if match(ins, filters) {
+			list = append(list, ins)
+		}
+	}
+
+	return list
+}
+
+// 检测实例是否满足全部过滤器
+func match(ins *ServiceInstance, filters []Filter) bool {
+	for _, filter := range filters {
+		if !filter(ins) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewFilteredRegistry 创建带过滤器的服务注册发现组件
+// Services与Watch仅返回满足全部过滤器的实例，注册与解注册不受影响
+func NewFilteredRegistry(registry Registry, filters ...Filter) *FilteredRegistry {
+	return &FilteredRegistry{Registry: registry, filters: filters}
+}
+
+// FilteredRegistry 带过滤器的服务注册发现组件
+type FilteredRegistry struct {
+	Registry
+	filters []Filter
+}
+
+// Watch 监听相同服务名的服务实例变化
+func (r *FilteredRegistry) Watch(ctx context.Context, serviceName string) (Watcher, error) {
+	watcher, err := r.Registry.Watch(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filteredWatcher{Watcher: watcher, filters: r.filters}, nil
+}
+
+// Services 获取服务实例列表
+func (r *FilteredRegistry) Services(ctx context.Context, serviceName string) ([]*ServiceInstance, error) {
+	services, err := r.Registry.Services(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterServices(services, r.filters...), nil
+}
+
+// Close 关闭服务注册发现
+func (r *FilteredRegistry) Close() error {
+	return closeRegistry(r.Registry)
+}
+
+type filteredWatcher struct {
+	Watcher
+	filters []Filter
+}
+
+// Next 返回服务实例列表
+func (w *filteredWatcher) Next() ([]*ServiceInstance, error) {
+	services, err := w.Watcher.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterServices(services, w.filters...), nil
+}
+
+// 关闭服务注册发现组件；组件未实现Close时忽略
+func closeRegistry(registry Registry) error {
+	if closer, ok := registry.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,347 @@
+package gregistry
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/glog"
+	"github.com/goodluck0107/gcore/gwrap/endpoint"
+	"net"
+	"sync"
+	"time"
+)
+
+// NewHealthRegistry 创建带主动健康检查的服务注册发现组件
+// 定时探测已发现实例的连接端口，连续探测失败达到阈值的工作或繁忙实例将以挂起状态返回，
+// 调度器随之不再为其分配新的负载；端口恢复应答后实例恢复原有状态
+func NewHealthRegistry(registry Registry, opts ...HealthOption) *HealthRegistry {
+	o := defaultHealthOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r := &HealthRegistry{}
+	r.Registry = registry
+	r.opts = o
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.services = make(map[string]map[string]struct{})
+	r.targets = make(map[string]*target)
+	r.watchers = make(map[*healthWatcher]struct{})
+
+	go r.probe()
+
+	return r
+}
+
+// 探测目标
+type target struct {
+	failures int  // 连续失败次数
+	healthy  bool // 是否健康
+}
+
+// HealthRegistry 带主动健康检查的服务注册发现组件
+type HealthRegistry struct {
+	Registry
+	opts     *healthOptions
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mu       sync.RWMutex
+	services map[string]map[string]struct{} // 服务名对应的探测地址
+	targets  map[string]*target
+	watchers map[*healthWatcher]struct{}
+}
+
+// Watch 监听相同服务名的服务实例变化
+func (r *HealthRegistry) Watch(ctx context.Context, serviceName string) (Watcher, error) {
+	watcher, err := r.Registry.Watch(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &healthWatcher{}
+	w.ctx, w.cancel = context.WithCancel(r.ctx)
+	w.registry = r
+	w.serviceName = serviceName
+	w.watcher = watcher
+	w.chServices = make(chan watchResult)
+	w.chChanged = make(chan struct{}, 1)
+
+	r.mu.Lock()
+	r.watchers[w] = struct{}{}
+	r.mu.Unlock()
+
+	go w.pump()
+
+	return w, nil
+}
+
+// Services 获取服务实例列表
+func (r *HealthRegistry) Services(ctx context.Context, serviceName string) ([]*ServiceInstance, error) {
+	services, err := r.Registry.Services(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	r.track(serviceName, services)
+
+	return r.demote(services), nil
+}
+
+// Close 停止健康检查并关闭服务注册发现
+func (r *HealthRegistry) Close() error {
+	r.cancel()
+
+	return closeRegistry(r.Registry)
+}
+
+// 更新服务的探测地址
+func (r *HealthRegistry) track(serviceName string, services []*ServiceInstance) {
+	addrs := make(map[string]struct{}, len(services))
+	for _, ins := range services {
+		if addr := address(ins); addr != "" {
+			addrs[addr] = struct{}{}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.services[serviceName] = addrs
+
+	for addr := range addrs {
+		if _, ok := r.targets[addr]; !ok {
+			r.targets[addr] = &target{healthy: true}
+		}
+	}
+
+	for addr := range r.targets {
+		if !r.referenced(addr) {
+			delete(r.targets, addr)
+		}
+	}
+}
+
+// 检测地址是否仍被服务引用；调用方需持有写锁
+func (r *HealthRegistry) referenced(addr string) bool {
+	for _, addrs := range r.services {
+		if _, ok := addrs[addr]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 降级不健康的实例；返回的列表中被降级的实例为副本，不修改原实例
+func (r *HealthRegistry) demote(services []*ServiceInstance) []*ServiceInstance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*ServiceInstance, 0, len(services))
+	for _, ins := range services {
+		if t, ok := r.targets[address(ins)]; ok && !t.healthy {
+			switch ins.State {
+			case gcluster.Work.String(), gcluster.Busy.String():
+				c := *ins
+				c.State = gcluster.Hang.String()
+				ins = &c
+			}
+		}
+
+		list = append(list, ins)
+	}
+
+	return list
+}
+
+// 定时探测
+func (r *HealthRegistry) probe() {
+	ticker := time.NewTicker(r.opts.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.check()
+		}
+	}
+}
+
+// 并发探测全部目标，健康状态变化时通知监听器
+func (r *HealthRegistry) check() {
+	r.mu.RLock()
+	addrs := make([]string, 0, len(r.targets))
+	for addr := range r.targets {
+		addrs = append(addrs, addr)
+	}
+	r.mu.RUnlock()
+
+	if len(addrs) == 0 {
+		return
+	}
+
+	results := make([]bool, len(addrs))
+
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+			results[i] = r.dial(addr)
+		}(i, addr)
+	}
+	wg.Wait()
+
+	if r.ctx.Err() != nil {
+		return
+	}
+
+	changed := false
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, addr := range addrs {
+		t, ok := r.targets[addr]
+		if !ok {
+			continue
+		}
+
+		if results[i] {
+			t.failures = 0
+			if !t.healthy {
+				t.healthy, changed = true, true
+				glog.Infof("service instance recovered, address: %s", addr)
+			}
+			continue
+		}
+
+		t.failures++
+		if t.healthy && t.failures >= r.opts.threshold {
+			t.healthy, changed = false, true
+			glog.Warnf("service instance unhealthy, address: %s failures: %d", addr, t.failures)
+		}
+	}
+
+	if changed {
+		for w := range r.watchers {
+			w.notify()
+		}
+	}
+}
+
+// 探测地址是否应答
+func (r *HealthRegistry) dial(addr string) bool {
+	ctx, cancel := context.WithTimeout(r.ctx, r.opts.timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return false
+	}
+
+	_ = conn.Close()
+
+	return true
+}
+
+// 移除监听器
+func (r *HealthRegistry) removeWatcher(w *healthWatcher) {
+	r.mu.Lock()
+	delete(r.watchers, w)
+	r.mu.Unlock()
+}
+
+// 获取实例的探测地址
+func address(ins *ServiceInstance) string {
+	if ins.Endpoint == "" {
+		return ""
+	}
+
+	ep, err := endpoint.ParseEndpoint(ins.Endpoint)
+	if err != nil {
+		return ""
+	}
+
+	return ep.Address()
+}
+
+type watchResult struct {
+	services []*ServiceInstance
+	err      error
+}
+
+type healthWatcher struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	registry    *HealthRegistry
+	serviceName string
+	watcher     Watcher
+	chServices  chan watchResult
+	chChanged   chan struct{}
+	services    []*ServiceInstance // 最近一次的服务实例列表
+	ready       bool
+	err         error
+}
+
+// 转发底层监听器的服务实例列表
+func (w *healthWatcher) pump() {
+	for {
+		services, err := w.watcher.Next()
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case w.chServices <- watchResult{services: services, err: err}:
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// 通知健康状态变化；未及时消费的通知将被合并
+func (w *healthWatcher) notify() {
+	select {
+	case w.chChanged <- struct{}{}:
+	default:
+	}
+}
+
+// Next 返回服务实例列表
+func (w *healthWatcher) Next() ([]*ServiceInstance, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case rst := <-w.chServices:
+			if rst.err != nil {
+				w.err = rst.err
+				return nil, rst.err
+			}
+
+			w.services, w.ready = rst.services, true
+			w.registry.track(w.serviceName, rst.services)
+
+			return w.registry.demote(rst.services), nil
+		case <-w.chChanged:
+			if w.ready {
+				return w.registry.demote(w.services), nil
+			}
+		}
+	}
+}
+
+// Stop 停止监听
+func (w *healthWatcher) Stop() error {
+	w.cancel()
+	w.registry.removeWatcher(w)
+
+	return w.watcher.Stop()
+}
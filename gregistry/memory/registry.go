@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gregistry"
+	"sync"
+)
+
+const name = "memory"
+
+var _ gregistry.Registry = &Registry{}
+
+// Registry 进程内服务注册发现组件
+// 适用于单进程部署及集成测试，多个集群组件共享同一个实例即可相互发现
+type Registry struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	opts     *options
+	rw       sync.RWMutex
+	services map[string]map[string]*gregistry.ServiceInstance
+	watchers map[string]*watcherMgr
+}
+
+func NewRegistry(opts ...Option) *Registry {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r := &Registry{}
+	r.opts = o
+	r.ctx, r.cancel = context.WithCancel(o.ctx)
+	r.services = make(map[string]map[string]*gregistry.ServiceInstance)
+	r.watchers = make(map[string]*watcherMgr)
+
+	return r
+}
+
+// Name 获取服务注册发现组件名
+func (r *Registry) Name() string {
+	return name
+}
+
+// Register 注册服务实例
+func (r *Registry) Register(ctx context.Context, ins *gregistry.ServiceInstance) error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+
+	r.rw.Lock()
+	instances, ok := r.services[ins.Name]
+	if !ok {
+		instances = make(map[string]*gregistry.ServiceInstance)
+		r.services[ins.Name] = instances
+	}
+	instances[ins.ID] = clone(ins)
+	r.broadcast(ins.Name)
+	r.rw.Unlock()
+
+	return nil
+}
+
+// Deregister 解注册服务实例
+func (r *Registry) Deregister(ctx context.Context, ins *gregistry.ServiceInstance) error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+
+	r.rw.Lock()
+	instances, ok := r.services[ins.Name]
+	if !ok {
+		r.rw.Unlock()
+		return nil
+	}
+
+	if _, ok = instances[ins.ID]; !ok {
+		r.rw.Unlock()
+		return nil
+	}
+
+	delete(instances, ins.ID)
+	if len(instances) == 0 {
+		delete(r.services, ins.Name)
+	}
+	r.broadcast(ins.Name)
+	r.rw.Unlock()
+
+	return nil
+}
+
+// Watch 监听相同服务名的服务实例变化
+func (r *Registry) Watch(ctx context.Context, serviceName string) (gregistry.Watcher, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if wm, ok := r.watchers[serviceName]; ok {
+		if w, ok := wm.fork(); ok {
+			return w, nil
+		}
+	}
+
+	wm := newWatcherMgr(r, serviceName)
+	r.watchers[serviceName] = wm
+
+	w, ok := wm.fork()
+	if !ok {
+		return nil, r.ctx.Err()
+	}
+
+	return w, nil
+}
+
+// Services 获取服务实例列表
+func (r *Registry) Services(ctx context.Context, serviceName string) ([]*gregistry.ServiceInstance, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	return r.snapshot(serviceName), nil
+}
+
+// Close 关闭服务注册发现
+func (r *Registry) Close() error {
+	r.cancel()
+
+	return nil
+}
+
+// 获取服务实例快照
+func (r *Registry) snapshot(serviceName string) []*gregistry.ServiceInstance {
+	instances := r.services[serviceName]
+	services := make([]*gregistry.ServiceInstance, 0, len(instances))
+	for _, ins := range instances {
+		services = append(services, clone(ins))
+	}
+
+	return services
+}
+
+// 广播服务实例变更；调用方需持有写锁，以保证快照按变更顺序投递
+func (r *Registry) broadcast(serviceName string) {
+	if wm, ok := r.watchers[serviceName]; ok {
+		wm.broadcast(r.snapshot(serviceName))
+	}
+}
+
+// 移除监听管理器
+func (r *Registry) removeWatcherMgr(serviceName string, wm *watcherMgr) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if r.watchers[serviceName] == wm {
+		delete(r.watchers, serviceName)
+	}
+}
+
+// 深拷贝服务实例，避免调用方后续修改实例时产生数据竞争
+func clone(ins *gregistry.ServiceInstance) *gregistry.ServiceInstance {
+	c := *ins
+
+	if ins.Events != nil {
+		c.Events = append(make([]int, 0, len(ins.Events)), ins.Events...)
+	}
+
+	if ins.Routes != nil {
+		c.Routes = append(make([]gregistry.Route, 0, len(ins.Routes)), ins.Routes...)
+	}
+
+	if ins.Services != nil {
+		c.Services = append(make([]string, 0, len(ins.Services)), ins.Services...)
+	}
+
+	if ins.Metadata != nil {
+		c.Metadata = make(map[string]string, len(ins.Metadata))
+		for k, v := range ins.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+
+	return &c
+}
@@ -0,0 +1,226 @@
+package memory_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/gregistry"
+	"github.com/goodluck0107/gcore/gregistry/memory"
+	"net"
+	"testing"
+	"time"
+)
+
+const serviceName = "node"
+
+func TestRegistry_Watch(t *testing.T) {
+	var (
+		reg = memory.NewRegistry()
+		ctx = context.Background()
+	)
+	defer reg.Close()
+
+	ins := &gregistry.ServiceInstance{
+		ID:       "test-1",
+		Name:     serviceName,
+		Kind:     gcluster.Node.String(),
+		State:    gcluster.Work.String(),
+		Endpoint: "grpc://127.0.0.1:3553",
+	}
+
+	if err := reg.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher, err := reg.Watch(ctx, serviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	services, err := watcher.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].ID != ins.ID {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	ins.State = gcluster.Busy.String()
+	if err = reg.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	services, err = watcher.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].State != gcluster.Busy.String() {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	if err = reg.Deregister(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	services, err = watcher.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 0 {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+}
+
+func TestRegistry_Services(t *testing.T) {
+	var (
+		reg = memory.NewRegistry()
+		ctx = context.Background()
+	)
+	defer reg.Close()
+
+	ins := &gregistry.ServiceInstance{ID: "test-2", Name: serviceName, Services: []string{"greeter"}}
+
+	if err := reg.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	ins.Services[0] = "modified"
+
+	services, err := reg.Services(ctx, serviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].Services[0] != "greeter" {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+}
+
+func TestRegistry_Filter(t *testing.T) {
+	var (
+		reg = memory.NewRegistry()
+		ctx = context.Background()
+	)
+	defer reg.Close()
+
+	instances := []*gregistry.ServiceInstance{
+		{ID: "blue", Name: serviceName, Metadata: map[string]string{"version": "1.4.1", "zone": "a"}},
+		{ID: "green", Name: serviceName, Metadata: map[string]string{"version": "1.4.2", "zone": "a"}},
+		{ID: "canary", Name: serviceName, Metadata: map[string]string{"version": "1.4.2", "zone": "b"}},
+	}
+
+	for _, ins := range instances {
+		if err := reg.Register(ctx, ins); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filtered := gregistry.NewFilteredRegistry(reg,
+		gregistry.MatchMetadata("version", "1.4.2"),
+		gregistry.MatchMetadata("zone", "a"),
+	)
+
+	services, err := filtered.Services(ctx, serviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].ID != "green" || services[0].Metadata["version"] != "1.4.2" {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	watcher, err := filtered.Watch(ctx, serviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	if services, err = watcher.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].ID != "green" {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	if err = reg.Register(ctx, &gregistry.ServiceInstance{
+		ID:       "canary",
+		Name:     serviceName,
+		Metadata: map[string]string{"version": "1.4.2", "zone": "a"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if services, err = watcher.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 2 {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+}
+
+func TestRegistry_Health(t *testing.T) {
+	var (
+		reg = gregistry.NewHealthRegistry(memory.NewRegistry(),
+			gregistry.WithHealthInterval(20*time.Millisecond),
+			gregistry.WithHealthThreshold(2),
+		)
+		ctx = context.Background()
+	)
+	defer reg.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ins := &gregistry.ServiceInstance{
+		ID:       "test-3",
+		Name:     serviceName,
+		Kind:     gcluster.Node.String(),
+		State:    gcluster.Work.String(),
+		Endpoint: "grpc://" + ln.Addr().String(),
+	}
+
+	if err = reg.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher, err := reg.Watch(ctx, serviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	services, err := watcher.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].State != gcluster.Work.String() {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	_ = ln.Close()
+
+	if services, err = watcher.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].State != gcluster.Hang.String() {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	if services, err = reg.Services(ctx, serviceName); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].State != gcluster.Hang.String() {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gregistry"
+	"sync"
+	"sync/atomic"
+)
+
+type watcher struct {
+	idx        int64
+	state      int32
+	ctx        context.Context
+	cancel     context.CancelFunc
+	watcherMgr *watcherMgr
+	chWatch    chan []*gregistry.ServiceInstance
+}
+
+func newWatcher(wm *watcherMgr, idx int64) *watcher {
+	w := &watcher{}
+	w.ctx, w.cancel = context.WithCancel(wm.ctx)
+	w.idx = idx
+	w.watcherMgr = wm
+	w.chWatch = make(chan []*gregistry.ServiceInstance, 1)
+
+	return w
+}
+
+// 通知变更；服务实例列表为全量快照，未及时消费的旧快照将被新快照覆盖
+func (w *watcher) notify(services []*gregistry.ServiceInstance) {
+	if atomic.LoadInt32(&w.state) == 0 {
+		return
+	}
+
+	for {
+		select {
+		case w.chWatch <- services:
+			return
+		default:
+		}
+
+		select {
+		case <-w.chWatch:
+		default:
+		}
+	}
+}
+
+// Next 返回服务实例列表
+func (w *watcher) Next() ([]*gregistry.ServiceInstance, error) {
+	if atomic.LoadInt32(&w.state) == 0 {
+		atomic.StoreInt32(&w.state, 1)
+		return w.watcherMgr.services()
+	}
+
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case services := <-w.chWatch:
+		return services, nil
+	}
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.cancel()
+	return w.watcherMgr.recycle(w.idx)
+}
+
+type watcherMgr struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	registry    *Registry
+	serviceName string
+
+	idx      int64
+	rw       sync.RWMutex
+	watchers map[int64]*watcher
+}
+
+func newWatcherMgr(r *Registry, serviceName string) *watcherMgr {
+	wm := &watcherMgr{}
+	wm.ctx, wm.cancel = context.WithCancel(r.ctx)
+	wm.registry = r
+	wm.serviceName = serviceName
+	wm.watchers = make(map[int64]*watcher)
+
+	return wm
+}
+
+// 派生监听器；监听管理器已关闭时返回false
+func (wm *watcherMgr) fork() (gregistry.Watcher, bool) {
+	wm.rw.Lock()
+	defer wm.rw.Unlock()
+
+	if wm.ctx.Err() != nil {
+		return nil, false
+	}
+
+	w := newWatcher(wm, atomic.AddInt64(&wm.idx, 1))
+	wm.watchers[w.idx] = w
+
+	return w, true
+}
+
+func (wm *watcherMgr) recycle(idx int64) error {
+	wm.rw.Lock()
+	delete(wm.watchers, idx)
+	empty := len(wm.watchers) == 0
+	if empty {
+		wm.cancel()
+	}
+	wm.rw.Unlock()
+
+	if empty {
+		wm.registry.removeWatcherMgr(wm.serviceName, wm)
+	}
+
+	return nil
+}
+
+func (wm *watcherMgr) broadcast(services []*gregistry.ServiceInstance) {
+	wm.rw.RLock()
+	defer wm.rw.RUnlock()
+
+	for _, w := range wm.watchers {
+		w.notify(services)
+	}
+}
+
+func (wm *watcherMgr) services() ([]*gregistry.ServiceInstance, error) {
+	return wm.registry.Services(wm.ctx, wm.serviceName)
+}
@@ -240,6 +240,12 @@ func parseInstances(instances []model.Instance) ([]*gregistry.ServiceInstance, e
 			}
 		}
 
+		if v := instance.Metadata[metaFieldMetadata]; v != "" && v != "null" {
+			if err := json.Unmarshal([]byte(v), &ins.Metadata); err != nil {
+				return nil, err
+			}
+		}
+
 		services = append(services, ins)
 	}
 
@@ -0,0 +1,54 @@
+package gregistry
+
+import (
+	"github.com/goodluck0107/gcore/getc"
+	"time"
+)
+
+const (
+	defaultHealthInterval  = "5s"
+	defaultHealthTimeout   = "1s"
+	defaultHealthThreshold = 3
+)
+
+const (
+	defaultHealthIntervalKey  = "etc.registry.health.interval"
+	defaultHealthTimeoutKey   = "etc.registry.health.timeout"
+	defaultHealthThresholdKey = "etc.registry.health.threshold"
+)
+
+type HealthOption func(o *healthOptions)
+
+type healthOptions struct {
+	// 健康检查间隔，默认为5s
+	interval time.Duration
+
+	// 单次探测的超时时间，默认为1s
+	timeout time.Duration
+
+	// 连续探测失败多少次后降级实例，默认为3
+	threshold int
+}
+
+func defaultHealthOptions() *healthOptions {
+	return &healthOptions{
+		interval:  getc.Get(defaultHealthIntervalKey, defaultHealthInterval).Duration(),
+		timeout:   getc.Get(defaultHealthTimeoutKey, defaultHealthTimeout).Duration(),
+		threshold: getc.Get(defaultHealthThresholdKey, defaultHealthThreshold).Int(),
+	}
+}
+
+// WithHealthInterval 设置健康检查间隔
+func WithHealthInterval(interval time.Duration) HealthOption {
+	return func(o *healthOptions) { o.interval = interval }
+}
+
+// WithHealthTimeout 设置单次探测的超时时间
+func WithHealthTimeout(timeout time.Duration) HealthOption {
+	return func(o *healthOptions) { o.timeout = timeout }
+}
+
+// WithHealthThreshold 设置连续探测失败多少次后降级实例
+func WithHealthThreshold(threshold int) HealthOption {
+	return func(o *healthOptions) { o.threshold = threshold }
+}
@@ -52,6 +52,8 @@ type ServiceInstance struct {
 	Endpoint string `json:"endpoint,omitempty"`
 	// 微服务路由加权轮询权重
 	Weight int `json:"weight,omitempty"`
+	// 服务实例元数据，例如version、zone等，可用于发现时过滤实例
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 type Route struct {
@@ -0,0 +1,27 @@
+package grepo
+
+import (
+	"context"
+	"time"
+)
+
+// Document 玩家文档
+type Document struct {
+	UID       int64     // 用户ID
+	Version   int64     // 版本号；保存时作为期望的存储版本号，保存成功后存储版本号为Version+1
+	Data      []byte    // 文档数据
+	UpdatedAt time.Time // 更新时间
+}
+
+// Driver 存储驱动
+type Driver interface {
+	// Name 获取驱动名称
+	Name() string
+	// Load 加载玩家文档，文档不存在时返回nil
+	Load(ctx context.Context, uid int64) (*Document, error)
+	// Save 批量保存玩家文档，返回与docs一一对应的错误
+	// 存储中的版本号与文档的版本号不一致时，对应的错误为gerrors.ErrVersionConflict
+	Save(ctx context.Context, docs []*Document) []error
+	// Close 关闭驱动
+	Close() error
+}
@@ -0,0 +1,140 @@
+package file
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gencoding/json"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/grepo"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+const name = "file"
+
+// Driver 本地文件存储驱动
+// 每个玩家文档存放为一个文件，写入时先写临时文件再原子替换；版本校验仅在单进程内有效，适用于测试与单机部署
+type Driver struct {
+	opts *options
+	err  error
+	mu   sync.Mutex
+}
+
+func NewDriver(opts ...Option) *Driver {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	d := &Driver{}
+	d.opts = o
+	d.err = os.MkdirAll(o.dir, 0o755)
+
+	return d
+}
+
+// Name 获取驱动名称
+func (d *Driver) Name() string {
+	return name
+}
+
+// Load 加载玩家文档
+func (d *Driver) Load(_ context.Context, uid int64) (*grepo.Document, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.read(uid)
+}
+
+// Save 批量保存玩家文档
+func (d *Driver) Save(ctx context.Context, docs []*grepo.Document) []error {
+	errs := make([]error, len(docs))
+
+	if d.err != nil {
+		for i := range errs {
+			errs[i] = d.err
+		}
+		return errs
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		errs[i] = d.write(doc)
+	}
+
+	return errs
+}
+
+// Close 关闭驱动
+func (d *Driver) Close() error {
+	return nil
+}
+
+// 读取文档
+func (d *Driver) read(uid int64) (*grepo.Document, error) {
+	buf, err := os.ReadFile(d.path(uid))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	doc := &grepo.Document{}
+	if err = json.Unmarshal(buf, doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// 校验版本后写入文档
+func (d *Driver) write(doc *grepo.Document) error {
+	old, err := d.read(doc.UID)
+	if err != nil {
+		return err
+	}
+
+	var version int64
+	if old != nil {
+		version = old.Version
+	}
+
+	if version != doc.Version {
+		return gerrors.ErrVersionConflict
+	}
+
+	stored := *doc
+	stored.Version = doc.Version + 1
+
+	buf, err := json.Marshal(&stored)
+	if err != nil {
+		return err
+	}
+
+	path := d.path(doc.UID)
+	tmp := path + ".tmp"
+
+	if err = os.WriteFile(tmp, buf, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// 获取文档路径
+func (d *Driver) path(uid int64) string {
+	return filepath.Join(d.opts.dir, strconv.FormatInt(uid, 10)+".json")
+}
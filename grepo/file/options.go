@@ -0,0 +1,27 @@
+package file
+
+import (
+	"github.com/goodluck0107/gcore/getc"
+)
+
+const defaultDir = "./data/repo"
+
+const defaultDirKey = "etc.repo.file.dir"
+
+type Option func(o *options)
+
+type options struct {
+	// 存储目录，默认为./data/repo
+	dir string
+}
+
+func defaultOptions() *options {
+	return &options{
+		dir: getc.Get(defaultDirKey, defaultDir).String(),
+	}
+}
+
+// WithDir 设置存储目录
+func WithDir(dir string) Option {
+	return func(o *options) { o.dir = dir }
+}
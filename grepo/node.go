@@ -0,0 +1,31 @@
+package grepo
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gcluster"
+	"github.com/goodluck0107/gcore/gcluster/node"
+	"github.com/goodluck0107/gcore/glog"
+)
+
+// Attach 接入节点，节点销毁时完成全部脏文档的最终回写并关闭仓库
+// 节点关闭阶段仍会等待处理中的消息执行完毕，因此在其后的销毁阶段回写，避免关闭仓库后仍有消息修改文档
+func (r *Repository[T]) Attach(proxy *node.Proxy) {
+	proxy.AddHookListener(gcluster.Destroy, func(proxy *node.Proxy) {
+		if err := r.Close(context.Background()); err != nil {
+			glog.Errorf("repository close failed: %v", err)
+		}
+	})
+}
+
+// DisconnectHandler 包装断开连接事件处理器，处理完成后回写并卸载该用户的文档
+func (r *Repository[T]) DisconnectHandler(next node.EventHandler) node.EventHandler {
+	return func(ctx node.Context) {
+		if next != nil {
+			next(ctx)
+		}
+
+		if err := r.Unload(ctx.Context(), ctx.UID()); err != nil {
+			glog.Errorf("document unload failed, uid: %d err: %v", ctx.UID(), err)
+		}
+	}
+}
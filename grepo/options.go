@@ -0,0 +1,86 @@
+package grepo
+
+import (
+	"github.com/goodluck0107/gcore/gencoding"
+	"github.com/goodluck0107/gcore/getc"
+	"time"
+)
+
+const (
+	defaultCodec         = "json"
+	defaultFlushInterval = "5s"
+	defaultBatchSize     = 100
+	defaultMaxRetries    = 3
+	defaultRetryBackoff  = "1s"
+)
+
+const (
+	defaultCodecKey         = "etc.repo.codec"
+	defaultFlushIntervalKey = "etc.repo.flushInterval"
+	defaultBatchSizeKey     = "etc.repo.batchSize"
+	defaultMaxRetriesKey    = "etc.repo.maxRetries"
+	defaultRetryBackoffKey  = "etc.repo.retryBackoff"
+)
+
+type Option func(o *options)
+
+type options struct {
+	// 存储驱动
+	driver Driver
+
+	// 文档编解码器，默认为json
+	codec gencoding.Codec
+
+	// 回写间隔
+	// 脏文档的批量回写间隔，默认为5s，小于等于0时仅在卸载与关闭时回写
+	flushInterval time.Duration
+
+	// 单次批量回写的最大文档数，默认为100
+	batchSize int
+
+	// 关闭时最终回写的最大重试次数，默认为3次
+	maxRetries int
+
+	// 最终回写的重试间隔，默认为1s
+	retryBackoff time.Duration
+}
+
+func defaultOptions() *options {
+	return &options{
+		codec:         gencoding.Invoke(getc.Get(defaultCodecKey, defaultCodec).String()),
+		flushInterval: getc.Get(defaultFlushIntervalKey, defaultFlushInterval).Duration(),
+		batchSize:     getc.Get(defaultBatchSizeKey, defaultBatchSize).Int(),
+		maxRetries:    getc.Get(defaultMaxRetriesKey, defaultMaxRetries).Int(),
+		retryBackoff:  getc.Get(defaultRetryBackoffKey, defaultRetryBackoff).Duration(),
+	}
+}
+
+// WithDriver 设置存储驱动
+func WithDriver(driver Driver) Option {
+	return func(o *options) { o.driver = driver }
+}
+
+// WithCodec 设置文档编解码器
+func WithCodec(codec gencoding.Codec) Option {
+	return func(o *options) { o.codec = codec }
+}
+
+// WithFlushInterval 设置回写间隔
+func WithFlushInterval(flushInterval time.Duration) Option {
+	return func(o *options) { o.flushInterval = flushInterval }
+}
+
+// WithBatchSize 设置单次批量回写的最大文档数
+func WithBatchSize(batchSize int) Option {
+	return func(o *options) { o.batchSize = batchSize }
+}
+
+// WithMaxRetries 设置最终回写的最大重试次数
+func WithMaxRetries(maxRetries int) Option {
+	return func(o *options) { o.maxRetries = maxRetries }
+}
+
+// WithRetryBackoff 设置最终回写的重试间隔
+func WithRetryBackoff(retryBackoff time.Duration) Option {
+	return func(o *options) { o.retryBackoff = retryBackoff }
+}
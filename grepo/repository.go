@@ -0,0 +1,448 @@
+package grepo
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/glog"
+	"golang.org/x/sync/singleflight"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 文档缓存条目
+type entry[T any] struct {
+	mu       sync.Mutex
+	value    T
+	version  int64  // 存储版本号
+	revision uint64 // 修改序号，每次修改自增
+	flushed  uint64 // 已回写的修改序号
+	conflict bool   // 回写时发生版本冲突，未回写的修改已丢失
+	removed  bool   // 已从缓存中移除，持有该条目的访问者需重新加载
+}
+
+// 是否存在未回写的修改
+func (e *entry[T]) dirty() bool {
+	return e.revision != e.flushed
+}
+
+// Repository 玩家文档仓库
+// 首次访问时通过存储驱动加载文档并缓存，修改后标记为脏文档，由后台按回写间隔批量回写；
+// 回写时以加载时的版本号做乐观校验，避免多个节点相互覆盖；版本冲突的文档不再回写，
+// 其后对该文档的首次访问或卸载返回gerrors.ErrVersionConflict以告知调用方未回写的修改已丢失，再次访问时重新加载。
+// 缓存中的文档仅能在View与Update中加锁访问，Load与Get返回文档副本；文档类型T应为指针类型，以便在Update中原地修改
+type Repository[T any] struct {
+	opts   *options
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+	sfg    singleflight.Group
+
+	rw      sync.RWMutex
+	entries map[int64]*entry[T]
+
+	flushMu sync.Mutex
+}
+
+func NewRepository[T any](opts ...Option) *Repository[T] {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.driver == nil {
+		glog.Fatal("repository requires a storage driver")
+	}
+
+	r := &Repository[T]{}
+	r.opts = o
+	r.entries = make(map[int64]*entry[T])
+	r.done = make(chan struct{})
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	go r.loop()
+
+	return r
+}
+
+// Load 加载玩家文档副本；文档不存在时返回新建的空文档，修改副本不会影响缓存
+func (r *Repository[T]) Load(ctx context.Context, uid int64) (T, error) {
+	e, err := r.lock(ctx, uid)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer e.mu.Unlock()
+
+	return r.clone(e)
+}
+
+// Get 获取已缓存的玩家文档副本；文档未缓存或复制失败时返回false
+func (r *Repository[T]) Get(uid int64) (T, bool) {
+	r.rw.RLock()
+	e, ok := r.entries[uid]
+	r.rw.RUnlock()
+
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conflict || e.removed {
+		var zero T
+		return zero, false
+	}
+
+	v, err := r.clone(e)
+	if err != nil {
+		glog.Warnf("document clone failed, uid: %d err: %v", uid, err)
+		return v, false
+	}
+
+	return v, true
+}
+
+// View 只读访问玩家文档，fn执行期间持有文档锁，fn不应修改或在返回后继续引用文档；文档未缓存时先加载
+func (r *Repository[T]) View(ctx context.Context, uid int64, fn func(v T) error) error {
+	e, err := r.lock(ctx, uid)
+	if err != nil {
+		return err
+	}
+	defer e.mu.Unlock()
+
+	return fn(e.value)
+}
+
+// Update 修改玩家文档，fn执行期间持有文档锁，执行成功后文档被标记为脏文档；fn不应在返回后继续引用文档，文档未缓存时先加载
+func (r *Repository[T]) Update(ctx context.Context, uid int64, fn func(v T) error) error {
+	e, err := r.lock(ctx, uid)
+	if err != nil {
+		return err
+	}
+	defer e.mu.Unlock()
+
+	if err = fn(e.value); err != nil {
+		return err
+	}
+
+	e.revision++
+
+	return nil
+}
+
+// Flush 回写脏文档；未指定用户时回写全部脏文档，返回首个回写失败的错误
+func (r *Repository[T]) Flush(ctx context.Context, uids ...int64) error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	uids, entries := r.collect(uids)
+
+	batchSize := r.opts.batchSize
+	if batchSize <= 0 {
+		batchSize = len(entries)
+	}
+
+	var firstErr error
+	for start := 0; start < len(entries); start += batchSize {
+		end := min(start+batchSize, len(entries))
+
+		if err := r.flush(ctx, uids[start:end], entries[start:end]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Unload 回写并卸载玩家文档；回写失败时保留缓存，由后台继续重试
+// 回写期间文档被再次修改时同样保留缓存；文档发生版本冲突时卸载并返回gerrors.ErrVersionConflict
+func (r *Repository[T]) Unload(ctx context.Context, uid int64) error {
+	if err := r.Flush(ctx, uid); err != nil && !gerrors.Is(err, gerrors.ErrVersionConflict) {
+		return err
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	e, ok := r.entries[uid]
+	if !ok {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch {
+	case e.conflict:
+		r.remove(uid, e)
+		return gerrors.ErrVersionConflict
+	case !e.dirty():
+		r.remove(uid, e)
+	}
+
+	return nil
+}
+
+// Close 停止后台回写，并在重试次数内确保全部脏文档完成最终回写后关闭存储驱动
+func (r *Repository[T]) Close(ctx context.Context) (err error) {
+	r.once.Do(func() {
+		r.cancel()
+		<-r.done
+
+		for i := 0; ; i++ {
+			if err = r.Flush(ctx); err == nil || i >= r.opts.maxRetries || ctx.Err() != nil {
+				break
+			}
+
+			glog.Warnf("repository final flush failed, retry: %d err: %v", i+1, err)
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(r.opts.retryBackoff):
+			}
+		}
+
+		if e := r.opts.driver.Close(); e != nil && err == nil {
+			err = e
+		}
+	})
+
+	return
+}
+
+// 加载并锁定文档缓存条目
+// 条目在加锁前被卸载时重新加载，避免修改已移除的条目导致修改丢失；条目发生版本冲突时移除并返回gerrors.ErrVersionConflict
+func (r *Repository[T]) lock(ctx context.Context, uid int64) (*entry[T], error) {
+	for {
+		e, err := r.load(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+
+		e.mu.Lock()
+
+		if !e.removed && !e.conflict {
+			return e, nil
+		}
+
+		conflict := e.conflict && !e.removed
+		e.mu.Unlock()
+
+		if conflict && r.discard(uid, e) {
+			return nil, gerrors.ErrVersionConflict
+		}
+	}
+}
+
+// 移除版本冲突的条目，返回是否由本次调用移除
+func (r *Repository[T]) discard(uid int64, e *entry[T]) bool {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.removed {
+		return false
+	}
+
+	r.remove(uid, e)
+
+	return true
+}
+
+// 从缓存中移除条目，调用方需持有仓库写锁与条目锁
+func (r *Repository[T]) remove(uid int64, e *entry[T]) {
+	e.removed = true
+
+	if r.entries[uid] == e {
+		delete(r.entries, uid)
+	}
+}
+
+// 加载文档缓存条目
+func (r *Repository[T]) load(ctx context.Context, uid int64) (*entry[T], error) {
+	r.rw.RLock()
+	e, ok := r.entries[uid]
+	r.rw.RUnlock()
+
+	if ok {
+		return e, nil
+	}
+
+	v, err, _ := r.sfg.Do(strconv.FormatInt(uid, 10), func() (interface{}, error) {
+		doc, err := r.opts.driver.Load(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+
+		e := &entry[T]{value: newValue[T]()}
+
+		if doc != nil {
+			e.version = doc.Version
+
+			if err = r.decode(doc.Data, &e.value); err != nil {
+				return nil, err
+			}
+		}
+
+		r.rw.Lock()
+		defer r.rw.Unlock()
+
+		if old, ok := r.entries[uid]; ok {
+			return old, nil
+		}
+
+		r.entries[uid] = e
+
+		return e, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*entry[T]), nil
+}
+
+// 收集待回写的脏文档
+func (r *Repository[T]) collect(uids []int64) ([]int64, []*entry[T]) {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	if len(uids) == 0 {
+		uids = make([]int64, 0, len(r.entries))
+		for uid := range r.entries {
+			uids = append(uids, uid)
+		}
+	}
+
+	dirtyUIDs := make([]int64, 0, len(uids))
+	entries := make([]*entry[T], 0, len(uids))
+	for _, uid := range uids {
+		if e, ok := r.entries[uid]; ok {
+			e.mu.Lock()
+			dirty := e.dirty() && !e.conflict
+			e.mu.Unlock()
+
+			if dirty {
+				dirtyUIDs = append(dirtyUIDs, uid)
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	return dirtyUIDs, entries
+}
+
+// 批量回写一批脏文档
+func (r *Repository[T]) flush(ctx context.Context, uids []int64, entries []*entry[T]) error {
+	docs := make([]*Document, 0, len(entries))
+	revisions := make([]uint64, 0, len(entries))
+
+	for i, e := range entries {
+		e.mu.Lock()
+		data, err := r.opts.codec.Marshal(e.value)
+		doc := &Document{UID: uids[i], Version: e.version, Data: data, UpdatedAt: time.Now()}
+		revision := e.revision
+		e.mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+
+		docs = append(docs, doc)
+		revisions = append(revisions, revision)
+	}
+
+	var firstErr error
+	for i, err := range r.opts.driver.Save(ctx, docs) {
+		e := entries[i]
+
+		switch {
+		case err == nil:
+			e.mu.Lock()
+			e.version = docs[i].Version + 1
+			e.flushed = revisions[i]
+			e.mu.Unlock()
+		case gerrors.Is(err, gerrors.ErrVersionConflict):
+			// 文档已被其他节点修改，不再回写；下次访问时返回冲突错误并重新加载
+			glog.Errorf("document version conflict, uid: %d version: %d", uids[i], docs[i].Version)
+
+			e.mu.Lock()
+			e.conflict = true
+			e.mu.Unlock()
+		default:
+			glog.Warnf("document flush failed, uid: %d err: %v", uids[i], err)
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// 后台定时回写
+func (r *Repository[T]) loop() {
+	defer close(r.done)
+
+	if r.opts.flushInterval <= 0 {
+		<-r.ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(r.opts.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Flush(r.ctx); err != nil && r.ctx.Err() == nil {
+				glog.Warnf("repository flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// 复制缓存中的文档，调用方需持有条目锁
+func (r *Repository[T]) clone(e *entry[T]) (T, error) {
+	data, err := r.opts.codec.Marshal(e.value)
+
+	v := newValue[T]()
+	if err != nil {
+		return v, err
+	}
+
+	if err = r.decode(data, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// 解码文档数据
+func (r *Repository[T]) decode(data []byte, v *T) error {
+	if rv := reflect.ValueOf(*v); rv.Kind() == reflect.Ptr {
+		return r.opts.codec.Unmarshal(data, *v)
+	}
+
+	return r.opts.codec.Unmarshal(data, v)
+}
+
+// 新建空文档，指针类型分配内存
+func newValue[T any]() (v T) {
+	if rt := reflect.TypeOf(v); rt != nil && rt.Kind() == reflect.Ptr {
+		v = reflect.New(rt.Elem()).Interface().(T)
+	}
+
+	return
+}
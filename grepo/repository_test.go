@@ -0,0 +1,169 @@
+package grepo_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/grepo"
+	"github.com/goodluck0107/gcore/grepo/file"
+	"testing"
+)
+
+type player struct {
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+}
+
+func newRepository(dir string) *grepo.Repository[*player] {
+	return grepo.NewRepository[*player](
+		grepo.WithDriver(file.NewDriver(file.WithDir(dir))),
+		grepo.WithFlushInterval(0),
+	)
+}
+
+func TestRepository_Flush(t *testing.T) {
+	var (
+		ctx = context.Background()
+		dir = t.TempDir()
+		uid = int64(10001)
+	)
+
+	repo := newRepository(dir)
+
+	err := repo.Update(ctx, uid, func(p *player) error {
+		p.Name, p.Level = "fuxiao", 1
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 关闭时完成最终回写
+	if err = repo.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	repo = newRepository(dir)
+	defer repo.Close(ctx)
+
+	p, err := repo.Load(ctx, uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Name != "fuxiao" || p.Level != 1 {
+		t.Fatalf("unexpected player: %+v", p)
+	}
+}
+
+func TestRepository_VersionConflict(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		dir   = t.TempDir()
+		uid   = int64(10002)
+		repo1 = newRepository(dir)
+		repo2 = newRepository(dir)
+	)
+	defer repo1.Close(ctx)
+	defer repo2.Close(ctx)
+
+	for _, repo := range []*grepo.Repository[*player]{repo1, repo2} {
+		if _, err := repo.Load(ctx, uid); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	levelUp := func(p *player) error {
+		p.Level++
+		return nil
+	}
+
+	if err := repo2.Update(ctx, uid, levelUp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo2.Unload(ctx, uid); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo1.Update(ctx, uid, levelUp); err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他节点已回写，本节点基于旧版本的修改被拒绝
+	if err := repo1.Flush(ctx, uid); !gerrors.Is(err, gerrors.ErrVersionConflict) {
+		t.Fatalf("got %v, want version conflict", err)
+	}
+
+	if _, ok := repo1.Get(uid); ok {
+		t.Fatal("conflicted document still readable")
+	}
+
+	// 冲突后的首次访问告知调用方修改已丢失，再次访问时重新加载
+	if _, err := repo1.Load(ctx, uid); !gerrors.Is(err, gerrors.ErrVersionConflict) {
+		t.Fatalf("got %v, want version conflict", err)
+	}
+
+	p, err := repo1.Load(ctx, uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Level != 1 {
+		t.Fatalf("got level %d, want 1", p.Level)
+	}
+
+	if err = repo2.Update(ctx, uid, levelUp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repo1.Update(ctx, uid, levelUp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repo2.Flush(ctx, uid); err != nil {
+		t.Fatal(err)
+	}
+
+	// 卸载时发生冲突同样返回冲突错误
+	if err = repo1.Unload(ctx, uid); !gerrors.Is(err, gerrors.ErrVersionConflict) {
+		t.Fatalf("got %v, want version conflict", err)
+	}
+}
+
+func TestRepository_Copy(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		uid  = int64(10003)
+		repo = newRepository(t.TempDir())
+	)
+	defer repo.Close(ctx)
+
+	p, err := repo.Load(ctx, uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 修改副本不影响缓存中的文档
+	p.Level = 10
+
+	err = repo.View(ctx, uid, func(p *player) error {
+		if p.Level != 0 {
+			t.Fatalf("got level %d, want 0", p.Level)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repo.Update(ctx, uid, func(p *player) error {
+		p.Level = 1
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if p, ok := repo.Get(uid); !ok || p.Level != 1 {
+		t.Fatalf("unexpected player: %+v", p)
+	}
+}
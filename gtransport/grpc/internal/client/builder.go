@@ -2,6 +2,7 @@ package client
 
 import (
 	"github.com/goodluck0107/gcore/gregistry"
+	"github.com/goodluck0107/gcore/gtransport"
 	"github.com/goodluck0107/gcore/gtransport/grpc/internal/resolver/direct"
 	"github.com/goodluck0107/gcore/gtransport/grpc/internal/resolver/discovery"
 	"google.golang.org/grpc"
@@ -19,10 +20,11 @@ type Builder struct {
 }
 
 type Options struct {
-	CertFile   string
-	ServerName string
-	Discovery  gregistry.Discovery
-	DialOpts   []grpc.DialOption
+	CertFile     string
+	ServerName   string
+	Discovery    gregistry.Discovery
+	DialOpts     []grpc.DialOption
+	Interceptors []gtransport.ClientInterceptor
 }
 
 func NewBuilder(opts *Options) *Builder {
@@ -44,9 +46,14 @@ func NewBuilder(opts *Options) *Builder {
 		resolvers = append(resolvers, discovery.NewBuilder(opts.Discovery))
 	}
 
-	b.dialOpts = make([]grpc.DialOption, 0, len(opts.DialOpts)+2)
+	b.dialOpts = make([]grpc.DialOption, 0, len(opts.DialOpts)+3)
 	b.dialOpts = append(b.dialOpts, grpc.WithTransportCredentials(creds))
 	b.dialOpts = append(b.dialOpts, grpc.WithResolvers(resolvers...))
+	if interceptor := gtransport.ChainClientInterceptors(opts.Interceptors...); interceptor != nil {
+		b.dialOpts = append(b.dialOpts, grpc.WithChainUnaryInterceptor(unaryInterceptor(interceptor), codeInterceptor))
+	} else {
+		b.dialOpts = append(b.dialOpts, grpc.WithChainUnaryInterceptor(codeInterceptor))
+	}
 
 	return b
 }
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gtransport"
+	"github.com/goodluck0107/gcore/gtransport/grpc/internal/code"
+	"google.golang.org/grpc"
+	"strings"
+)
+
+const scheme = "grpc"
+
+// 将GRPC状态错误还原为携带错误码的错误
+func codeInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return code.FromStatus(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// 将传输层客户端拦截器适配为GRPC客户端拦截器
+func unaryInterceptor(interceptor gtransport.ClientInterceptor) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return interceptor(ctx, newCallInfo(method), req, reply, func(ctx context.Context, args, reply interface{}) error {
+			return invoker(ctx, method, args, reply, cc, opts...)
+		})
+	}
+}
+
+// 解析完整方法名；格式为 /package.Service/Method
+func newCallInfo(fullMethod string) *gtransport.CallInfo {
+	info := &gtransport.CallInfo{Scheme: scheme}
+
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		info.Service, info.Method = fullMethod[:i], fullMethod[i+1:]
+	} else {
+		info.Method = fullMethod
+	}
+
+	return info
+}
@@ -0,0 +1,86 @@
+package code
+
+import (
+	"github.com/goodluck0107/gcore/gcodes"
+	"github.com/goodluck0107/gcore/gtransport/internal/status"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+const (
+	reason = "GCORE_CODE"
+	domain = "gcore"
+)
+
+// ToStatus 将携带错误码的错误转换为GRPC状态错误
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	md := make(map[string]string)
+	if !status.Encode(err, md) {
+		return err
+	}
+
+	code, _ := gcodes.Convert(err)
+
+	st, e := grpcstatus.New(toGRPCCode(code), err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   domain,
+		Metadata: md,
+	})
+	if e != nil {
+		return err
+	}
+
+	return st.Err()
+}
+
+// FromStatus 将GRPC状态错误还原为携带错误码的错误
+func FromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := grpcstatus.FromError(err)
+	if !ok {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Reason != reason || info.Domain != domain {
+			continue
+		}
+
+		if e, ok := status.Decode(info.Metadata); ok {
+			return e
+		}
+	}
+
+	return err
+}
+
+// 将错误码映射为GRPC状态码；业务错误码统一映射为Unknown
+func toGRPCCode(code *gcodes.Code) codes.Code {
+	switch code.Code() {
+	case gcodes.Canceled.Code():
+		return codes.Canceled
+	case gcodes.InvalidArgument.Code(), gcodes.IllegalRequest.Code():
+		return codes.InvalidArgument
+	case gcodes.DeadlineExceeded.Code():
+		return codes.DeadlineExceeded
+	case gcodes.NotFound.Code():
+		return codes.NotFound
+	case gcodes.InternalError.Code():
+		return codes.Internal
+	case gcodes.Unauthorized.Code():
+		return codes.Unauthenticated
+	case gcodes.IllegalInvoke.Code():
+		return codes.FailedPrecondition
+	default:
+		return codes.Unknown
+	}
+}
@@ -3,8 +3,11 @@ package server
 import (
 	"context"
 	"github.com/goodluck0107/gcore/glog"
+	"github.com/goodluck0107/gcore/gtransport"
+	"github.com/goodluck0107/gcore/gtransport/grpc/internal/code"
 	"google.golang.org/grpc"
 	"runtime"
+	"strings"
 )
 
 func recoverInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
@@ -21,3 +24,67 @@ func recoverInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo
 
 	return handler(ctx, req)
 }
+
+// 将携带错误码的错误转换为GRPC状态错误
+func codeInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	reply, err := handler(ctx, req)
+
+	return reply, code.ToStatus(err)
+}
+
+// 将传输层一元拦截器适配为GRPC一元拦截器
+func unaryInterceptor(interceptor gtransport.ServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return interceptor(ctx, req, newCallInfo(info.FullMethod), gtransport.UnaryHandler(handler))
+	}
+}
+
+// 将传输层流拦截器适配为GRPC流拦截器
+func streamInterceptor(interceptor gtransport.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return interceptor(srv, ss, &gtransport.StreamInfo{
+			CallInfo:       *newCallInfo(info.FullMethod),
+			IsClientStream: info.IsClientStream,
+			IsServerStream: info.IsServerStream,
+		}, func(srv interface{}, stream gtransport.ServerStream) error {
+			if s, ok := stream.(grpc.ServerStream); ok {
+				return handler(srv, s)
+			}
+
+			return handler(srv, &serverStream{ServerStream: ss, stream: stream})
+		})
+	}
+}
+
+// 解析完整方法名；格式为 /package.Service/Method
+func newCallInfo(fullMethod string) *gtransport.CallInfo {
+	info := &gtransport.CallInfo{Scheme: scheme}
+
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		info.Service, info.Method = fullMethod[:i], fullMethod[i+1:]
+	} else {
+		info.Method = fullMethod
+	}
+
+	return info
+}
+
+// 拦截器替换后的服务端流
+type serverStream struct {
+	grpc.ServerStream
+	stream gtransport.ServerStream
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.stream.Context()
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	return s.stream.SendMsg(m)
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	return s.stream.RecvMsg(m)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"errors"
+	"github.com/goodluck0107/gcore/gtransport"
 	"github.com/goodluck0107/gcore/gwrap/endpoint"
 	xnet "github.com/goodluck0107/gcore/gwrap/net"
 	"google.golang.org/grpc"
@@ -19,10 +20,12 @@ type Server struct {
 }
 
 type Options struct {
-	Addr       string
-	KeyFile    string
-	CertFile   string
-	ServerOpts []grpc.ServerOption
+	Addr               string
+	KeyFile            string
+	CertFile           string
+	ServerOpts         []grpc.ServerOption
+	Interceptors       []gtransport.ServerInterceptor
+	StreamInterceptors []gtransport.StreamServerInterceptor
 }
 
 func NewServer(opts *Options) (*Server, error) {
@@ -32,9 +35,16 @@ func NewServer(opts *Options) (*Server, error) {
 	}
 
 	isSecure := false
-	serverOpts := make([]grpc.ServerOption, 0, len(opts.ServerOpts)+2)
+	serverOpts := make([]grpc.ServerOption, 0, len(opts.ServerOpts)+3)
 	serverOpts = append(serverOpts, opts.ServerOpts...)
-	serverOpts = append(serverOpts, grpc.UnaryInterceptor(recoverInterceptor))
+	if interceptor := gtransport.ChainServerInterceptors(opts.Interceptors...); interceptor != nil {
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(codeInterceptor, recoverInterceptor, unaryInterceptor(interceptor)))
+	} else {
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(codeInterceptor, recoverInterceptor))
+	}
+	if interceptor := gtransport.ChainStreamServerInterceptors(opts.StreamInterceptors...); interceptor != nil {
+		serverOpts = append(serverOpts, grpc.ChainStreamInterceptor(streamInterceptor(interceptor)))
+	}
 	if opts.CertFile != "" && opts.KeyFile != "" {
 		cred, err := credentials.NewServerTLSFromFile(opts.CertFile, opts.KeyFile)
 		if err != nil {
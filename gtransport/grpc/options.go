@@ -3,6 +3,7 @@ package grpc
 import (
 	"github.com/goodluck0107/gcore/getc"
 	"github.com/goodluck0107/gcore/gregistry"
+	"github.com/goodluck0107/gcore/gtransport"
 	"github.com/goodluck0107/gcore/gtransport/grpc/internal/client"
 	"github.com/goodluck0107/gcore/gtransport/grpc/internal/server"
 	"google.golang.org/grpc"
@@ -55,6 +56,16 @@ func WithServerOptions(opts ...grpc.ServerOption) Option {
 	return func(o *options) { o.server.ServerOpts = opts }
 }
 
+// WithServerInterceptors 设置服务器一元调用拦截器，先设置的拦截器处于外层
+func WithServerInterceptors(interceptors ...gtransport.ServerInterceptor) Option {
+	return func(o *options) { o.server.Interceptors = interceptors }
+}
+
+// WithServerStreamInterceptors 设置服务器流拦截器，先设置的拦截器处于外层
+func WithServerStreamInterceptors(interceptors ...gtransport.StreamServerInterceptor) Option {
+	return func(o *options) { o.server.StreamInterceptors = interceptors }
+}
+
 // WithClientCredentials 设置客户端证书和校验域名
 func WithClientCredentials(certFile string, serverName string) Option {
 	return func(o *options) { o.client.CertFile, o.client.ServerName = certFile, serverName }
@@ -69,3 +80,8 @@ func WithClientDiscovery(discovery gregistry.Discovery) Option {
 func WithClientDialOptions(opts ...grpc.DialOption) Option {
 	return func(o *options) { o.client.DialOpts = opts }
 }
+
+// WithClientInterceptors 设置客户端一元调用拦截器，先设置的拦截器处于外层
+func WithClientInterceptors(interceptors ...gtransport.ClientInterceptor) Option {
+	return func(o *options) { o.client.Interceptors = interceptors }
+}
@@ -0,0 +1,117 @@
+package gtransport
+
+import "context"
+
+// CallInfo 调用信息
+type CallInfo struct {
+	Scheme  string // 传输协议
+	Service string // 服务名称
+	Method  string // 方法名称
+}
+
+// StreamInfo 流调用信息
+type StreamInfo struct {
+	CallInfo
+	IsClientStream bool // 是否为客户端流
+	IsServerStream bool // 是否为服务端流
+}
+
+// UnaryHandler 服务端一元调用处理器
+type UnaryHandler func(ctx context.Context, req interface{}) (interface{}, error)
+
+// ServerInterceptor 服务端一元调用拦截器
+type ServerInterceptor func(ctx context.Context, req interface{}, info *CallInfo, handler UnaryHandler) (interface{}, error)
+
+// ServerStream 服务端流
+type ServerStream interface {
+	// Context 获取流上下文
+	Context() context.Context
+	// SendMsg 发送消息
+	SendMsg(m interface{}) error
+	// RecvMsg 接收消息
+	RecvMsg(m interface{}) error
+}
+
+// StreamHandler 服务端流处理器
+type StreamHandler func(srv interface{}, stream ServerStream) error
+
+// StreamServerInterceptor 服务端流拦截器
+type StreamServerInterceptor func(srv interface{}, stream ServerStream, info *StreamInfo, handler StreamHandler) error
+
+// UnaryInvoker 客户端一元调用器
+type UnaryInvoker func(ctx context.Context, args, reply interface{}) error
+
+// ClientInterceptor 客户端一元调用拦截器
+type ClientInterceptor func(ctx context.Context, info *CallInfo, args, reply interface{}, invoker UnaryInvoker) error
+
+// ChainServerInterceptors 串联服务端一元调用拦截器，先添加的拦截器处于外层
+func ChainServerInterceptors(interceptors ...ServerInterceptor) ServerInterceptor {
+	switch len(interceptors) {
+	case 0:
+		return nil
+	case 1:
+		return interceptors[0]
+	}
+
+	return func(ctx context.Context, req interface{}, info *CallInfo, handler UnaryHandler) (interface{}, error) {
+		return interceptors[0](ctx, req, info, chainUnaryHandler(interceptors, 0, info, handler))
+	}
+}
+
+func chainUnaryHandler(interceptors []ServerInterceptor, curr int, info *CallInfo, final UnaryHandler) UnaryHandler {
+	if curr == len(interceptors)-1 {
+		return final
+	}
+
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		return interceptors[curr+1](ctx, req, info, chainUnaryHandler(interceptors, curr+1, info, final))
+	}
+}
+
+// ChainStreamServerInterceptors 串联服务端流拦截器，先添加的拦截器处于外层
+func ChainStreamServerInterceptors(interceptors ...StreamServerInterceptor) StreamServerInterceptor {
+	switch len(interceptors) {
+	case 0:
+		return nil
+	case 1:
+		return interceptors[0]
+	}
+
+	return func(srv interface{}, stream ServerStream, info *StreamInfo, handler StreamHandler) error {
+		return interceptors[0](srv, stream, info, chainStreamHandler(interceptors, 0, info, handler))
+	}
+}
+
+func chainStreamHandler(interceptors []StreamServerInterceptor, curr int, info *StreamInfo, final StreamHandler) StreamHandler {
+	if curr == len(interceptors)-1 {
+		return final
+	}
+
+	return func(srv interface{}, stream ServerStream) error {
+		return interceptors[curr+1](srv, stream, info, chainStreamHandler(interceptors, curr+1, info, final))
+	}
+}
+
+// ChainClientInterceptors 串联客户端一元调用拦截器，先添加的拦截器处于外层
+func ChainClientInterceptors(interceptors ...ClientInterceptor) ClientInterceptor {
+	switch len(interceptors) {
+	case 0:
+		return nil
+	case 1:
+		return interceptors[0]
+	}
+
+	return func(ctx context.Context, info *CallInfo, args, reply interface{}, invoker UnaryInvoker) error {
+		return interceptors[0](ctx, info, args, reply, chainUnaryInvoker(interceptors, 0, info, invoker))
+	}
+}
+
+func chainUnaryInvoker(interceptors []ClientInterceptor, curr int, info *CallInfo, final UnaryInvoker) UnaryInvoker {
+	if curr == len(interceptors)-1 {
+		return final
+	}
+
+	return func(ctx context.Context, args, reply interface{}) error {
+		return interceptors[curr+1](ctx, info, args, reply, chainUnaryInvoker(interceptors, curr+1, info, final))
+	}
+}
@@ -0,0 +1,70 @@
+package gtransport_test
+
+import (
+	"context"
+	"github.com/goodluck0107/gcore/gtransport"
+	"reflect"
+	"testing"
+)
+
+func TestChainServerInterceptors(t *testing.T) {
+	var orders []string
+
+	interceptor := gtransport.ChainServerInterceptors(
+		func(ctx context.Context, req interface{}, info *gtransport.CallInfo, handler gtransport.UnaryHandler) (interface{}, error) {
+			orders = append(orders, "a")
+			return handler(ctx, req)
+		},
+		func(ctx context.Context, req interface{}, info *gtransport.CallInfo, handler gtransport.UnaryHandler) (interface{}, error) {
+			orders = append(orders, "b")
+			return handler(ctx, req)
+		},
+	)
+
+	info := &gtransport.CallInfo{Service: "greeter", Method: "Hello"}
+
+	reply, err := interceptor(context.Background(), "ping", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		orders = append(orders, "handler")
+		return req.(string) + "-pong", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reply != "ping-pong" {
+		t.Fatalf("unexpected reply: %v", reply)
+	}
+
+	if !reflect.DeepEqual(orders, []string{"a", "b", "handler"}) {
+		t.Fatalf("unexpected orders: %v", orders)
+	}
+}
+
+func TestChainClientInterceptors(t *testing.T) {
+	var orders []string
+
+	interceptor := gtransport.ChainClientInterceptors(
+		func(ctx context.Context, info *gtransport.CallInfo, args, reply interface{}, invoker gtransport.UnaryInvoker) error {
+			orders = append(orders, "a")
+			return invoker(ctx, args, reply)
+		},
+		func(ctx context.Context, info *gtransport.CallInfo, args, reply interface{}, invoker gtransport.UnaryInvoker) error {
+			orders = append(orders, "b")
+			return invoker(ctx, args, reply)
+		},
+	)
+
+	info := &gtransport.CallInfo{Service: "greeter", Method: "Hello"}
+
+	err := interceptor(context.Background(), info, nil, nil, func(ctx context.Context, args, reply interface{}) error {
+		orders = append(orders, "invoker")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(orders, []string{"a", "b", "invoker"}) {
+		t.Fatalf("unexpected orders: %v", orders)
+	}
+}
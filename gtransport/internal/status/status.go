@@ -0,0 +1,69 @@
+package status
+
+import (
+	"github.com/goodluck0107/gcore/gcodes"
+	"github.com/goodluck0107/gcore/gerrors"
+	"strconv"
+	"strings"
+)
+
+const (
+	codeKey     = "__gcore_code__"
+	messageKey  = "__gcore_message__"
+	redirectKey = "__gcore_redirect__"
+	textKey     = "__gcore_text__"
+)
+
+// Encode 将错误中携带的错误码编码到元数据中
+func Encode(err error, md map[string]string) bool {
+	if err == nil || md == nil {
+		return false
+	}
+
+	code, ok := gcodes.Convert(err)
+	if !ok || code == nil {
+		return false
+	}
+
+	md[codeKey] = strconv.Itoa(code.Code())
+	md[messageKey] = code.Message()
+
+	if redirect := code.Redirect(); redirect != "" {
+		md[redirectKey] = redirect
+	}
+
+	if text := strings.TrimPrefix(strings.TrimPrefix(err.Error(), code.String()), ": "); text != code.String() {
+		md[textKey] = text
+	}
+
+	return true
+}
+
+// Decode 从元数据中解码出携带错误码的错误
+func Decode(md map[string]string) (error, bool) {
+	if md == nil {
+		return nil, false
+	}
+
+	v, ok := md[codeKey]
+	if !ok {
+		return nil, false
+	}
+
+	c, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, false
+	}
+
+	code := gcodes.NewCode(c, md[messageKey])
+
+	if redirect := md[redirectKey]; redirect != "" {
+		code = code.WithRedirect(redirect)
+	}
+
+	if text := md[textKey]; text != "" {
+		return gerrors.NewError(code, text), true
+	}
+
+	return gerrors.NewError(code), true
+}
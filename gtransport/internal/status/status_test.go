@@ -0,0 +1,38 @@
+package status_test
+
+import (
+	"github.com/goodluck0107/gcore/gcodes"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gtransport/internal/status"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	src := gerrors.NewError("account exists", gcodes.NewCode(10, "register failed").WithRedirect("/login"))
+
+	md := make(map[string]string)
+	if !status.Encode(src, md) {
+		t.Fatal("encode failed")
+	}
+
+	dst, ok := status.Decode(md)
+	if !ok {
+		t.Fatal("decode failed")
+	}
+
+	if dst.Error() != src.Error() {
+		t.Fatalf("unexpected error text: %s", dst.Error())
+	}
+
+	code := gerrors.Code(dst)
+	if code == nil || code.Code() != 10 || code.Message() != "register failed" || code.Redirect() != "/login" {
+		t.Fatalf("unexpected code: %v", code)
+	}
+}
+
+func TestEncodeWithoutCode(t *testing.T) {
+	md := make(map[string]string)
+	if status.Encode(gerrors.New("plain error"), md) {
+		t.Fatal("plain error should not be encoded")
+	}
+}
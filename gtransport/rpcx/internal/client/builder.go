@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/gregistry"
+	"github.com/goodluck0107/gcore/gtransport"
 	"github.com/goodluck0107/gcore/gtransport/rpcx/internal/resolver"
 	"github.com/goodluck0107/gcore/gtransport/rpcx/internal/resolver/direct"
 	"github.com/goodluck0107/gcore/gtransport/rpcx/internal/resolver/discovery"
@@ -19,25 +20,29 @@ import (
 const defaultBuilder = "direct"
 
 type Builder struct {
-	err      error
-	opts     *Options
-	dialOpts cli.Option
-	pools    sync.Map
-	builders map[string]resolver.Builder
+	err         error
+	opts        *Options
+	dialOpts    cli.Option
+	pools       sync.Map
+	builders    map[string]resolver.Builder
+	interceptor gtransport.ClientInterceptor
 }
 
 type Options struct {
-	PoolSize   int
-	CertFile   string
-	ServerName string
-	Discovery  gregistry.Discovery
-	FailMode   cli.FailMode
+	PoolSize     int
+	CertFile     string
+	ServerName   string
+	Discovery    gregistry.Discovery
+	FailMode     cli.FailMode
+	Interceptors []gtransport.ClientInterceptor
 }
 
 func NewBuilder(opts *Options) *Builder {
 	b := &Builder{}
 	b.opts = opts
 	b.builders = make(map[string]resolver.Builder)
+	b.interceptor = gtransport.ChainClientInterceptors(opts.Interceptors...)
+
 	b.dialOpts = cli.DefaultOption
 	b.dialOpts.CompressType = proto.Gzip
 	b.RegisterBuilder(direct.NewBuilder(opts.Discovery))
@@ -66,6 +71,11 @@ func newClientTLSFromFile(certFile string, serverName string) (*tls.Config, erro
 	return &tls.Config{ServerName: serverName, RootCAs: cp}, nil
 }
 
+// Interceptor 获取客户端拦截器
+func (b *Builder) Interceptor() gtransport.ClientInterceptor {
+	return b.interceptor
+}
+
 // RegisterBuilder 注册构建器
 func (b *Builder) RegisterBuilder(builder resolver.Builder) {
 	b.builders[builder.Scheme()] = builder
@@ -2,20 +2,43 @@ package client
 
 import (
 	"context"
+	"github.com/goodluck0107/gcore/gtransport"
+	"github.com/goodluck0107/gcore/gtransport/rpcx/internal/code"
 	cli "github.com/smallnest/rpcx/client"
+	"github.com/smallnest/rpcx/share"
 )
 
+const scheme = "rpcx"
+
 type Client struct {
-	cli *cli.OneClient
+	cli         *cli.OneClient
+	interceptor gtransport.ClientInterceptor
 }
 
-func NewClient(cli *cli.OneClient) *Client {
-	return &Client{cli: cli}
+func NewClient(cli *cli.OneClient, interceptor gtransport.ClientInterceptor) *Client {
+	return &Client{cli: cli, interceptor: interceptor}
 }
 
 // Call 调用服务方法
 func (c *Client) Call(ctx context.Context, service, method string, args interface{}, reply interface{}, opts ...interface{}) error {
-	return c.cli.Call(ctx, service, method, args, reply)
+	if c.interceptor == nil {
+		return c.call(ctx, service, method, args, reply)
+	}
+
+	info := &gtransport.CallInfo{Scheme: scheme, Service: service, Method: method}
+
+	return c.interceptor(ctx, info, args, reply, func(ctx context.Context, args, reply interface{}) error {
+		return c.call(ctx, service, method, args, reply)
+	})
+}
+
+// 发起调用，并通过本次调用的响应元数据还原携带错误码的错误
+func (c *Client) call(ctx context.Context, service, method string, args interface{}, reply interface{}) error {
+	md := make(map[string]string)
+
+	err := c.cli.Call(context.WithValue(ctx, share.ResMetaDataKey, md), service, method, args, reply)
+
+	return code.FromError(err, md)
 }
 
 // Client 获取客户端
@@ -0,0 +1,31 @@
+package code
+
+import (
+	"github.com/goodluck0107/gcore/gtransport/internal/status"
+	"github.com/smallnest/rpcx/client"
+	"github.com/smallnest/rpcx/protocol"
+)
+
+// ServerErrorFunc 将携带错误码的错误编码到响应元数据中
+func ServerErrorFunc(res *protocol.Message, err error) string {
+	status.Encode(err, res.Metadata)
+
+	return err.Error()
+}
+
+// FromError 结合响应元数据还原携带错误码的错误
+func FromError(err error, md map[string]string) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(client.ServiceError); !ok {
+		return err
+	}
+
+	if e, ok := status.Decode(md); ok {
+		return e
+	}
+
+	return err
+}
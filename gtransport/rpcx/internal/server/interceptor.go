@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/goodluck0107/gcore/gcodes"
+	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gtransport"
+	"reflect"
+)
+
+var (
+	typeOfError   = reflect.TypeOf((*error)(nil)).Elem()
+	typeOfContext = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// 注册经拦截器包装的服务
+// RPCX未提供环绕式的调用钩子，故将服务的每个方法包装为函数后以函数服务的形式进行注册
+func (s *Server) registerInterceptedService(name string, ss interface{}) error {
+	v := reflect.ValueOf(ss)
+	t := v.Type()
+	n := 0
+
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		fn := v.Method(i)
+
+		if !isSuitableMethod(fn.Type()) {
+			continue
+		}
+
+		if err := s.server.RegisterFunctionName(name, method.Name, s.wrapMethod(name, method.Name, fn), ""); err != nil {
+			return err
+		}
+
+		n++
+	}
+
+	if n == 0 {
+		return gerrors.New("no suitable method in service")
+	}
+
+	return nil
+}
+
+// 包装服务方法
+func (s *Server) wrapMethod(service, method string, fn reflect.Value) reflect.Value {
+	info := &gtransport.CallInfo{Scheme: scheme, Service: service, Method: method}
+
+	return reflect.MakeFunc(fn.Type(), func(in []reflect.Value) []reflect.Value {
+		ctx, _ := in[0].Interface().(context.Context)
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		reply := in[2]
+
+		resp, err := s.interceptor(ctx, in[1].Interface(), info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			args := reflect.Zero(fn.Type().In(1))
+			if req != nil {
+				args = reflect.ValueOf(req)
+				if !args.Type().AssignableTo(fn.Type().In(1)) {
+					return nil, gerrors.NewError(gcodes.InvalidArgument, fmt.Sprintf("interceptor replaced request %s with %s", fn.Type().In(1), args.Type()))
+				}
+			}
+
+			out := fn.Call([]reflect.Value{reflect.ValueOf(ctx), args, reply})
+
+			if err, ok := out[0].Interface().(error); ok && err != nil {
+				return reply.Interface(), err
+			}
+
+			return reply.Interface(), nil
+		})
+
+		if resp != nil {
+			if rv := reflect.ValueOf(resp); rv.Type() == reply.Type() && rv.Pointer() != reply.Pointer() && !rv.IsNil() {
+				reply.Elem().Set(rv.Elem())
+			}
+		}
+
+		if err != nil {
+			return []reflect.Value{reflect.ValueOf(&err).Elem()}
+		}
+
+		return []reflect.Value{reflect.Zero(typeOfError)}
+	})
+}
+
+// 检测方法是否满足RPCX的函数签名；func(ctx context.Context, args *Args, reply *Reply) error
+func isSuitableMethod(t reflect.Type) bool {
+	if t.NumIn() != 3 || t.NumOut() != 1 {
+		return false
+	}
+
+	if !t.In(0).Implements(typeOfContext) {
+		return false
+	}
+
+	if t.In(2).Kind() != reflect.Ptr {
+		return false
+	}
+
+	return t.Out(0) == typeOfError
+}
@@ -3,6 +3,8 @@ package server
 import (
 	"crypto/tls"
 	"github.com/goodluck0107/gcore/gerrors"
+	"github.com/goodluck0107/gcore/gtransport"
+	"github.com/goodluck0107/gcore/gtransport/rpcx/internal/code"
 	"github.com/goodluck0107/gcore/gwrap/endpoint"
 	xnet "github.com/goodluck0107/gcore/gwrap/net"
 	"github.com/smallnest/rpcx/server"
@@ -15,14 +17,16 @@ type Server struct {
 	exposeAddr       string
 	server           *server.Server
 	endpoint         *endpoint.Endpoint
+	interceptor      gtransport.ServerInterceptor
 	disabledServices []string
 }
 
 type Options struct {
-	Addr       string
-	KeyFile    string
-	CertFile   string
-	ServerOpts []server.OptionFn
+	Addr         string
+	KeyFile      string
+	CertFile     string
+	ServerOpts   []server.OptionFn
+	Interceptors []gtransport.ServerInterceptor
 }
 
 func NewServer(opts *Options) (*Server, error) {
@@ -47,7 +51,11 @@ func NewServer(opts *Options) (*Server, error) {
 	s.listenAddr = listenAddr
 	s.exposeAddr = exposeAddr
 	s.server = server.NewServer(serverOpts...)
+	if s.server.ServerErrorFunc == nil {
+		s.server.ServerErrorFunc = code.ServerErrorFunc
+	}
 	s.endpoint = endpoint.NewEndpoint(scheme, exposeAddr, isSecure)
+	s.interceptor = gtransport.ChainServerInterceptors(opts.Interceptors...)
 
 	return s, nil
 }
@@ -84,5 +92,9 @@ func (s *Server) RegisterService(desc, ss interface{}) error {
 		return gerrors.New("invalid dispatcher desc")
 	}
 
+	if s.interceptor != nil {
+		return s.registerInterceptedService(name, ss)
+	}
+
 	return s.server.RegisterName(name, ss, "")
 }
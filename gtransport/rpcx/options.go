@@ -3,6 +3,7 @@ package rpcx
 import (
 	"github.com/goodluck0107/gcore/getc"
 	"github.com/goodluck0107/gcore/gregistry"
+	"github.com/goodluck0107/gcore/gtransport"
 	"github.com/goodluck0107/gcore/gtransport/rpcx/internal/client"
 	"github.com/goodluck0107/gcore/gtransport/rpcx/internal/server"
 )
@@ -50,6 +51,11 @@ func WithServerCredentials(certFile, keyFile string) Option {
 	return func(o *options) { o.server.KeyFile, o.server.CertFile = keyFile, certFile }
 }
 
+// WithServerInterceptors 设置服务器一元调用拦截器，先设置的拦截器处于外层
+func WithServerInterceptors(interceptors ...gtransport.ServerInterceptor) Option {
+	return func(o *options) { o.server.Interceptors = interceptors }
+}
+
 // WithClientPoolSize 设置客户端连接池大小
 func WithClientPoolSize(size int) Option {
 	return func(o *options) { o.client.PoolSize = size }
@@ -64,3 +70,8 @@ func WithClientCredentials(certFile string, serverName string) Option {
 func WithClientDiscovery(discovery gregistry.Discovery) Option {
 	return func(o *options) { o.client.Discovery = discovery }
 }
+
+// WithClientInterceptors 设置客户端一元调用拦截器，先设置的拦截器处于外层
+func WithClientInterceptors(interceptors ...gtransport.ClientInterceptor) Option {
+	return func(o *options) { o.client.Interceptors = interceptors }
+}
@@ -60,5 +60,5 @@ func (t *Transporter) NewClient(target string) (gtransport.Client, error) {
 		return nil, err
 	}
 
-	return client.NewClient(cli), nil
+	return client.NewClient(cli, t.builder.Interceptor()), nil
 }
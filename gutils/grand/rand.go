@@ -18,7 +18,36 @@ const (
 	SymbolSeed           = "!\\\"#$%&'()*+,-./:;<=>?@[\\\\]^_`{|}~"               // 特殊字符
 )
 
-var globalRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+var (
+	globalRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	global     = &globalGenerator{globalRand}
+)
+
+// 随机数生成器；由全局随机源与随机流实现，以便包函数与随机流共用同一套抽取逻辑
+type generator interface {
+	// 生成[0,n)范围间的整数
+	int63n(n int64) int64
+	// 生成[0,1)范围间的浮点数
+	float64() float64
+	// 打乱n个元素
+	shuffle(n int, swap func(i, j int))
+}
+
+type globalGenerator struct {
+	rand *rand.Rand
+}
+
+func (g *globalGenerator) int63n(n int64) int64 {
+	return g.rand.Int63n(n)
+}
+
+func (g *globalGenerator) float64() float64 {
+	return g.rand.Float64()
+}
+
+func (g *globalGenerator) shuffle(n int, swap func(i, j int)) {
+	g.rand.Shuffle(n, swap)
+}
 
 // Str 生成指定长度的字符串
 func Str(seed string, length int) (str string) {
@@ -66,45 +95,52 @@ func Symbols(length int) string {
 
 // Int 生成[min,max]的整数
 func Int(min, max int) int {
-	if min == max {
-		return min
-	}
-
-	if min > max {
-		min, max = max, min
-	}
-
-	return globalRand.Intn(max+1-min) + min
+	return int(randInt64(global, int64(min), int64(max)))
 }
 
 // Int32 生成[min,max]范围间的32位整数，
 func Int32(min, max int32) int32 {
-	if min == max {
-		return min
-	}
-
-	if min > max {
-		min, max = max, min
-	}
-
-	return globalRand.Int31n(max+1-min) + min
+	return int32(randInt64(global, int64(min), int64(max)))
 }
 
 // Int64 生成[min,max]范围间的64位整数
 func Int64(min, max int64) int64 {
-	if min == max {
-		return min
-	}
-
-	if min > max {
-		min, max = max, min
-	}
-
-	return globalRand.Int63n(max+1-min) + min
+	return randInt64(global, min, max)
 }
 
 // Float32 生成[min,max)范围间的32位浮点数
 func Float32(min, max float32) float32 {
+	return float32(randFloat64(global, float64(min), float64(max)))
+}
+
+// Float64 生成[min,max)范围间的64位浮点数
+func Float64(min, max float64) float64 {
+	return randFloat64(global, min, max)
+}
+
+// Lucky 根据概率抽取幸运值
+func Lucky(probability float64, base ...float64) bool {
+	return lucky(global, probability, base...)
+}
+
+// Weight 权重随机
+func Weight(fn func(v interface{}) float64, list ...interface{}) int {
+	return weight(global, fn, list...)
+}
+
+// Shuffle 打乱数组
+func Shuffle(list []interface{}) {
+	global.shuffle(len(list), func(i, j int) {
+		list[i], list[j] = list[j], list[i]
+	})
+}
+
+func Rand() *rand.Rand {
+	return globalRand
+}
+
+// 生成[min,max]范围间的整数
+func randInt64(g generator, min, max int64) int64 {
 	if min == max {
 		return min
 	}
@@ -113,11 +149,11 @@ func Float32(min, max float32) float32 {
 		min, max = max, min
 	}
 
-	return min + globalRand.Float32()*(max-min)
+	return g.int63n(max+1-min) + min
 }
 
-// Float64 生成[min,max)范围间的64位浮点数
-func Float64(min, max float64) float64 {
+// 生成[min,max)范围间的浮点数
+func randFloat64(g generator, min, max float64) float64 {
 	if min == max {
 		return min
 	}
@@ -126,11 +162,11 @@ func Float64(min, max float64) float64 {
 		min, max = max, min
 	}
 
-	return min + globalRand.Float64()*(max-min)
+	return min + g.float64()*(max-min)
 }
 
-// Lucky 根据概率抽取幸运值
-func Lucky(probability float64, base ...float64) bool {
+// 根据概率抽取幸运值
+func lucky(g generator, probability float64, base ...float64) bool {
 	if probability <= 0 {
 		return false
 	}
@@ -151,11 +187,11 @@ func Lucky(probability float64, base ...float64) bool {
 		scale = math.Pow10(len(str) - i - 1)
 	}
 
-	return Int64(1, int64(b*scale)) <= int64(probability*scale)
+	return randInt64(g, 1, int64(b*scale)) <= int64(probability*scale)
 }
 
-// Weight 权重随机
-func Weight(fn func(v interface{}) float64, list ...interface{}) int {
+// 权重随机
+func weight(g generator, fn func(v interface{}) float64, list ...interface{}) int {
 	if len(list) == 0 {
 		return -1
 	}
@@ -177,10 +213,10 @@ func Weight(fn func(v interface{}) float64, list ...interface{}) int {
 	sum := int64(total * scale)
 
 	if sum == 0 {
-		return Int(1, len(list))
+		return int(randInt64(g, 1, int64(len(list))))
 	}
 
-	weight := Int64(1, sum)
+	weight := randInt64(g, 1, sum)
 	acc := int64(0)
 
 	for i, item := range list {
@@ -190,16 +226,5 @@ func Weight(fn func(v interface{}) float64, list ...interface{}) int {
 		}
 	}
 
-	return Int(1, len(list))
-}
-
-// Shuffle 打乱数组
-func Shuffle(list []interface{}) {
-	globalRand.Shuffle(len(list), func(i, j int) {
-		list[i], list[j] = list[j], list[i]
-	})
-}
-
-func Rand() *rand.Rand {
-	return globalRand
+	return int(randInt64(g, 1, int64(len(list))))
 }
@@ -1,6 +1,7 @@
 package grand_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/goodluck0107/gcore/gutils/gconv"
 	"github.com/goodluck0107/gcore/gutils/grand"
@@ -52,3 +53,75 @@ func TestWeight(t *testing.T) {
 		fmt.Printf("index: %d, weight: %f, probability: %f\n", i, gconv.Float64(weights[i]), float64(num)/float64(total)*100)
 	}
 }
+
+func TestStream(t *testing.T) {
+	draw := func(s *grand.Stream) []int64 {
+		values := make([]int64, 0, 100)
+		for i := 0; i < 100; i++ {
+			values = append(values, s.Int64(1, 1000000))
+		}
+		return values
+	}
+
+	a, b := grand.NewStream("battle", 20240101), grand.NewStream("battle", 20240101)
+	if fmt.Sprint(draw(a)) != fmt.Sprint(draw(b)) {
+		t.Fatal("streams with same name and seed should be identical")
+	}
+
+	if fmt.Sprint(draw(grand.NewStream("loot", 20240101))) == fmt.Sprint(draw(grand.NewStream("battle", 20240101))) {
+		t.Fatal("streams with different names should be independent")
+	}
+
+	data, err := json.Marshal(a.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := draw(a)
+
+	state := &grand.StreamState{}
+	if err = json.Unmarshal(data, state); err != nil {
+		t.Fatal(err)
+	}
+
+	c := grand.NewStream("", 0)
+	if err = c.Restore(state); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(draw(c)) != fmt.Sprint(expected) || c.Draws() != a.Draws() {
+		t.Fatal("restored stream should continue the original sequence")
+	}
+
+	c.Reset()
+	if fmt.Sprint(draw(c)) != fmt.Sprint(draw(grand.NewStream("battle", 20240101))) {
+		t.Fatal("reset stream should replay from the seed")
+	}
+
+	list := []interface{}{1, 2, 3, 4, 5, 6, 7, 8}
+	grand.NewStream("shuffle", 1).Shuffle(list)
+	replay := []interface{}{1, 2, 3, 4, 5, 6, 7, 8}
+	grand.NewStream("shuffle", 1).Shuffle(replay)
+	if fmt.Sprint(list) != fmt.Sprint(replay) {
+		t.Fatal("shuffle should be reproducible")
+	}
+}
+
+func TestStreams(t *testing.T) {
+	battle := grand.NewStreams(42)
+	battle.Stream("damage").Int(1, 100)
+	battle.Stream("crit").Lucky(30)
+
+	states := battle.Snapshot()
+
+	replay := grand.NewStreams(42)
+	if err := replay.Restore(states); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"damage", "crit", "drop"} {
+		if battle.Stream(name).Float64(0, 1) != replay.Stream(name).Float64(0, 1) {
+			t.Fatalf("stream %s should be restored", name)
+		}
+	}
+}
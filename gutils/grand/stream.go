@@ -0,0 +1,226 @@
+package grand
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"math/rand/v2"
+	"sort"
+	"sync"
+)
+
+// Stream 可设置种子的确定性随机流
+// 相同名称与种子的随机流产生完全相同的随机序列，且状态可快照与恢复，可用于战斗回放与客户端模拟校验
+// 随机流基于PCG-DXSM算法，抽取逻辑不依赖Go版本；随机流非并发安全，应在单个协程（如Actor）中使用
+type Stream struct {
+	name  string
+	seed  uint64
+	pcg   *rand.PCG
+	draws uint64
+}
+
+// StreamState 随机流状态
+type StreamState struct {
+	Name  string `json:"name"`  // 随机流名称
+	Seed  uint64 `json:"seed"`  // 种子
+	Draws uint64 `json:"draws"` // 已抽取的64位随机数个数
+	State []byte `json:"state"` // PCG状态
+}
+
+// NewStream 新建随机流；相同种子不同名称的随机流互不相关
+func NewStream(name string, seed uint64) *Stream {
+	return &Stream{name: name, seed: seed, pcg: rand.NewPCG(seed, streamHash(name))}
+}
+
+// Name 获取随机流名称
+func (s *Stream) Name() string {
+	return s.name
+}
+
+// Seed 获取随机流种子
+func (s *Stream) Seed() uint64 {
+	return s.seed
+}
+
+// Draws 获取已抽取的64位随机数个数；可用于校验客户端模拟的抽取次数
+func (s *Stream) Draws() uint64 {
+	return s.draws
+}
+
+// Reset 重置随机流到初始状态
+func (s *Stream) Reset() {
+	s.pcg.Seed(s.seed, streamHash(s.name))
+	s.draws = 0
+}
+
+// Snapshot 快照随机流状态
+func (s *Stream) Snapshot() *StreamState {
+	state, _ := s.pcg.MarshalBinary()
+
+	return &StreamState{Name: s.name, Seed: s.seed, Draws: s.draws, State: state}
+}
+
+// Restore 恢复随机流状态
+func (s *Stream) Restore(state *StreamState) error {
+	pcg := &rand.PCG{}
+
+	if err := pcg.UnmarshalBinary(state.State); err != nil {
+		return err
+	}
+
+	s.name, s.seed, s.draws, s.pcg = state.Name, state.Seed, state.Draws, pcg
+
+	return nil
+}
+
+// Uint64 生成64位无符号整数
+func (s *Stream) Uint64() uint64 {
+	s.draws++
+	return s.pcg.Uint64()
+}
+
+// Int 生成[min,max]的整数
+func (s *Stream) Int(min, max int) int {
+	return int(randInt64(s, int64(min), int64(max)))
+}
+
+// Int32 生成[min,max]范围间的32位整数
+func (s *Stream) Int32(min, max int32) int32 {
+	return int32(randInt64(s, int64(min), int64(max)))
+}
+
+// Int64 生成[min,max]范围间的64位整数
+func (s *Stream) Int64(min, max int64) int64 {
+	return randInt64(s, min, max)
+}
+
+// Float32 生成[min,max)范围间的32位浮点数
+func (s *Stream) Float32(min, max float32) float32 {
+	return float32(randFloat64(s, float64(min), float64(max)))
+}
+
+// Float64 生成[min,max)范围间的64位浮点数
+func (s *Stream) Float64(min, max float64) float64 {
+	return randFloat64(s, min, max)
+}
+
+// Lucky 根据概率抽取幸运值
+func (s *Stream) Lucky(probability float64, base ...float64) bool {
+	return lucky(s, probability, base...)
+}
+
+// Weight 权重随机
+func (s *Stream) Weight(fn func(v interface{}) float64, list ...interface{}) int {
+	return weight(s, fn, list...)
+}
+
+// Shuffle 打乱数组
+func (s *Stream) Shuffle(list []interface{}) {
+	s.shuffle(len(list), func(i, j int) {
+		list[i], list[j] = list[j], list[i]
+	})
+}
+
+// 生成[0,n)范围间的整数；使用Lemire拒绝采样消除取模偏差
+func (s *Stream) int63n(n int64) int64 {
+	if n <= 0 {
+		panic("invalid argument to int63n")
+	}
+
+	hi, lo := bits.Mul64(s.Uint64(), uint64(n))
+	if lo < uint64(n) {
+		threshold := -uint64(n) % uint64(n)
+		for lo < threshold {
+			hi, lo = bits.Mul64(s.Uint64(), uint64(n))
+		}
+	}
+
+	return int64(hi)
+}
+
+// 生成[0,1)范围间的浮点数
+func (s *Stream) float64() float64 {
+	return float64(s.Uint64()>>11) / (1 << 53)
+}
+
+// 打乱n个元素；Fisher-Yates洗牌
+func (s *Stream) shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, int(s.int63n(int64(i+1))))
+	}
+}
+
+// Streams 随机流集合；同一种子派生出多个具名随机流，如战斗中的伤害、掉落等相互独立的随机序列
+type Streams struct {
+	mu      sync.Mutex
+	seed    uint64
+	streams map[string]*Stream
+}
+
+// NewStreams 新建随机流集合
+func NewStreams(seed uint64) *Streams {
+	return &Streams{seed: seed, streams: make(map[string]*Stream)}
+}
+
+// Seed 获取种子
+func (s *Streams) Seed() uint64 {
+	return s.seed
+}
+
+// Stream 获取具名随机流；不存在时以集合种子创建
+func (s *Streams) Stream(name string) *Stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream, ok := s.streams[name]
+	if !ok {
+		stream = NewStream(name, s.seed)
+		s.streams[name] = stream
+	}
+
+	return stream
+}
+
+// Snapshot 快照所有随机流状态；按名称排序
+func (s *Streams) Snapshot() []*StreamState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]*StreamState, 0, len(s.streams))
+	for _, stream := range s.streams {
+		states = append(states, stream.Snapshot())
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+
+	return states
+}
+
+// Restore 恢复随机流状态；快照中不存在的随机流将被重置
+func (s *Streams) Restore(states []*StreamState) error {
+	streams := make(map[string]*Stream, len(states))
+
+	for _, state := range states {
+		stream := &Stream{}
+
+		if err := stream.Restore(state); err != nil {
+			return err
+		}
+
+		streams[state.Name] = stream
+	}
+
+	s.mu.Lock()
+	s.streams = streams
+	s.mu.Unlock()
+
+	return nil
+}
+
+// 计算随机流名称的哈希值
+func streamHash(name string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return h.Sum64()
+}
@@ -0,0 +1,493 @@
+package gvalidate
+
+import (
+	"fmt"
+	"github.com/goodluck0107/gcore/gcodes"
+	"github.com/goodluck0107/gcore/gerrors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const tagName = "validate"
+
+// Validator 自定义校验器；结构体标签校验通过后调用
+type Validator interface {
+	Validate() error
+}
+
+// FieldError 字段校验错误
+type FieldError struct {
+	Field string `json:"field"`           // 字段路径，如 Server.Ports[0]
+	Rule  string `json:"rule"`            // 校验规则
+	Param string `json:"param,omitempty"` // 规则参数
+}
+
+func (e *FieldError) Error() string {
+	if e.Param == "" {
+		return fmt.Sprintf("field %s failed on the %s rule", e.Field, e.Rule)
+	}
+
+	return fmt.Sprintf("field %s failed on the %s=%s rule", e.Field, e.Rule, e.Param)
+}
+
+// FieldErrors 字段校验错误列表
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	texts := make([]string, 0, len(e))
+	for _, fe := range e {
+		texts = append(texts, fe.Error())
+	}
+
+	return strings.Join(texts, "; ")
+}
+
+type rule struct {
+	name  string
+	param string
+	num   float64        // min、max、len 规则的数值参数
+	re    *regexp.Regexp // regexp 规则编译后的正则表达式
+}
+
+type field struct {
+	index int
+	name  string
+	rules []rule
+}
+
+type fieldsEntry struct {
+	fields []*field
+	err    error
+}
+
+// 已知的校验规则；值表示规则是否须携带参数
+var knownRules = map[string]bool{
+	"required":  false,
+	"omitempty": false,
+	"min":       true,
+	"max":       true,
+	"len":       true,
+	"oneof":     true,
+	"email":     false,
+	"url":       false,
+	"mobile":    false,
+	"telephone": false,
+	"qq":        false,
+	"idcard":    false,
+	"number":    false,
+	"regexp":    true,
+}
+
+var (
+	fieldsCache sync.Map // reflect.Type -> *fieldsEntry
+	rulesCache  sync.Map // reflect.Type -> map[string]string
+)
+
+// RegisterRules 为结构体注册字段校验规则；key 为字段名，value 与 validate 标签格式一致
+// 用于无法添加结构体标签的类型，如由 protoc-gen-idmsg 根据 (extend.validate) 字段选项生成的 protobuf 消息；字段已有标签时以标签为准
+func RegisterRules(v interface{}, rules map[string]string) {
+	typ := reflect.TypeOf(v)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	rulesCache.Store(typ, rules)
+	fieldsCache.Delete(typ)
+}
+
+// Struct 按 validate 标签校验结构体，嵌套的结构体、结构体指针及其切片会被递归校验
+// 支持的规则：required、omitempty、min、max、len、oneof、email、url、mobile、telephone、qq、idcard、number、regexp
+// 多个规则以逗号分隔，regexp 规则须位于最后；如 `validate:"required,min=1,max=10"`
+// 校验失败时返回第一个 *FieldError；标签中含有未知规则或非法参数时，在首次解析该类型时返回 gerrors.ErrInvalidValidateRule
+func Struct(v interface{}) error {
+	return (&validation{}).validate(v)
+}
+
+// StructAll 按 validate 标签校验结构体，规则同 Struct
+// 与 Struct 不同的是，字段校验失败时继续校验其余字段，并以 FieldErrors 返回所有字段的校验错误
+func StructAll(v interface{}) error {
+	vd := &validation{all: true}
+
+	if err := vd.validate(v); err != nil {
+		return err
+	}
+
+	if len(vd.errs) > 0 {
+		return vd.errs
+	}
+
+	return nil
+}
+
+// Request 校验请求参数，规则同 Struct
+// 校验失败时返回携带 gcodes.InvalidArgument 错误码的错误，错误码消息为各字段的校验错误，可通过 errors.As 获取 FieldErrors
+func Request(v interface{}) error {
+	err := StructAll(v)
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := gcodes.Convert(err); ok || gerrors.Is(err, gerrors.ErrInvalidValidateRule) {
+		return err
+	}
+
+	return gerrors.NewError(gcodes.InvalidArgument.WithMessage(err.Error()), err)
+}
+
+type validation struct {
+	all     bool               // 是否收集所有字段的校验错误
+	errs    FieldErrors        // 字段校验错误
+	visited map[visit]struct{} // 已校验的引用；避免循环引用导致无限递归
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// 校验
+func (vd *validation) validate(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return vd.validateStruct(rv, "")
+}
+
+// 校验结构体
+func (vd *validation) validateStruct(rv reflect.Value, path string) error {
+	failed := len(vd.errs)
+
+	fields, err := parseFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		name := f.name
+		if path != "" {
+			name = path + "." + f.name
+		}
+
+		if fe := validateField(fv, name, f.rules); fe != nil {
+			if !vd.all {
+				return fe
+			}
+
+			vd.errs = append(vd.errs, fe)
+			continue
+		}
+
+		if err := vd.validateNested(fv, name); err != nil {
+			return err
+		}
+	}
+
+	// 字段校验失败时不再调用自定义校验器
+	if len(vd.errs) > failed {
+		return nil
+	}
+
+	if rv.CanAddr() {
+		if validator, ok := rv.Addr().Interface().(Validator); ok {
+			return validator.Validate()
+		}
+	}
+
+	if validator, ok := rv.Interface().(Validator); ok {
+		return validator.Validate()
+	}
+
+	return nil
+}
+
+// 递归校验嵌套结构；同一引用仅校验一次
+func (vd *validation) validateNested(fv reflect.Value, path string) error {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+
+		if fv.Kind() == reflect.Ptr && !vd.visit(fv) {
+			return nil
+		}
+
+		fv = fv.Elem()
+	}
+
+	switch fv.Kind() {
+	case reflect.Struct:
+		return vd.validateStruct(fv, path)
+	case reflect.Slice:
+		if fv.IsNil() || !vd.visit(fv) {
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			if err := vd.validateNested(fv.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if fv.IsNil() || !vd.visit(fv) {
+			return nil
+		}
+
+		iter := fv.MapRange()
+		for iter.Next() {
+			if err := vd.validateNested(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key().Interface())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// 记录已校验的引用；引用已校验过时返回false
+func (vd *validation) visit(v reflect.Value) bool {
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+
+	if vd.visited == nil {
+		vd.visited = make(map[visit]struct{})
+	}
+
+	if _, ok := vd.visited[key]; ok {
+		return false
+	}
+
+	vd.visited[key] = struct{}{}
+
+	return true
+}
+
+// 校验字段
+func validateField(fv reflect.Value, name string, rules []rule) *FieldError {
+	for _, r := range rules {
+		switch r.name {
+		case "omitempty":
+			if fv.IsZero() {
+				return nil
+			}
+			continue
+		case "required":
+			if fv.IsZero() {
+				return &FieldError{Field: name, Rule: r.name}
+			}
+			continue
+		}
+
+		v := fv
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if v.Kind() == reflect.Ptr {
+			continue
+		}
+
+		if !check(v, r) {
+			return &FieldError{Field: name, Rule: r.name, Param: r.param}
+		}
+	}
+
+	return nil
+}
+
+// 执行规则校验
+func check(v reflect.Value, r rule) bool {
+	switch r.name {
+	case "min", "max", "len":
+		n, ok := measure(v)
+		if !ok {
+			return false
+		}
+
+		switch r.name {
+		case "min":
+			return n >= r.num
+		case "max":
+			return n <= r.num
+		default:
+			return n == r.num
+		}
+	case "oneof":
+		s := fmt.Sprint(v.Interface())
+		for _, item := range strings.Fields(r.param) {
+			if item == s {
+				return true
+			}
+		}
+		return false
+	case "regexp":
+		return v.Kind() == reflect.String && r.re.MatchString(v.String())
+	}
+
+	if v.Kind() != reflect.String {
+		return false
+	}
+
+	s := v.String()
+
+	switch r.name {
+	case "email":
+		return IsEmail(s)
+	case "url":
+		return IsUrl(s)
+	case "mobile":
+		return IsMobile(s)
+	case "telephone":
+		return IsTelephone(s)
+	case "qq":
+		return IsQQ(s)
+	case "idcard":
+		return IsIdCard(s)
+	case "number":
+		return IsNumber(s)
+	default:
+		return false
+	}
+}
+
+// 度量字段值；数值取其值，字符串取字符数，集合取长度
+func measure(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true
+	default:
+		return 0, false
+	}
+}
+
+// 解析结构体字段规则；解析结果连同错误一并缓存，未知规则或非法参数仅在首次解析时检查
+func parseFields(typ reflect.Type) ([]*field, error) {
+	if entry, ok := fieldsCache.Load(typ); ok {
+		return entry.(*fieldsEntry).fields, entry.(*fieldsEntry).err
+	}
+
+	var registered map[string]string
+	if rules, ok := rulesCache.Load(typ); ok {
+		registered = rules.(map[string]string)
+	}
+
+	entry := &fieldsEntry{fields: make([]*field, 0, typ.NumField())}
+
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup(tagName)
+		if !ok {
+			tag = registered[sf.Name]
+		}
+		if tag == "-" {
+			continue
+		}
+
+		rules, err := parseRules(tag)
+		if err != nil {
+			entry.fields, entry.err = nil, gerrors.NewError(fmt.Sprintf("field %s.%s: %v", typ.String(), sf.Name, err), gerrors.ErrInvalidValidateRule)
+			break
+		}
+
+		entry.fields = append(entry.fields, &field{index: i, name: sf.Name, rules: rules})
+	}
+
+	fieldsCache.Store(typ, entry)
+
+	return entry.fields, entry.err
+}
+
+// 解析规则标签
+func parseRules(tag string) ([]rule, error) {
+	rules := make([]rule, 0)
+
+	for tag != "" {
+		var item string
+
+		if strings.HasPrefix(tag, "regexp=") {
+			item, tag = tag, ""
+		} else if i := strings.IndexByte(tag, ','); i >= 0 {
+			item, tag = tag[:i], tag[i+1:]
+		} else {
+			item, tag = tag, ""
+		}
+
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, param, _ := strings.Cut(item, "=")
+
+		r, err := newRule(name, param)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+// 新建规则并预处理参数
+func newRule(name, param string) (rule, error) {
+	r := rule{name: name, param: param}
+
+	withParam, ok := knownRules[name]
+	if !ok {
+		return r, fmt.Errorf("unknown rule %s", name)
+	}
+
+	if withParam && param == "" {
+		return r, fmt.Errorf("rule %s requires a parameter", name)
+	}
+
+	if !withParam && param != "" {
+		return r, fmt.Errorf("rule %s does not take a parameter", name)
+	}
+
+	var err error
+
+	switch name {
+	case "min", "max", "len":
+		if r.num, err = strconv.ParseFloat(param, 64); err != nil {
+			return r, fmt.Errorf("rule %s has invalid number %s", name, param)
+		}
+	case "regexp":
+		if r.re, err = regexp.Compile(param); err != nil {
+			return r, fmt.Errorf("rule %s has invalid expression: %v", name, err)
+		}
+	}
+
+	return r, nil
+}
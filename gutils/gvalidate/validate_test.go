@@ -1,7 +1,11 @@
 package gvalidate_test
 
 import (
+	"errors"
+	"github.com/goodluck0107/gcore/gcodes"
+	"github.com/goodluck0107/gcore/gerrors"
 	"github.com/goodluck0107/gcore/gutils/gvalidate"
+	"strings"
 	"testing"
 )
 
@@ -62,3 +66,140 @@ func TestIn(t *testing.T) {
 func TestIsIdCard(t *testing.T) {
 	t.Log(gvalidate.IsIdCard("512301195011260279"))
 }
+
+type address struct {
+	City string `validate:"required"`
+}
+
+type profile struct {
+	Name      string    `validate:"required,min=2,max=8"`
+	Email     string    `validate:"omitempty,email"`
+	Level     int       `validate:"min=1,max=99"`
+	Gender    string    `validate:"oneof=male female"`
+	Code      string    `validate:"omitempty,regexp=^[a-z]{2},[0-9]+$"`
+	Addresses []address `validate:"min=1"`
+	Backup    *address
+}
+
+func TestStruct(t *testing.T) {
+	p := &profile{
+		Name:      "alice",
+		Level:     10,
+		Gender:    "female",
+		Code:      "ab,12",
+		Addresses: []address{{City: "chengdu"}},
+	}
+
+	if err := gvalidate.Struct(p); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]func(p profile) profile{
+		"Name":         func(p profile) profile { p.Name = ""; return p },
+		"Email":        func(p profile) profile { p.Email = "alice"; return p },
+		"Level":        func(p profile) profile { p.Level = 100; return p },
+		"Gender":       func(p profile) profile { p.Gender = "unknown"; return p },
+		"Code":         func(p profile) profile { p.Code = "ab12"; return p },
+		"Addresses":    func(p profile) profile { p.Addresses = nil; return p },
+		"Addresses[0]": func(p profile) profile { p.Addresses = []address{{}}; return p },
+		"Backup":       func(p profile) profile { p.Backup = &address{}; return p },
+	}
+
+	for field, fn := range cases {
+		invalid := fn(*p)
+
+		err := gvalidate.Struct(&invalid)
+		if err == nil {
+			t.Fatalf("field %s is expected to be invalid", field)
+		}
+
+		if fe, ok := err.(*gvalidate.FieldError); !ok || !strings.HasPrefix(fe.Field, field) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestStructAll(t *testing.T) {
+	p := &profile{Level: 100, Gender: "female", Addresses: []address{{}}}
+
+	err := gvalidate.StructAll(p)
+
+	var errs gvalidate.FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fields := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, fe.Field)
+	}
+
+	if strings.Join(fields, ",") != "Name,Level,Addresses[0].City" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+
+	code, ok := gcodes.Convert(gvalidate.Request(p))
+	if !ok || code.Code() != gcodes.InvalidArgument.Code() || code.Message() != errs.Error() {
+		t.Fatalf("unexpected code: %v", code)
+	}
+}
+
+// 模拟无法添加标签的protobuf消息
+type loginReq struct {
+	Account string
+	Email   string
+}
+
+func TestRegisterRules(t *testing.T) {
+	gvalidate.RegisterRules(&loginReq{}, map[string]string{
+		"Account": "required,min=4,max=16",
+		"Email":   "omitempty,email",
+	})
+
+	if err := gvalidate.Request(&loginReq{Account: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gvalidate.Request(&loginReq{Account: "bob", Email: "bob"}); err == nil {
+		t.Fatal("expected validation error")
+	}
+}
+
+type unknownRuleReq struct {
+	Level int `validate:"gte=1"`
+}
+
+func TestStruct_UnknownRule(t *testing.T) {
+	err := gvalidate.Struct(&unknownRuleReq{Level: 1})
+	if !errors.Is(err, gerrors.ErrInvalidValidateRule) || !strings.Contains(err.Error(), "unknown rule gte") {
+		t.Fatalf("expected unknown rule error, got %v", err)
+	}
+
+	// 规则定义错误并非参数错误，不应转换为 gcodes.InvalidArgument
+	if err = gvalidate.Request(&unknownRuleReq{Level: 1}); !errors.Is(err, gerrors.ErrInvalidValidateRule) {
+		t.Fatalf("expected unknown rule error, got %v", err)
+	}
+}
+
+type treeNode struct {
+	Name     string      `validate:"required"`
+	Parent   *treeNode   `validate:"-"`
+	Children []*treeNode `validate:"max=10"`
+}
+
+func TestStruct_Cycle(t *testing.T) {
+	root := &treeNode{Name: "root"}
+	child := &treeNode{Name: "child", Parent: root}
+	root.Children = append(root.Children, child, root)
+
+	if err := gvalidate.Struct(root); err != nil {
+		t.Fatal(err)
+	}
+
+	child.Name = ""
+
+	var fe *gvalidate.FieldError
+	if err := gvalidate.Struct(root); !errors.As(err, &fe) || fe.Field != "Children[0].Name" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -42,6 +42,19 @@ func (d *Dispatcher) FindEndpoint(insID string) (*endpoint.Endpoint, error) {
 	return ep, nil
 }
 
+// FindInstance 查找服务实例
+func (d *Dispatcher) FindInstance(insID string) (*gregistry.ServiceInstance, error) {
+	d.rw.RLock()
+	defer d.rw.RUnlock()
+
+	ins, ok := d.instances[insID]
+	if !ok {
+		return nil, gerrors.ErrNotFoundEndpoint
+	}
+
+	return ins, nil
+}
+
 // IterateEndpoint 迭代服务端口
 func (d *Dispatcher) IterateEndpoint(fn func(insID string, ep *endpoint.Endpoint) bool) {
 	d.rw.RLock()
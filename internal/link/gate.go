@@ -567,6 +567,10 @@ func (l *GateLinker) WatchUserLocate() {
 
 			events, err := watcher.Next()
 			if err != nil {
+				// 定位事件存在缺失，清空用户源，后续定位时重新加载
+				if gerrors.Is(err, gerrors.ErrResyncRequired) {
+					l.sources.Clear()
+				}
 				continue
 			}
 
@@ -67,6 +67,12 @@ func (l *NodeLinker) Has(nid string) bool {
 	return err == nil
 }
 
+// Instance 获取已同步的节点实例
+func (l *NodeLinker) Instance(nid string) (*gregistry.ServiceInstance, bool) {
+	ins, err := l.dispatcher.FindInstance(nid)
+	return ins, err == nil
+}
+
 // Locate 定位用户所在节点
 func (l *NodeLinker) Locate(ctx context.Context, uid int64, name string) (string, error) {
 	if l.opts.Locator == nil {
@@ -354,6 +360,13 @@ func (l *NodeLinker) doSaveSource(uid int64, name, nid string) {
 	sources[name] = nid
 }
 
+// 清空用户节点来源
+func (l *NodeLinker) doClearSources() {
+	l.rw.Lock()
+	l.sources = make(map[int64]map[string]string)
+	l.rw.Unlock()
+}
+
 // 删除用户节点来源
 func (l *NodeLinker) doDeleteSource(uid int64, name, nid string) {
 	l.rw.Lock()
@@ -420,6 +433,10 @@ func (l *NodeLinker) WatchUserLocate() {
 
 			events, err := watcher.Next()
 			if err != nil {
+				// 定位事件存在缺失，清空用户源，后续定位时重新加载
+				if gerrors.Is(err, gerrors.ErrResyncRequired) {
+					l.doClearSources()
+				}
 				continue
 			}
 